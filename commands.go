@@ -0,0 +1,821 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/daily"
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/play"
+	"github.com/chytilp/sudoku/render"
+	"github.com/chytilp/sudoku/repl"
+	"github.com/chytilp/sudoku/server"
+	"github.com/chytilp/sudoku/session"
+	"github.com/chytilp/sudoku/store"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Default values of subcommand flags.
+const (
+	defaultCountLimit int    = 1000
+	defaultGenerated  int    = 1
+	defaultAddr       string = ":8080"
+	defaultDB         string = "puzzles.db"
+)
+
+//Errors for subcommands.
+var (
+	ErrOutputFormatRequired error = errors.New("Output format must be set by --output flag")
+	ErrInvalidCount         error = errors.New("Count of games must be greater than 0")
+	ErrPlayStdin            error = errors.New("Game can not be read from standard input, it is used for keys")
+	ErrGameNumber           error = errors.New("Collection does not contain game with given number")
+	ErrReplStdin            error = errors.New("Game can not be read from standard input, it is used for commands")
+	ErrPuzzleSources        error = errors.New("Only one of --puzzles and --db flags can be set")
+	ErrMirrorAxis           error = errors.New("Mirror axis should be h (horizontal) or v (vertical)")
+	ErrInvalidDate          error = errors.New("Date should be in format YYYY-MM-DD")
+	ErrPageSize             error = errors.New("Page size should be a4 or letter")
+	ErrExportFormat         error = errors.New("Export format should be svg, png or html")
+)
+
+//runSolve solves games and writes their solutions in output format.
+func runSolve(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "solve", &opts, "format", "output", "strategies", "timeout")
+	trace := fs.Bool("trace", false, "write every step of solving to standard error")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	strategies, guess, err := opts.engineStrategies()
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	puzzles, format, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	output, err := opts.outputFormat(format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	var observer engine.Observer
+	if *trace {
+		observer = engine.NewTracer(env.stderr)
+	}
+	code := exitOk
+	var solved []*structures.Puzzle
+	for idx, p := range puzzles {
+		if err = solveGame(p.Game, strategies, guess, &opts, observer); err != nil {
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+			continue
+		}
+		solved = append(solved, p)
+	}
+	if err = writePuzzles(env.stdout, solved, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return code
+}
+
+//solveGame solves game by strategies, when guess is false only logical
+// strategies are used. Observer is registered on engine when it is not nil.
+func solveGame(g *structures.Game, strategies []engine.Strategy, guess bool, opts *options,
+	observer engine.Observer) error {
+	ctx, cancel := opts.context()
+	defer cancel()
+	e := engine.NewEngine(g)
+	e.SetStrategies(strategies)
+	if observer != nil {
+		e.AddObserver(observer)
+	}
+	var err error
+	if guess {
+		_, err = e.Solve(ctx)
+	} else {
+		_, err = e.Run()
+	}
+	return err
+}
+
+//runValidate checks that games have no conflict and have unique solution.
+func runValidate(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "validate", &opts, "format", "timeout")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	code := exitOk
+	for idx, p := range puzzles {
+		message, err := validateGame(p.Game, &opts)
+		if err != nil && !errors.Is(err, engine.ErrUnsolvable) && !errors.Is(err, engine.ErrMultipleSolutions) {
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+			continue
+		}
+		if err != nil {
+			code = worse(code, exitFailure)
+		}
+		fmt.Fprintf(env.stdout, "%s%s\n", label(puzzles, idx), message)
+	}
+	return code
+}
+
+//validateGame returns description of game state, error is returned when game
+// is not valid.
+func validateGame(g *structures.Game, opts *options) (string, error) {
+	report, err := g.ValidationReport()
+	if err != nil {
+		return "", err
+	}
+	if !report.IsValid() {
+		var problems []string
+		for _, c := range report.Conflicts {
+			problems = append(problems, c.String())
+		}
+		if len(report.EmptyCandidates) > 0 {
+			problems = append(problems, "no candidate in cells "+strings.Join(report.EmptyCandidates, " "))
+		}
+		return "invalid: " + strings.Join(problems, "; "), engine.ErrUnsolvable
+	}
+	ctx, cancel := opts.context()
+	defer cancel()
+	_, err = engine.NewEngine(g.Clone()).SolveUnique(ctx)
+	switch {
+	case err == nil:
+		return "valid", nil
+	case errors.Is(err, engine.ErrUnsolvable), errors.Is(err, engine.ErrMultipleSolutions):
+		return "invalid: " + err.Error(), err
+	}
+	return "", err
+}
+
+//runRate rates difficulty of games.
+func runRate(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "rate", &opts, "format", "timeout")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	code := exitOk
+	for idx, p := range puzzles {
+		ctx, cancel := opts.context()
+		rating, err := engine.Rate(ctx, p.Game)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+			continue
+		}
+		var used []string
+		for _, s := range engine.Strategies() {
+			if count := rating.Strategies[s.Name()]; count > 0 {
+				used = append(used, fmt.Sprintf("%s %d", s.Name(), count))
+			}
+		}
+		if rating.Guesses > 0 {
+			used = append(used, fmt.Sprintf("%s %d", engine.StrategyGuess, rating.Guesses))
+		}
+		fmt.Fprintf(env.stdout, "%s%s (score %d): %s\n", label(puzzles, idx), rating.Level, rating.Score,
+			strings.Join(used, ", "))
+	}
+	return code
+}
+
+//runHint writes next logical step of games.
+func runHint(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "hint", &opts, "format", "strategies")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	strategies, _, err := opts.engineStrategies()
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	code := exitOk
+	for idx, p := range puzzles {
+		e := engine.NewEngine(p.Game)
+		e.SetStrategies(strategies)
+		step, err := e.Hint()
+		if err != nil {
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+			continue
+		}
+		if step == nil {
+			fmt.Fprintf(env.stdout, "%sno logical step found\n", label(puzzles, idx))
+			code = worse(code, exitFailure)
+			continue
+		}
+		fmt.Fprintf(env.stdout, "%s%s\n", label(puzzles, idx), step)
+	}
+	return code
+}
+
+//runGenerate generates new games with unique solution.
+func runGenerate(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "generate", &opts, "output", "timeout")
+	level := fs.String("level", "", "level of games ("+strings.Join(engine.Levels(), ", ")+"), any when empty")
+	count := fs.Int("count", defaultGenerated, "count of generated games")
+	seed := fs.Int64("seed", 0, "seed of random generator, current time when 0")
+	size := fs.String("size", structures.StandardGeometry.String(), "grid size (4x4, 6x6, 9x9, 12x12 or 16x16)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(env.stderr, "%s: unexpected arguments %v\n", fs.Name(), fs.Args())
+		return exitUsage
+	}
+	if *count < 1 {
+		return fail(env, fs.Name(), ErrInvalidCount)
+	}
+	geometry, err := structures.ParseGeometry(*size)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	output, err := opts.outputFormat(structures.FormatLine)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	r := rand.New(rand.NewSource(*seed))
+	var puzzles []*structures.Puzzle
+	for i := 0; i < *count; i++ {
+		p, err := generatePuzzle(r, geometry, *level, &opts)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		puzzles = append(puzzles, p)
+	}
+	if err = writePuzzles(env.stdout, puzzles, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//generatePuzzle generates one game of the grid geometry and rates it.
+func generatePuzzle(r *rand.Rand, geometry *structures.Geometry, level string,
+	opts *options) (*structures.Puzzle, error) {
+	ctx, cancel := opts.context()
+	defer cancel()
+	g, err := engine.GenerateGeometry(ctx, r, geometry, level)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := engine.Rate(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	return &structures.Puzzle{Game: g, Rating: rating.Level}, nil
+}
+
+//runCount writes count of solutions of games.
+func runCount(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "count", &opts, "format", "timeout")
+	limit := fs.Int("limit", defaultCountLimit, "stop counting at limit (no limit when 0)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	code := exitOk
+	for idx, p := range puzzles {
+		ctx, cancel := opts.context()
+		count, err := engine.NewEngine(p.Game.Clone()).CountSolutions(ctx, *limit)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(env.stderr, "%s: %s%v (%d solutions found)\n", fs.Name(), label(puzzles, idx), err, count)
+			code = worse(code, exitCode(err))
+			continue
+		}
+		fmt.Fprintf(env.stdout, "%s%d\n", label(puzzles, idx), count)
+	}
+	return code
+}
+
+//runConvert writes games in output format.
+func runConvert(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "convert", &opts, "format", "output")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if opts.output == "" {
+		fmt.Fprintf(env.stderr, "%s: %v\n", fs.Name(), ErrOutputFormatRequired)
+		fs.Usage()
+		return exitUsage
+	}
+	output, err := opts.outputFormat("")
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if err = writePuzzles(env.stdout, puzzles, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//runBooklet writes games as PDF booklet, solutions of games are printed in
+// answers section.
+func runBooklet(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "booklet", &opts, "format", "timeout")
+	title := fs.String("title", "Sudoku", "title of booklet")
+	layout := fs.String("layout", "2x3", "columns and rows of games on one page")
+	answers := fs.String("answers", "3x4", "columns and rows of answers on one page")
+	page := fs.String("page", "a4", "page size (a4, letter)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	b := render.NewBooklet(*title)
+	var err error
+	if b.Layout, err = render.ParseLayout(*layout); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if b.AnswerLayout, err = render.ParseLayout(*answers); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	switch strings.ToLower(*page) {
+	case "a4":
+	case "letter":
+		b.PageWidth, b.PageHeight = render.LetterWidth, render.LetterHeight
+	default:
+		return fail(env, fs.Name(), ErrPageSize)
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	code := exitOk
+	for idx, p := range puzzles {
+		solution := p.Game.Clone()
+		if err = solveGame(solution, engine.Strategies(), true, &opts, nil); err != nil {
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+			solution = nil
+		}
+		b.Add(p.Name, p.Game, solution)
+	}
+	if err = b.WritePDF(env.stdout); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return code
+}
+
+//runExport writes game drawn as image or as HTML page with solving widget
+// in output format.
+func runExport(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "export", &opts, "format", "timeout")
+	output := fs.String("output", "svg", "export format (svg, png, html)")
+	number := fs.Int("number", 1, "number of game in collection file")
+	cellSize := fs.Int("cell-size", render.DefaultOptions().CellSize, "size of cell in pixels")
+	pencilMarks := fs.Bool("pencil-marks", false, "draw candidates of empty cells")
+	title := fs.String("title", "", "title of HTML page")
+	solve := fs.Bool("solve", false, "add solution and solving steps to HTML page")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if *number < 1 || *number > len(puzzles) {
+		return fail(env, fs.Name(), ErrGameNumber)
+	}
+	g := puzzles[*number-1].Game
+	renderOpts := render.Options{CellSize: *cellSize, PencilMarks: *pencilMarks}
+	switch strings.ToLower(*output) {
+	case "svg":
+		err = render.SVG(env.stdout, g, renderOpts)
+	case "png":
+		err = render.PNG(env.stdout, g, renderOpts)
+	case "html":
+		htmlOpts := render.HTMLOptions{Title: *title}
+		if *solve {
+			if htmlOpts.Solution, htmlOpts.Steps, err = solutionSteps(g, &opts); err != nil {
+				return fail(env, fs.Name(), err)
+			}
+		}
+		err = render.HTML(env.stdout, g, htmlOpts)
+	default:
+		err = ErrExportFormat
+	}
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//solutionSteps returns solved copy of the game and steps of its solving.
+func solutionSteps(g *structures.Game, opts *options) (*structures.Game, []engine.Step, error) {
+	ctx, cancel := opts.context()
+	defer cancel()
+	solution := g.Clone()
+	result, err := engine.NewEngine(solution).Solve(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return solution, result.Steps, nil
+}
+
+//runTransform writes games transformed by validity-preserving transformations,
+// they are applied in order transpose, rotate, mirror and random.
+func runTransform(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "transform", &opts, "format", "output")
+	transpose := fs.Bool("transpose", false, "swap rows and columns")
+	rotate := fs.Int("rotate", 0, "rotate clockwise by quarter turns (counterclockwise when negative)")
+	mirror := fs.String("mirror", "", "mirror by axis h (columns a and i swapped) or v (rows 1 and 9 swapped)")
+	random := fs.Bool("random", false, "apply random transformation (digits, rows, columns, bands, stacks)")
+	seed := fs.Int64("seed", 0, "seed of random transformation, current time when 0")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	t := structures.IdentityTransform()
+	if *transpose {
+		t = t.Then(structures.Transpose())
+	}
+	t = t.Then(structures.Rotate(*rotate))
+	switch *mirror {
+	case "":
+	case "h":
+		t = t.Then(structures.MirrorHorizontal())
+	case "v":
+		t = t.Then(structures.MirrorVertical())
+	default:
+		return fail(env, fs.Name(), ErrMirrorAxis)
+	}
+	puzzles, input, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	output, err := opts.outputFormat(input)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	r := rand.New(rand.NewSource(*seed))
+	for _, p := range puzzles {
+		transform := t
+		if *random {
+			transform = t.Then(structures.RandomTransform(r))
+		}
+		if p.Game, err = p.Game.Transform(transform); err != nil {
+			return fail(env, fs.Name(), err)
+		}
+	}
+	if err = writePuzzles(env.stdout, puzzles, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//runPlay plays game from file (or generated game) in terminal.
+func runPlay(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "play", &opts, "format")
+	level := fs.String("level", "", "level of generated game when no file is given ("+
+		strings.Join(engine.Levels(), ", ")+")")
+	number := fs.Int("number", 1, "number of game in collection file")
+	size := fs.String("size", structures.StandardGeometry.String(), "grid size of generated game (4x4, 6x6, 9x9, 12x12 or 16x16)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(env.stderr, "%s: only one file can be played\n", fs.Name())
+		return exitUsage
+	}
+	geometry, err := structures.ParseGeometry(*size)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	tty, ok := env.stdin.(*os.File)
+	if !ok {
+		return fail(env, fs.Name(), play.ErrNotTerminal)
+	}
+	var g *structures.Game
+	if fs.NArg() == 1 {
+		if fs.Arg(0) == stdinPath {
+			return fail(env, fs.Name(), ErrPlayStdin)
+		}
+		puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		if *number < 1 || *number > len(puzzles) {
+			return fail(env, fs.Name(), ErrGameNumber)
+		}
+		g = puzzles[*number-1].Game
+	} else {
+		p, err := generatePuzzle(rand.New(rand.NewSource(time.Now().UnixNano())), geometry, *level, &opts)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		g = p.Game
+	}
+	m, err := play.Run(g, tty, env.stdout)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if m.Solved() {
+		fmt.Fprintf(env.stdout, "Solved in %s.\n", m.Elapsed().Round(time.Second))
+	} else {
+		fmt.Fprintf(env.stdout, "Game left after %s:\n%s", m.Elapsed().Round(time.Second), m.Game().Line()+"\n")
+	}
+	return exitOk
+}
+
+//runRepl starts REPL reading commands from standard input, game from file is
+// loaded when it is given.
+func runRepl(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "repl", &opts, "format", "strategies")
+	number := fs.Int("number", 1, "number of game in collection file")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(env.stderr, "%s: only one file can be loaded\n", fs.Name())
+		return exitUsage
+	}
+	r := repl.New(env.stdout)
+	if opts.strategies != "" {
+		strategies, err := engine.ParseStrategies(opts.strategies)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		r.SetStrategies(strategies)
+	}
+	if fs.NArg() == 1 {
+		if fs.Arg(0) == stdinPath {
+			return fail(env, fs.Name(), ErrReplStdin)
+		}
+		puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		if *number < 1 || *number > len(puzzles) {
+			return fail(env, fs.Name(), ErrGameNumber)
+		}
+		r.Load(puzzles[*number-1].Game)
+	}
+	if err := r.Run(env.stdin); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//runServe runs HTTP server with REST API until it is interrupted.
+func runServe(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "serve", &opts)
+	addr := fs.String("addr", defaultAddr, "address of server")
+	maxBody := fs.Int64("max-body", server.DefaultMaxBodySize, "maximal size of request body in bytes")
+	timeout := fs.Duration("timeout", server.DefaultTimeout, "time limit for one request")
+	limit := fs.Int("limit", server.DefaultCountLimit, "maximal count of solutions counted by one request")
+	puzzles := fs.String("puzzles", "", "file with puzzles played in sessions, sessions are disabled when empty")
+	db := fs.String("db", "", "puzzle library played in sessions (instead of --puzzles) and of daily puzzles")
+	window := fs.Int("window", daily.DefaultWindow, "count of days in which daily puzzle is not repeated")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(env.stderr, "%s: unexpected arguments %v\n", fs.Name(), fs.Args())
+		return exitUsage
+	}
+	if *puzzles != "" && *db != "" {
+		return fail(env, fs.Name(), ErrPuzzleSources)
+	}
+	serverOpts := server.Options{MaxBodySize: *maxBody, Timeout: *timeout, CountLimit: *limit}
+	if *puzzles != "" {
+		source, err := sessionPuzzles(env, *puzzles)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		serverOpts.Sessions = session.NewService(source, session.NewMemoryStore(), *timeout)
+	}
+	if *db != "" {
+		library, err := store.Open(*db)
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		defer library.Close()
+		serverOpts.Sessions = session.NewService(library, session.NewMemoryStore(), *timeout)
+		serverOpts.Daily = daily.NewScheduler(library, *window)
+	}
+	srv := server.New(serverOpts).HTTPServer(*addr)
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer func() {
+		signal.Stop(interrupted)
+		close(interrupted)
+	}()
+	go func() {
+		if _, ok := <-interrupted; ok {
+			srv.Shutdown(context.Background())
+		}
+	}()
+	fmt.Fprintf(env.stderr, "%s: listening on %s\n", fs.Name(), *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//sessionPuzzles reads puzzles played in sessions from file, puzzle id is its
+// name or its 1-based number in the file when puzzle has no name.
+func sessionPuzzles(env *environment, path string) (session.MemoryPuzzles, error) {
+	puzzles, _, err := readPuzzles(env, []string{path}, "")
+	if err != nil {
+		return nil, err
+	}
+	source := make(session.MemoryPuzzles, len(puzzles))
+	for idx, p := range puzzles {
+		id := p.Name
+		if id == "" {
+			id = strconv.Itoa(idx + 1)
+		}
+		source[id] = p
+	}
+	return source, nil
+}
+
+//runImport adds games to puzzle library, games equivalent to stored ones are
+// skipped.
+func runImport(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "import", &opts, "format", "timeout")
+	db := fs.String("db", defaultDB, "file of puzzle library")
+	tags := fs.String("tags", "", "comma separated tags of imported games")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	puzzles, _, err := readPuzzles(env, fs.Args(), opts.format)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	library, err := store.Open(*db)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	defer library.Close()
+	code := exitOk
+	added, duplicates := 0, 0
+	for idx, p := range puzzles {
+		ctx, cancel := opts.context()
+		r, err := library.Add(ctx, p, strings.Split(*tags, ",")...)
+		cancel()
+		var duplicate *store.DuplicateError
+		switch {
+		case errors.As(err, &duplicate):
+			fmt.Fprintf(env.stdout, "%sduplicate of %d\n", label(puzzles, idx), duplicate.Existing.ID)
+			duplicates++
+		case err != nil:
+			fmt.Fprintf(env.stderr, "%s: %s%v\n", fs.Name(), label(puzzles, idx), err)
+			code = worse(code, exitCode(err))
+		default:
+			fmt.Fprintf(env.stdout, "%sadded %d (%s)\n", label(puzzles, idx), r.ID, r.Level)
+			added++
+		}
+	}
+	fmt.Fprintf(env.stdout, "%d added, %d duplicates, %d puzzles in library\n", added, duplicates, library.Len())
+	return code
+}
+
+//runQuery writes games from puzzle library matching conditions.
+func runQuery(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "query", &opts, "output")
+	db := fs.String("db", defaultDB, "file of puzzle library")
+	var q store.Query
+	fs.IntVar(&q.MinScore, "min-score", 0, "minimal rating score (1 - 5)")
+	fs.IntVar(&q.MaxScore, "max-score", 0, "maximal rating score (1 - 5)")
+	fs.StringVar(&q.Tag, "tag", "", "tag of games")
+	fs.IntVar(&q.MinGivens, "min-givens", 0, "minimal count of given cells")
+	fs.IntVar(&q.MaxGivens, "max-givens", 0, "maximal count of given cells")
+	fs.IntVar(&q.Limit, "limit", 0, "maximal count of games (no limit when 0)")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(env.stderr, "%s: unexpected arguments %v\n", fs.Name(), fs.Args())
+		return exitUsage
+	}
+	output, err := opts.outputFormat(structures.FormatLine)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	if _, err = os.Stat(*db); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	library, err := store.Open(*db)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	defer library.Close()
+	var puzzles []*structures.Puzzle
+	for _, r := range library.Query(q) {
+		p, err := r.Puzzle()
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		if p.Name == "" {
+			p.Name = strconv.Itoa(r.ID)
+		}
+		puzzles = append(puzzles, p)
+	}
+	if err = writePuzzles(env.stdout, puzzles, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
+
+//runDaily writes daily puzzles of date, puzzles which were not published yet
+// are picked from puzzle library or generated.
+func runDaily(env *environment, args []string) int {
+	var opts options
+	fs := newFlagSet(env, "daily", &opts, "output", "timeout")
+	db := fs.String("db", defaultDB, "file of puzzle library")
+	date := fs.String("date", "", "date of puzzles (YYYY-MM-DD), today when empty")
+	level := fs.String("level", "", "level of puzzle ("+strings.Join(engine.Levels(), ", ")+"), all when empty")
+	window := fs.Int("window", daily.DefaultWindow, "count of days in which puzzle is not repeated")
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(env.stderr, "%s: unexpected arguments %v\n", fs.Name(), fs.Args())
+		return exitUsage
+	}
+	output, err := opts.outputFormat(structures.FormatLine)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	day := time.Now()
+	if *date != "" {
+		if day, err = time.Parse(daily.DateLayout, *date); err != nil {
+			return fail(env, fs.Name(), ErrInvalidDate)
+		}
+	}
+	levels := engine.Levels()
+	if *level != "" {
+		levels = []string{*level}
+	}
+	library, err := store.Open(*db)
+	if err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	defer library.Close()
+	scheduler := daily.NewScheduler(library, *window)
+	var puzzles []*structures.Puzzle
+	for _, l := range levels {
+		ctx, cancel := opts.context()
+		r, err := scheduler.Puzzle(ctx, day, l)
+		cancel()
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		p, err := r.Puzzle()
+		if err != nil {
+			return fail(env, fs.Name(), err)
+		}
+		p.Name = strconv.Itoa(r.ID)
+		puzzles = append(puzzles, p)
+	}
+	if err = writePuzzles(env.stdout, puzzles, output); err != nil {
+		return fail(env, fs.Name(), err)
+	}
+	return exitOk
+}
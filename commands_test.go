@@ -0,0 +1,261 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestCommands(t *testing.T) {
+	unsolvable := ".2345678.9........1........" + strings.Repeat(".", 54)
+	// values 2 and 3 in c1, f1, c2, f2 can be swapped.
+	twoSolutions := "81.75.64994.68.175" + hardSolved[18:]
+	tests := []struct {
+		stdin    string
+		args     []string
+		code     int
+		expected string
+	}{
+		{hardGame, []string{"solve"}, exitOk, hardSolved + "\n"},
+		{easyGame + "\tfirst\n" + hardGame + "\tsecond\n", []string{"solve"}, exitOk,
+			easySolved + "\tfirst\n" + hardSolved + "\tsecond\n"},
+		{hardGame, []string{"solve", "--strategies", "naked-single,hidden-single"}, exitFailure, ""},
+		{hardGame, []string{"solve", "--strategies", "naked-single,guess", "--output", "json"}, exitOk,
+			`"values":"` + hardSolved + `"`},
+		{unsolvable, []string{"solve"}, exitFailure, ""},
+		{hardGame, []string{"solve", "--timeout", "1ns"}, exitTimeout, ""},
+		{hardGame, []string{"validate"}, exitOk, "valid\n"},
+		{twoSolutions, []string{"validate"}, exitFailure, "invalid: Game has more than one solution\n"},
+		{unsolvable, []string{"validate"}, exitFailure, "invalid: no candidate in cells a1\n"},
+		{"55" + hardGame[2:], []string{"validate"}, exitFailure, "invalid: row 1: value 5 in cells [a1 b1]"},
+		{easyGame, []string{"rate"}, exitOk, "easy (score 1): naked-single 45\n"},
+		{hardGame, []string{"rate"}, exitOk, "extreme (score 5)"},
+		{easyGame, []string{"hint"}, exitOk, "a1 = 8 (naked-single)\n"},
+		{easyGame, []string{"hint", "--strategies", "hidden-single"}, exitOk, "i1 = 7 (hidden-single, row 1)\n"},
+		{hardGame, []string{"hint"}, exitFailure, "no logical step found\n"},
+		{twoSolutions, []string{"count"}, exitOk, "2\n"},
+		{strings.Repeat(".", 81), []string{"count", "--limit", "7"}, exitOk, "7\n"},
+		{hardGame, []string{"convert", "--output", "sdx"}, exitOk, "8 "},
+		{hardGame, []string{"convert", "--format", "grid"}, exitUsage, ""},
+		{hardGame, []string{"transform", "--transpose", "--rotate", "1", "--mirror", "h"}, exitOk, hardGame + "\n"},
+		{hardGame, []string{"transform", "--rotate", "2", "--mirror", "h"}, exitOk, ".9....4....85...1...1....68...1...3.....457...5...7....7..9.2....36.....8........\n"},
+		{hardGame, []string{"transform", "--mirror", "x"}, exitUsage, ""},
+		{easyGame + "\n10: a1 b1\n", []string{"solve"}, exitOk, easySolved + "\n10: a1 b1\n"},
+		{easyGame + "\n10: a1 b1\n", []string{"transform", "--random"}, exitUsage, ""},
+		{hardGame, []string{"play"}, exitError, ""},
+		{"load " + easyGame + "\nstep\nexplain\n", []string{"repl"}, exitOk, "a1 = 8: 8 is the only candidate of a1."},
+		{"", []string{"repl", "-"}, exitUsage, ""},
+		{"", []string{"serve", "extra"}, exitUsage, ""},
+		{"", []string{"serve", "--addr", "localhost:-1"}, exitError, ""},
+		{"", []string{"serve", "--puzzles", "missing.txt"}, exitError, ""},
+		{"", []string{"serve", "--puzzles", "a.txt", "--db", "a.db"}, exitUsage, ""},
+		{"", []string{"query", "--db", "missing.db"}, exitError, ""},
+	}
+	for _, test := range tests {
+		code, stdout, stderr := runCommand(test.stdin, test.args...)
+		if code != test.code {
+			t.Errorf("sudoku %v returns exit code %d (%s), but expected: %d", test.args, code, stderr, test.code)
+		}
+		if !strings.Contains(stdout, test.expected) {
+			t.Errorf("sudoku %v writes %q, it should contain: %q", test.args, stdout, test.expected)
+		}
+	}
+}
+
+func TestCommandSolveTrace(t *testing.T) {
+	code, stdout, stderr := runCommand(hardGame, "solve", "--trace")
+	if code != exitOk || stdout != hardSolved+"\n" {
+		t.Errorf("sudoku solve --trace returns exit code %d and %q, but expected: %d and solution", code, stdout,
+			exitOk)
+	}
+	if !strings.HasPrefix(stderr, "guess g8 = 3") || !strings.Contains(stderr, "\nbacktrack ") ||
+		!strings.HasSuffix(stderr, "guesses\n") {
+		t.Errorf("sudoku solve --trace should write trace to stderr, but writes: %q", stderr)
+	}
+}
+
+func TestCommandGenerate(t *testing.T) {
+	code, stdout, stderr := runCommand("", "generate", "--seed", "1", "--count", "2", "--level", "easy")
+	if code != exitOk {
+		t.Errorf("sudoku generate returns exit code %d (%s), but expected: %d", code, stderr, exitOk)
+	}
+	reader := structures.NewCollectionReader(strings.NewReader(stdout))
+	count := 0
+	for reader.Next() {
+		count++
+		if reader.Puzzle().Rating != "easy" {
+			t.Errorf("Generated puzzle has rating %s, but expected: easy", reader.Puzzle().Rating)
+		}
+		code, out, _ := runCommand(reader.Puzzle().Game.Line(), "validate")
+		if code != exitOk {
+			t.Errorf("Generated puzzle should be valid, but is: %s", out)
+		}
+	}
+	if reader.Err() != nil || count != 2 {
+		t.Errorf("sudoku generate should write 2 puzzles, but wrote %d, err: %v", count, reader.Err())
+	}
+}
+
+func TestSessionPuzzles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzles.txt")
+	if err := os.WriteFile(path, []byte(easyGame+"\teasy\n"+hardGame+"\n"), 0600); err != nil {
+		t.Errorf("Puzzles file should be written, but err: %v", err)
+		return
+	}
+	source, err := sessionPuzzles(&environment{}, path)
+	if err != nil {
+		t.Errorf("Puzzles should be read, but err: %v", err)
+		return
+	}
+	for id, expected := range map[string]string{"easy": easyGame, "2": hardGame} {
+		p, err := source.Puzzle(id)
+		if err != nil || p.Game.Line() != expected {
+			t.Errorf("Puzzle %s should be %s, but is: %v (err: %v)", id, expected, p, err)
+		}
+	}
+}
+
+func TestCommandLibrary(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "puzzles.db")
+	relabeled := strings.NewReplacer("1", "2", "2", "1").Replace(easyGame)
+	input := easyGame + "\teasy\n" + hardGame + "\n" + relabeled + "\tcopy\n"
+	code, stdout, stderr := runCommand(input, "import", "--db", db, "--tags", "daily")
+	expected := "easy: added 1 (easy)\n#2: added 2 (extreme)\ncopy: duplicate of 1\n" +
+		"2 added, 1 duplicates, 2 puzzles in library\n"
+	if code != exitOk || stdout != expected {
+		t.Errorf("sudoku import returns exit code %d (%s) and %q, but expected: %d and %q", code, stderr, stdout,
+			exitOk, expected)
+	}
+	code, stdout, stderr = runCommand("", "query", "--db", db, "--min-score", "5", "--tag", "daily")
+	expected = hardGame + "\t2\textreme\t\tdaily\n"
+	if code != exitOk || stdout != expected {
+		t.Errorf("sudoku query returns exit code %d (%s) and %q, but expected: %d and %q", code, stderr, stdout,
+			exitOk, expected)
+	}
+}
+
+func TestCommandBooklet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collection.txt")
+	unsolvable := ".2345678.9........1........" + strings.Repeat(".", 54)
+	if err := os.WriteFile(path, []byte(easyGame+"\tfirst\n"+hardGame+"\n"+unsolvable+"\tbroken\n"), 0600); err != nil {
+		t.Errorf("Collection file should be written, but err: %v", err)
+		return
+	}
+	code, stdout, stderr := runCommand("", "booklet", "--title", "Weekly pack", "--layout", "1x2", path)
+	if code != exitFailure || !strings.Contains(stderr, "broken: ") {
+		t.Errorf("sudoku booklet returns exit code %d (%s), but expected: %d with error of broken game", code,
+			stderr, exitFailure)
+	}
+	// 3 puzzles in 1x2 layout and 2 answers in 3x4 layout.
+	for _, expected := range []string{"%PDF-", "/Count 3", "(Weekly pack)", "(first)", "(#2)", "(broken)", "(Answers)"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("sudoku booklet should write PDF which contains %s", expected)
+		}
+	}
+	for _, args := range [][]string{{"--layout", "2"}, {"--answers", "0x1"}, {"--page", "a3"}, {"--layout", "30x30"}} {
+		if code, _, _ := runCommand(easyGame, append([]string{"booklet"}, args...)...); code != exitUsage {
+			t.Errorf("sudoku booklet %v returns exit code %d, but expected: %d", args, code, exitUsage)
+		}
+	}
+}
+
+func TestCommandExport(t *testing.T) {
+	input := easyGame + "\n" + hardGame + "\n"
+	code, stdout, stderr := runCommand(input, "export", "--number", "2", "--cell-size", "20")
+	if code != exitOk || !strings.HasPrefix(stdout, "<svg") || !strings.Contains(stdout, `width="`) {
+		t.Errorf("sudoku export returns exit code %d (%s) and %q, but expected SVG image", code, stderr, stdout)
+	}
+	code, stdout, stderr = runCommand(input, "export", "--output", "png", "--pencil-marks")
+	if code != exitOk || !strings.HasPrefix(stdout, "\x89PNG") {
+		t.Errorf("sudoku export --output png returns exit code %d (%s), but expected PNG image", code, stderr)
+	}
+	code, stdout, stderr = runCommand(input, "export", "--output", "html", "--title", "Easy one", "--solve")
+	for _, expected := range []string{"<title>Easy one</title>", `"solution":"` + easySolved + `"`, "<li>a1 = 8 "} {
+		if code != exitOk || !strings.Contains(stdout, expected) {
+			t.Errorf("sudoku export --output html returns exit code %d (%s), page should contain: %s", code, stderr,
+				expected)
+		}
+	}
+	unsolvable := ".2345678.9........1........" + strings.Repeat(".", 54)
+	if code, _, _ = runCommand(unsolvable, "export", "--output", "html", "--solve"); code != exitFailure {
+		t.Errorf("sudoku export --solve of unsolvable game returns exit code %d, but expected: %d", code, exitFailure)
+	}
+	for _, args := range [][]string{{"--output", "gif"}, {"--number", "3"}} {
+		if code, _, _ := runCommand(input, append([]string{"export"}, args...)...); code != exitUsage {
+			t.Errorf("sudoku export %v returns exit code %d, but expected: %d", args, code, exitUsage)
+		}
+	}
+}
+
+func TestCommandTransformRandom(t *testing.T) {
+	code, stdout, stderr := runCommand(easyGame+"\teasy\n", "transform", "--random", "--seed", "1")
+	g, err := structures.ParseLine(strings.SplitN(stdout, "\t", 2)[0])
+	if code != exitOk || err != nil || !strings.HasSuffix(stdout, "\teasy\n") {
+		t.Errorf("sudoku transform --random returns exit code %d (%s) and %q, but expected: %d and game", code,
+			stderr, stdout, exitOk)
+		return
+	}
+	original, _ := structures.ParseLine(easyGame)
+	if g.Line() == easyGame || !structures.AreEquivalent(g, original) {
+		t.Errorf("Randomly transformed game %s should be other game equivalent to %s", g.Line(), easyGame)
+	}
+}
+
+func TestCommandDaily(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "puzzles.db")
+	if code, _, stderr := runCommand(hardGame, "import", "--db", db); code != exitOk {
+		t.Errorf("sudoku import returns exit code %d (%s), but expected: %d", code, stderr, exitOk)
+	}
+	tests := []struct {
+		args     []string
+		code     int
+		expected string
+	}{
+		{[]string{"--date", "2021-03-14", "--level", "extreme"}, exitOk,
+			hardGame + "\t1\textreme\t\tdaily:2021-03-14:extreme\n"},
+		{[]string{"--date", "14.3.2021"}, exitUsage, ""},
+		{[]string{"--level", "trivial"}, exitUsage, ""},
+	}
+	for _, test := range tests {
+		code, stdout, stderr := runCommand("", append([]string{"daily", "--db", db}, test.args...)...)
+		if code != test.code || stdout != test.expected {
+			t.Errorf("sudoku daily %v returns exit code %d (%s) and %q, but expected: %d and %q", test.args, code,
+				stderr, stdout, test.code, test.expected)
+		}
+	}
+}
+
+func TestCommandGenerateSize(t *testing.T) {
+	code, stdout, stderr := runCommand("", "generate", "--seed", "1", "--size", "6x6")
+	if code != exitOk {
+		t.Errorf("sudoku generate returns exit code %d (%s), but expected: %d", code, stderr, exitOk)
+	}
+	g, err := structures.ParseLine(strings.Fields(stdout)[0])
+	if err != nil || g.Geometry() != structures.Geometry6x6 {
+		t.Errorf("sudoku generate should write 6x6 game, but wrote: %s, err: %v", stdout, err)
+	}
+	if code, _, _ := runCommand("", "generate", "--size", "5x5"); code != exitUsage {
+		t.Errorf("sudoku generate --size 5x5 returns exit code %d, but expected: %d", code, exitUsage)
+	}
+}
+
+func TestCommandConvertStandardOnly(t *testing.T) {
+	mini6 := ".......4..61.5....3...2.......2.6.3."
+	for _, format := range []string{"sdk", "ss", "sdx"} {
+		code, stdout, stderr := runCommand(mini6, "convert", "--output", format)
+		if code != exitUsage || !strings.Contains(stderr, structures.ErrStandardOnly.Error()) {
+			t.Errorf("sudoku convert --output %s of 6x6 game returns exit code %d (%s), but expected: %d",
+				format, code, stderr, exitUsage)
+		}
+		if stdout != "" {
+			t.Errorf("sudoku convert --output %s of 6x6 game writes %q, but expected no output", format, stdout)
+		}
+	}
+	code, stdout, stderr := runCommand(mini6, "convert", "--output", "grid")
+	if code != exitOk || !strings.HasPrefix(stdout, "...|...\n.4.|.61\n") {
+		t.Errorf("sudoku convert --output grid of 6x6 game returns exit code %d (%s) and %q", code, stderr, stdout)
+	}
+}
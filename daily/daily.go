@@ -0,0 +1,153 @@
+package daily
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/store"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Daily puzzle constants.
+const (
+	DefaultWindow    int    = 90
+	DateLayout       string = "2006-01-02"
+	Source           string = "daily"
+	tagPrefix        string = "daily:"
+	generateAttempts int    = 5
+)
+
+//Errors for daily puzzles.
+var (
+	ErrNoPuzzle error = errors.New("No new puzzle of requested level was found or generated")
+)
+
+//Scheduler picks daily puzzle of every level from store. Puzzle is chosen by
+// random generator seeded from date and level, so the same store gives the
+// same puzzle, and it is tagged by Tag(date, level) in store, so it does not
+// change later. Puzzles published within window days around the date are not
+// picked again, new puzzle is generated and stored when store has no unused one.
+type Scheduler struct {
+	store  *store.Store
+	window int
+	mu     sync.Mutex
+}
+
+//NewScheduler creates Scheduler object, window is count of days in which
+// puzzle is not repeated (DefaultWindow when it is 0).
+func NewScheduler(s *store.Store, window int) *Scheduler {
+	if window == 0 {
+		window = DefaultWindow
+	}
+	return &Scheduler{store: s, window: window}
+}
+
+//Tag returns tag of puzzle published at date for level, e.g. daily:2021-03-14:easy.
+func Tag(date time.Time, level string) string {
+	return tagPrefix + date.Format(DateLayout) + ":" + level
+}
+
+//Seed returns seed of random generator for date and level.
+func Seed(date time.Time, level string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(date.Format(DateLayout) + "/" + level))
+	return int64(h.Sum64())
+}
+
+//Puzzle method returns puzzle of level published at date, it is picked or
+// generated when it was not published yet.
+func (s *Scheduler) Puzzle(ctx context.Context, date time.Time, level string) (*store.Record, error) {
+	level, err := engine.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	date = day(date)
+	tag := Tag(date, level)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if published := s.store.Query(store.Query{Tag: tag, Limit: 1}); len(published) > 0 {
+		return published[0], nil
+	}
+	r := rand.New(rand.NewSource(Seed(date, level)))
+	recent := s.recent(date)
+	var candidates []*store.Record
+	for _, record := range s.store.Query(store.Query{Level: level}) {
+		if !recent[record.ID] {
+			candidates = append(candidates, record)
+		}
+	}
+	var record *store.Record
+	if len(candidates) > 0 {
+		record = candidates[r.Intn(len(candidates))]
+	} else if record, err = s.generate(ctx, r, level, recent); err != nil {
+		return nil, err
+	}
+	return s.store.SetTags(record.ID, append(record.Tags, tag)...)
+}
+
+//generate method generates new puzzle of level and adds it to store, stored
+// equivalent puzzle is used when it was not published recently.
+func (s *Scheduler) generate(ctx context.Context, r *rand.Rand, level string,
+	recent map[int]bool) (*store.Record, error) {
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		g, err := engine.Generate(ctx, r, level)
+		if err != nil {
+			return nil, err
+		}
+		record, err := s.store.Add(ctx, &structures.Puzzle{Game: g, Source: Source})
+		var duplicate *store.DuplicateError
+		switch {
+		case errors.As(err, &duplicate):
+			if !recent[duplicate.Existing.ID] {
+				return duplicate.Existing, nil
+			}
+		case err != nil:
+			return nil, err
+		default:
+			return record, nil
+		}
+	}
+	return nil, ErrNoPuzzle
+}
+
+//recent method returns ids of puzzles published less than window days before
+// or after date.
+func (s *Scheduler) recent(date time.Time) map[int]bool {
+	result := make(map[int]bool)
+	window := time.Duration(s.window) * 24 * time.Hour
+	for _, record := range s.store.Query(store.Query{}) {
+		for _, tag := range record.Tags {
+			published, ok := tagDate(tag)
+			if !ok {
+				continue
+			}
+			distance := published.Sub(date)
+			if distance < window && distance > -window {
+				result[record.ID] = true
+			}
+		}
+	}
+	return result
+}
+
+//tagDate returns date of daily puzzle tag.
+func tagDate(tag string) (time.Time, bool) {
+	if !strings.HasPrefix(tag, tagPrefix) {
+		return time.Time{}, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(tag, tagPrefix), ":", 2)
+	date, err := time.Parse(DateLayout, parts[0])
+	return date, err == nil
+}
+
+//day returns midnight UTC of the day of date in its location.
+func day(date time.Time) time.Time {
+	year, month, d := date.Date()
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
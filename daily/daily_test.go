@@ -0,0 +1,104 @@
+package daily
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/store"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	easyGame string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	hardGame string = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+)
+
+func openStore(t *testing.T) *store.Store {
+	s, err := store.Open(filepath.Join(t.TempDir(), "puzzles.db"))
+	if err != nil {
+		t.Fatalf("Store should be opened, but err: %v", err)
+	}
+	for _, line := range []string{easyGame, easyGame[:2] + "." + easyGame[3:], hardGame} {
+		g, _ := structures.ParseLine(line)
+		if _, err = s.Add(context.Background(), &structures.Puzzle{Game: g}); err != nil {
+			t.Fatalf("Puzzle should be added, but err: %v", err)
+		}
+	}
+	return s
+}
+
+func TestSchedulerPuzzle(t *testing.T) {
+	s := openStore(t)
+	defer s.Close()
+	scheduler := NewScheduler(s, 0)
+	date := time.Date(2021, 3, 14, 22, 30, 0, 0, time.UTC)
+	first, err := scheduler.Puzzle(context.Background(), date, "Easy")
+	if err != nil || first.Level != engine.LevelEasy || !first.HasTag("daily:2021-03-14:easy") {
+		t.Errorf("Easy puzzle tagged by date should be picked, but is: %+v (err: %v)", first, err)
+		return
+	}
+	again, err := scheduler.Puzzle(context.Background(), date.Add(time.Hour), engine.LevelEasy)
+	if err != nil || again.ID != first.ID {
+		t.Errorf("The same puzzle %d should be returned for the same day, but is: %v (err: %v)", first.ID, again, err)
+	}
+	second, err := scheduler.Puzzle(context.Background(), date.AddDate(0, 0, -1), engine.LevelEasy)
+	if err != nil || second.ID == first.ID || second.Level != engine.LevelEasy {
+		t.Errorf("Other easy puzzle should be picked for the previous day, but is: %+v (err: %v)", second, err)
+	}
+	third, err := scheduler.Puzzle(context.Background(), date.AddDate(0, 0, 1), engine.LevelEasy)
+	if err != nil || third.ID <= 3 || third.Source != Source || third.Level != engine.LevelEasy {
+		t.Errorf("New easy puzzle should be generated, but is: %+v (err: %v)", third, err)
+	}
+	extreme, err := scheduler.Puzzle(context.Background(), date, engine.LevelExtreme)
+	if err != nil || extreme.ID != 3 {
+		t.Errorf("Extreme puzzle 3 should be picked, but is: %+v (err: %v)", extreme, err)
+	}
+	if _, err = scheduler.Puzzle(context.Background(), date, "trivial"); !errors.Is(err, engine.ErrUnknownLevel) {
+		t.Errorf("Puzzle of unknown level should not be picked, but err: %v", err)
+	}
+}
+
+func TestSchedulerDeterministic(t *testing.T) {
+	date := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	var picked []int
+	for i := 0; i < 2; i++ {
+		s := openStore(t)
+		r, err := NewScheduler(s, 1).Puzzle(context.Background(), date, engine.LevelEasy)
+		if err != nil {
+			t.Errorf("Puzzle should be picked, but err: %v", err)
+			return
+		}
+		picked = append(picked, r.ID)
+		// window of 1 day allows to repeat puzzle the next day.
+		s.SetTags(r.ID)
+		s.SetTags(3-r.ID, Tag(date.AddDate(0, 0, 1), engine.LevelEasy))
+		next, err := NewScheduler(s, 1).Puzzle(context.Background(), date.AddDate(0, 0, 2), engine.LevelEasy)
+		if err != nil || next.ID > 2 {
+			t.Errorf("Puzzle published the day before should be picked again, but is: %+v (err: %v)", next, err)
+		}
+		s.Close()
+	}
+	if picked[0] != picked[1] {
+		t.Errorf("The same puzzle should be picked from the same store, but picked: %v", picked)
+	}
+}
+
+func TestTag(t *testing.T) {
+	date := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	if tag := Tag(date, engine.LevelHard); tag != "daily:2021-03-14:hard" {
+		t.Errorf("Tag is %s, but expected: daily:2021-03-14:hard", tag)
+	}
+	if published, ok := tagDate("daily:2021-03-14:hard"); !ok || !published.Equal(date) {
+		t.Errorf("Date of tag is %v, but expected: %v", published, date)
+	}
+	if _, ok := tagDate("classic"); ok {
+		t.Errorf("Tag classic should not have date")
+	}
+	if Seed(date, engine.LevelEasy) == Seed(date, engine.LevelHard) {
+		t.Errorf("Seeds of levels should differ")
+	}
+}
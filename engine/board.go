@@ -0,0 +1,323 @@
+package engine
+
+import (
+	"github.com/chytilp/sudoku/structures"
+)
+
+//layout contains indexes of cells of every unit (rows, columns and squares
+// followed by units of variant, see Geometry.Units) and peers of every cell
+// for one geometry and variant, cells are indexed by rows from 0. Types and
+// names of units are in the same order as units.
+type layout struct {
+	geometry *structures.Geometry
+	size     int
+	// all is mask with all values of the grid.
+	all       uint32
+	units     [][]int
+	unitTypes []structures.UnitType
+	unitNames []string
+	peers     [][]int
+}
+
+//layoutKey identifies layout by grid size and variant.
+type layoutKey struct {
+	size    uint8
+	variant structures.Variant
+}
+
+//layouts contains layout of every supported geometry and variant.
+var layouts = createLayouts()
+
+//cage represents cage of killer sudoku on board - indexes of its cells and sum.
+type cage struct {
+	cells []int
+	sum   int
+}
+
+//board represents game prepared for strategies - values of cells and masks
+// of candidates of empty cells (bit v is set when value v is possible). Cells
+// of killer game have index of their cage in cageOf (-1 for no cage).
+type board struct {
+	*layout
+	values     []uint8
+	candidates []uint32
+	cages      []cage
+	cageOf     []int
+}
+
+//newBoard creates board from the game, candidates are free values restricted
+// by pencil marks (when they are set), by eliminated values and by sums of cages.
+func newBoard(g *structures.Game, eliminated map[int]uint32) *board {
+	l := layoutOf(g.Geometry(), g.Variant())
+	b := board{layout: l, values: make([]uint8, l.size*l.size), candidates: make([]uint32, l.size*l.size),
+		cageOf: make([]int, l.size*l.size)}
+	for idx, char := range g.Line() {
+		b.values[idx], _ = l.geometry.ParseValue(char)
+	}
+	for idx := range b.values {
+		if b.values[idx] != structures.EmptyCellValue {
+			continue
+		}
+		mask := l.all
+		for _, peer := range l.peers[idx] {
+			mask &^= 1 << b.values[peer]
+		}
+		if marks, ok := g.PencilMarks(l.cellID(idx)); ok {
+			mask &= valuesMask(marks)
+		}
+		b.candidates[idx] = mask &^ eliminated[idx]
+	}
+	for idx := range b.cageOf {
+		b.cageOf[idx] = -1
+	}
+	for _, c := range g.Cages() {
+		bc := cage{sum: c.Sum}
+		for _, id := range c.Cells {
+			// cells of cages are validated by game.
+			idx, _ := l.cellIndex(id)
+			bc.cells = append(bc.cells, idx)
+			b.cageOf[idx] = len(b.cages)
+		}
+		b.cages = append(b.cages, bc)
+		for i, possible := range b.sumCandidates(bc.cells, bc.sum) {
+			b.candidates[bc.cells[i]] &= possible
+		}
+	}
+	return &b
+}
+
+//sumCandidates method returns for every cell values which it can have when
+// distinct values giving sum are placed into cells. Filled cells keep their
+// values and empty cells get values from their candidates.
+func (b *board) sumCandidates(cells []int, sum int) []uint32 {
+	options := make([]uint32, len(cells))
+	for i, idx := range cells {
+		options[i] = b.candidates[idx]
+		if b.values[idx] != structures.EmptyCellValue {
+			options[i] = 1 << b.values[idx]
+		}
+	}
+	// layers[i] contains masks of values placed into cells before i, their sum
+	// is not greater than sum.
+	layers := make([]map[uint32]bool, len(cells)+1)
+	layers[0] = map[uint32]bool{0: true}
+	for i := range cells {
+		layers[i+1] = make(map[uint32]bool)
+		for mask := range layers[i] {
+			for rest := options[i] &^ mask; rest != 0; rest &= rest - 1 {
+				if next := mask | rest&^(rest-1); maskSum(next) <= sum {
+					layers[i+1][next] = true
+				}
+			}
+		}
+	}
+	// masks are walked back from masks with exact sum, complete contains masks
+	// which can be completed to them.
+	complete := make(map[uint32]bool)
+	for mask := range layers[len(cells)] {
+		if maskSum(mask) == sum {
+			complete[mask] = true
+		}
+	}
+	result := make([]uint32, len(cells))
+	for i := len(cells) - 1; i >= 0; i-- {
+		previous := make(map[uint32]bool)
+		for mask := range layers[i] {
+			for rest := options[i] &^ mask; rest != 0; rest &= rest - 1 {
+				if bit := rest &^ (rest - 1); complete[mask|bit] {
+					previous[mask] = true
+					result[i] |= bit
+				}
+			}
+		}
+		complete = previous
+	}
+	return result
+}
+
+//contradiction returns if board can not be solved - some empty cell has no
+// candidate, some value has no place in unit or is placed twice in unit or
+// values of some cage can not give its sum.
+func (b *board) contradiction() bool {
+	for _, unit := range b.units {
+		var placed, possible uint32
+		for _, idx := range unit {
+			if b.values[idx] != structures.EmptyCellValue {
+				bit := uint32(1) << b.values[idx]
+				if placed&bit != 0 {
+					return true
+				}
+				placed |= bit
+				continue
+			}
+			if b.candidates[idx] == 0 {
+				return true
+			}
+			possible |= b.candidates[idx]
+		}
+		if placed|possible != b.all {
+			return true
+		}
+	}
+	for _, c := range b.cages {
+		for _, possible := range b.sumCandidates(c.cells, c.sum) {
+			if possible == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//bestCell returns empty cell with lowest number of candidates, -1 when board
+// has no empty cell.
+func (b *board) bestCell() int {
+	best := -1
+	for idx := range b.values {
+		if b.values[idx] != structures.EmptyCellValue {
+			continue
+		}
+		if best == -1 || bitCount(b.candidates[idx]) < bitCount(b.candidates[best]) {
+			best = idx
+		}
+	}
+	return best
+}
+
+//placement creates step which puts value into cell.
+func (b *board) placement(idx int, value uint8, strategy string) *Step {
+	return &Step{
+		CellID:     b.cellID(idx),
+		Value:      value,
+		Candidates: maskValues(b.candidates[idx]),
+		Strategy:   strategy,
+	}
+}
+
+// Package private functions.
+
+func createLayouts() map[layoutKey]*layout {
+	result := make(map[layoutKey]*layout)
+	for _, g := range structures.Geometries() {
+		for _, v := range []structures.Variant{structures.VariantClassic, structures.VariantDiagonal,
+			structures.VariantHyper, structures.VariantDiagonal | structures.VariantHyper} {
+			result[layoutKey{g.Size, v}] = newLayout(g, v)
+		}
+	}
+	return result
+}
+
+//layoutOf returns layout of the geometry with variant.
+func layoutOf(g *structures.Geometry, v structures.Variant) *layout {
+	if l, ok := layouts[layoutKey{g.Size, v}]; ok {
+		return l
+	}
+	return newLayout(g, v)
+}
+
+func newLayout(g *structures.Geometry, v structures.Variant) *layout {
+	n := int(g.Size)
+	l := layout{geometry: g, size: n, all: uint32(1)<<(n+1) - 2}
+	for _, unit := range g.Units(v) {
+		cells := make([]int, len(unit.Cells))
+		for i, id := range unit.Cells {
+			// cells of units are created by geometry.
+			cells[i], _ = l.cellIndex(id)
+		}
+		l.units = append(l.units, cells)
+		l.unitTypes = append(l.unitTypes, unit.Type)
+		l.unitNames = append(l.unitNames, unit.Name())
+	}
+	l.peers = make([][]int, n*n)
+	for idx := range l.peers {
+		seen := map[int]bool{idx: true}
+		for _, unit := range l.units {
+			if !unitContains(unit, idx) {
+				continue
+			}
+			for _, peer := range unit {
+				if !seen[peer] {
+					seen[peer] = true
+					l.peers[idx] = append(l.peers[idx], peer)
+				}
+			}
+		}
+	}
+	return &l
+}
+
+func unitContains(unit []int, idx int) bool {
+	for _, i := range unit {
+		if i == idx {
+			return true
+		}
+	}
+	return false
+}
+
+//cellID method returns id of cell with index (a1 has index 0, i9 index 80 in 9x9 grid).
+func (l *layout) cellID(idx int) string {
+	return l.geometry.CellID(uint8(idx/l.size+1), uint8(idx%l.size+1))
+}
+
+//cellIndex method returns index of cell with id.
+func (l *layout) cellIndex(id string) (int, error) {
+	c, err := l.geometry.NewCell(id, structures.EmptyCellValue)
+	if err != nil {
+		return 0, err
+	}
+	return int(c.Row()-1)*l.size + int(c.Column()-1), nil
+}
+
+//unitName method returns human readable name of unit (row 1, column a, square 1).
+func (l *layout) unitName(u int) string {
+	return l.unitNames[u]
+}
+
+//unitsOf method returns indexes of units of the type in order of units.
+func (l *layout) unitsOf(unitType structures.UnitType) []int {
+	var result []int
+	for u, t := range l.unitTypes {
+		if t == unitType {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+func valuesMask(values []uint8) uint32 {
+	var mask uint32
+	for _, v := range values {
+		mask |= 1 << v
+	}
+	return mask
+}
+
+func maskValues(mask uint32) structures.Values {
+	values := structures.Values{}
+	for v := uint8(1); mask>>v != 0; v++ {
+		if mask&(1<<v) != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+//maskSum returns sum of values of mask.
+func maskSum(mask uint32) int {
+	sum := 0
+	for v := 1; mask>>uint(v) != 0; v++ {
+		if mask&(1<<uint(v)) != 0 {
+			sum += v
+		}
+	}
+	return sum
+}
+
+func bitCount(mask uint32) int {
+	count := 0
+	for ; mask != 0; mask &= mask - 1 {
+		count++
+	}
+	return count
+}
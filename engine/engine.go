@@ -2,65 +2,126 @@ package engine
 
 import (
 	"errors"
-	//"math/rand"
+	"math/rand"
 
 	"github.com/chytilp/sudoku/structures"
 )
 
+//Errors for engine object.
+var (
+	ErrUnsolvable        error = errors.New("Game has no solution")
+	ErrMultipleSolutions error = errors.New("Game has more than one solution")
+	ErrNotFinished       error = errors.New("Game was not finished by logical strategies")
+)
+
 //Engine struct represent engine for solving sudoku game.
 type Engine struct {
-	game *structures.Game
-	p    *Plan
+	game       *structures.Game
+	p          *Plan
+	steps      []Step
+	strategies []Strategy
+	eliminated map[int]uint32
+	guesses    int
+	random     *rand.Rand
+	observers  []Observer
 }
 
 //NewEngine method is Engine object constructor.
 func NewEngine(g *structures.Game) *Engine {
 	e := Engine{
-		game: g,
-		p:    NewPlan(),
+		game:       g,
+		p:          NewPlan(),
+		strategies: Strategies(),
+		eliminated: make(map[int]uint32),
 	}
 	return &e
 }
 
+//Game returns game solved by engine.
+func (e *Engine) Game() *structures.Game {
+	return e.game
+}
+
+//Clone returns copy of engine with copy of its game, eliminated candidates
+// and steps, so the copy can be changed independently. Observers are not copied.
+func (e *Engine) Clone() *Engine {
+	clone := *e
+	s := e.snapshot()
+	clone.game = s.game
+	clone.eliminated = s.eliminated
+	clone.steps = append([]Step{}, e.steps...)
+	clone.strategies = append([]Strategy{}, e.strategies...)
+	clone.observers = nil
+	return &clone
+}
+
+//SetStrategies method sets logical strategies used by engine, they are tried
+// in given order.
+func (e *Engine) SetStrategies(strategies []Strategy) {
+	e.strategies = strategies
+}
+
+//SetRandom method sets random generator used for ordering of guessed values,
+// values are guessed from the lowest one when it is nil.
+func (e *Engine) SetRandom(r *rand.Rand) {
+	e.random = r
+}
+
 //PrintStatus dispalys solution cells of the game.
 func (e *Engine) PrintStatus() {
 	e.game.ShowSolutionCells()
 }
 
-//MakeStep creates one step in solving sudoku game.
+//MakeStep creates one step in solving sudoku game, it returns false when
+// no strategy finds next step.
 func (e *Engine) MakeStep() (*bool, error) {
-	candidates, err := e.SelectBestCandidates()
+	step, err := e.Hint()
 	if err != nil {
+		if errors.Is(err, ErrUnsolvable) {
+			e.notifyContradiction()
+		}
 		return nil, err
 	}
-	result := false
-	for cellID, values := range candidates {
-		if len(values) == 1 {
-			// simplest case, candidates has only best value.
-			cell, _ := structures.NewSolutionCell(cellID, values[0])
-			e.game.AddCell(cell)
-			result = true
-			return &result, nil
+	result := step != nil
+	if result {
+		if err = e.apply(*step); err != nil {
+			return nil, err
 		}
-		//TODO: not implemented yet - cases with len(values) != 1.
 	}
 	return &result, nil
 }
 
-//NextStepCandidates returns proposals for next step (cells and their values)
+//Hint returns next step found by logical strategies without making it, it
+// returns nil when no strategy finds the step.
+func (e *Engine) Hint() (*Step, error) {
+	b := newBoard(e.game, e.eliminated)
+	if b.contradiction() {
+		return nil, ErrUnsolvable
+	}
+	for _, s := range e.strategies {
+		if step := s.find(b); step != nil {
+			return step, nil
+		}
+	}
+	return nil, nil
+}
+
+//Candidates returns candidates of every empty cell - free values restricted by
+// pencil marks of the game and by candidates eliminated by engine.
+func (e *Engine) Candidates() map[string][]uint8 {
+	// board is always built, nextStepCandidates can not fail.
+	candidates, _ := e.nextStepCandidates()
+	return candidates
+}
+
+//nextStepCandidates returns proposals for next step (cells and their values)
 func (e *Engine) nextStepCandidates() (map[string][]uint8, error) {
 	m := make(map[string][]uint8)
-	emptyCells := e.game.EmptyCells()
-	for _, cellID := range emptyCells {
-		cell, err := structures.NewSolutionCell(cellID, 0)
-		if err != nil {
-			return nil, err
+	b := newBoard(e.game, e.eliminated)
+	for idx, value := range b.values {
+		if value == structures.EmptyCellValue {
+			m[b.cellID(idx)] = maskValues(b.candidates[idx])
 		}
-		values, err := e.game.CellFreeValues(cell)
-		if err != nil {
-			return nil, err
-		}
-		m[cellID] = values
 	}
 	return m, nil
 }
@@ -72,7 +133,7 @@ func (e *Engine) SelectBestCandidates() (map[string][]uint8, error) {
 	if err != nil {
 		return nil, err
 	}
-	min := 9
+	min := int(e.game.Geometry().Size)
 	bestCandidates := make(map[string][]uint8)
 	for cellID, vals := range candidates {
 		if len(vals) < min {
@@ -88,37 +149,53 @@ func (e *Engine) SelectBestCandidates() (map[string][]uint8, error) {
 
 //IsFinished returns if game is finished or not.
 func (e *Engine) IsFinished() bool {
-	emptyCells := e.game.EmptyCellCount()
-	//fmt.Printf("empty cells: %d\n", emptyCells)
-	return emptyCells == 0
+	return e.game.EmptyCellCount() == 0
 }
 
-//Run method runs solving sudoku process until it finishes game.
+//Run method runs logical strategies until they finish game, it returns
+// ErrNotFinished when no strategy finds next step.
 func (e *Engine) Run() (*bool, error) {
-	var counter int
-	var result bool
-	//var stepOk *bool
 	for !e.IsFinished() {
-		_, err := e.MakeStep()
+		stepOk, err := e.MakeStep()
 		if err != nil {
 			return nil, err
 		}
-
-		//e.PrintStatus()
-		counter++
-		if counter > 100 {
-			break
+		if !*stepOk {
+			return nil, ErrNotFinished
 		}
 	}
-	if !e.IsFinished() {
-		return nil, errors.New("game was not finished in 100 steps")
-	}
+	result := true
 	return &result, nil
 }
 
-//MakePlan analyzes game and returns Plan of solutions (paths).
+//MakePlan returns Plan of solutions (paths) - guesses made by last Solve or
+// CountSolutions call.
 func (e *Engine) MakePlan() (*Plan, error) {
-	var plan Plan
-	//TODO: create plan
-	return &plan, nil
+	return e.p, nil
+}
+
+//apply method makes step - puts value into cell or eliminates candidates.
+func (e *Engine) apply(step Step) error {
+	if step.IsPlacement() {
+		cell, err := e.game.Geometry().NewSolutionCell(step.CellID, step.Value)
+		if err != nil {
+			return err
+		}
+		if err = e.game.AddCell(cell); err != nil {
+			return err
+		}
+		if step.Strategy == StrategyGuess {
+			e.guesses++
+		}
+	}
+	for _, el := range step.Eliminations {
+		idx, err := layoutOf(e.game.Geometry(), e.game.Variant()).cellIndex(el.CellID)
+		if err != nil {
+			return err
+		}
+		e.eliminated[idx] |= valuesMask(el.Values)
+	}
+	e.steps = append(e.steps, step)
+	e.notifyStep(step)
+	return nil
 }
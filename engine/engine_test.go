@@ -3,6 +3,7 @@ package engine
 import (
 	//"fmt"
 
+	"errors"
 	"reflect"
 	"testing"
 
@@ -134,3 +135,41 @@ func checkGameIsFinished(t *testing.T, engine *Engine) {
 		t.Errorf("Game have %d empty cells, but expected is 0.", emptyCells)
 	}
 }
+
+func TestEngineMakeStepUnsolvable(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	// a1 can have only 8, put 8 into its row.
+	err = g.AddStringCell("b1=8 x")
+	if err != nil {
+		t.Errorf("Solution cell should be added, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	_, err = engine.MakeStep()
+	if !errors.Is(err, ErrUnsolvable) {
+		t.Errorf("Engine.MakeStep should return ErrUnsolvable, but returned: %v", err)
+	}
+}
+
+func TestEngineClone(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	clone := engine.Clone()
+	if _, err = clone.MakeStep(); err != nil {
+		t.Errorf("Engine.MakeStep should pass, but err: %v", err)
+	}
+	if len(engine.Result().Steps) != 0 || engine.Game().EmptyCellCount() != 45 {
+		t.Errorf("Engine should not be changed by step of its clone, steps: %v", engine.Result().Steps)
+	}
+	if len(clone.Result().Steps) != 1 || clone.Game().EmptyCellCount() != 44 {
+		t.Errorf("Clone should make one step, but steps: %v", clone.Result().Steps)
+	}
+	if candidates := clone.Candidates(); len(candidates) != 44 || len(candidates["a1"]) != 0 {
+		t.Errorf("Clone should have candidates of 44 empty cells, but has: %v", candidates)
+	}
+}
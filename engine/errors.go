@@ -0,0 +1,14 @@
+package engine
+
+import (
+	"fmt"
+)
+
+//UnknownStrategyError is returned when strategy with given name does not exist.
+type UnknownStrategyError struct {
+	Name string
+}
+
+func (e *UnknownStrategyError) Error() string {
+	return fmt.Sprintf("unknown strategy %s", e.Name)
+}
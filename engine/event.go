@@ -0,0 +1,81 @@
+package engine
+
+//EventType represents kind of change of engine state.
+type EventType string
+
+//Types of engine events.
+const (
+	//EventPlace is published when value is placed into cell by logical strategy.
+	EventPlace EventType = "place"
+	//EventEliminate is published when candidates are eliminated.
+	EventEliminate EventType = "eliminate"
+	//EventGuess is published when value is guessed, Node is its plan node.
+	EventGuess EventType = "guess"
+	//EventBacktrack is published when guess leads to contradiction or to another
+	// solution and engine returns to untried guess, Node is plan node of the guess.
+	EventBacktrack EventType = "backtrack"
+	//EventContradiction is published when some cell has no candidate, Node is
+	// plan node of the last guess.
+	EventContradiction EventType = "contradiction"
+	//EventFinish is published when game is solved.
+	EventFinish EventType = "finish"
+)
+
+//Event represents change of engine state made by step or by backtracking.
+// EmptyCells is count of empty cells after the change.
+type Event struct {
+	Type       EventType `json:"type"`
+	Step       *Step     `json:"step,omitempty"`
+	Node       string    `json:"node,omitempty"`
+	EmptyCells int       `json:"emptyCells"`
+}
+
+//SetEventHandler method sets function called for every event of engine, it
+// replaces previous handler and nil handler turns events off. Handler is
+// registered as observer, see Observer.
+func (e *Engine) SetEventHandler(handler func(Event)) {
+	observers := make([]Observer, 0, len(e.observers)+1)
+	for _, o := range e.observers {
+		if _, ok := o.(*eventObserver); !ok {
+			observers = append(observers, o)
+		}
+	}
+	if handler != nil {
+		observers = append(observers, &eventObserver{engine: e, handler: handler})
+	}
+	e.observers = observers
+}
+
+//eventObserver converts notifications of engine to events.
+type eventObserver struct {
+	engine  *Engine
+	handler func(Event)
+}
+
+func (o *eventObserver) publish(eventType EventType, step *Step, node string) {
+	o.handler(Event{Type: eventType, Step: step, Node: node, EmptyCells: o.engine.game.EmptyCellCount()})
+}
+
+func (o *eventObserver) OnPlace(step Step) {
+	o.publish(EventPlace, &step, "")
+}
+
+func (o *eventObserver) OnEliminate(step Step) {
+	o.publish(EventEliminate, &step, "")
+}
+
+func (o *eventObserver) OnGuess(step Step, node string) {
+	o.publish(EventGuess, &step, node)
+}
+
+func (o *eventObserver) OnBacktrack(node string) {
+	o.publish(EventBacktrack, nil, node)
+}
+
+func (o *eventObserver) OnContradiction(node string) {
+	o.publish(EventContradiction, nil, node)
+}
+
+func (o *eventObserver) OnFinish(result *Result) {
+	o.publish(EventFinish, nil, "")
+}
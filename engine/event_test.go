@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestEventsOfRun(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	var events []Event
+	engine.SetEventHandler(func(e Event) {
+		events = append(events, e)
+	})
+	if _, err = engine.Run(); err != nil {
+		t.Errorf("Engine.Run should pass, but err: %v", err)
+	}
+	if len(events) != 46 {
+		t.Errorf("Engine should publish 45 place events and finish event, but published: %d", len(events))
+		return
+	}
+	first := events[0]
+	if first.Type != EventPlace || first.Step == nil || first.Step.String() != "a1 = 8 (naked-single)" ||
+		first.EmptyCells != 44 {
+		t.Errorf("First event is %+v, but expected placement of a1 with 44 empty cells", first)
+	}
+	if last := events[45]; last.Type != EventFinish || last.EmptyCells != 0 {
+		t.Errorf("Last event is %+v, but expected finish", last)
+	}
+}
+
+func TestEventsOfSolve(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	counts := make(map[EventType]int)
+	engine.SetEventHandler(func(e Event) {
+		counts[e.Type]++
+		if (e.Type == EventGuess || e.Type == EventBacktrack) && e.Node == "" {
+			t.Errorf("Event %s should contain plan node", e.Type)
+		}
+	})
+	result, err := engine.Solve(context.Background())
+	if err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+		return
+	}
+	if counts[EventGuess] != result.Guesses || counts[EventBacktrack] == 0 || counts[EventFinish] != 1 {
+		t.Errorf("Engine published events %v, but expected %d guesses, backtracks and one finish", counts,
+			result.Guesses)
+	}
+	if counts[EventEliminate] == 0 {
+		t.Errorf("Engine should publish eliminations, but published: %v", counts)
+	}
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Number of attempts to generate game of requested level.
+const generatorAttempts int = 20
+
+//Errors for generator.
+var (
+	ErrGeneratorLevel error = errors.New("Game of requested level was not generated")
+)
+
+//Generate creates new game with unique solution. Random full grid is created
+// first, then its cells are removed in random order while solution stays unique
+// and game is not harder than level. When level is empty, game of any level is
+// returned, otherwise ErrGeneratorLevel is returned when no generated game
+// matches the level.
+func Generate(ctx context.Context, r *rand.Rand, level string) (*structures.Game, error) {
+	return GenerateGeometry(ctx, r, structures.StandardGeometry, level)
+}
+
+//GenerateGeometry creates new game of the grid geometry with unique solution,
+// see Generate.
+func GenerateGeometry(ctx context.Context, r *rand.Rand, geometry *structures.Geometry,
+	level string) (*structures.Game, error) {
+	if level != "" {
+		var err error
+		if level, err = ParseLevel(level); err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 0; attempt < generatorAttempts; attempt++ {
+		g, rating, err := generate(ctx, r, geometry, level)
+		if err != nil {
+			return nil, err
+		}
+		if level == "" || rating.Level == level {
+			return g, nil
+		}
+	}
+	return nil, ErrGeneratorLevel
+}
+
+//generate creates one game with unique solution which is not harder than level.
+func generate(ctx context.Context, r *rand.Rand, geometry *structures.Geometry,
+	level string) (*structures.Game, *Rating, error) {
+	full, err := structures.NewGame(geometry, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	e := NewEngine(full)
+	e.SetRandom(r)
+	if _, err = e.Solve(ctx); err != nil {
+		return nil, nil, err
+	}
+	line := []byte(full.Line())
+	var rating *Rating
+	for _, idx := range r.Perm(len(line)) {
+		value := line[idx]
+		line[idx] = structures.EmptyCellTextValue[0]
+		g, err := structures.ParseLine(string(line))
+		if err != nil {
+			return nil, nil, err
+		}
+		count, err := NewEngine(g.Clone()).CountSolutions(ctx, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count != 1 {
+			line[idx] = value
+			continue
+		}
+		candidate, err := Rate(ctx, g)
+		if err != nil {
+			return nil, nil, err
+		}
+		if level != "" && levelIndex(candidate.Level) > levelIndex(level) {
+			line[idx] = value
+			continue
+		}
+		rating = candidate
+	}
+	g, err := structures.ParseLine(string(line))
+	if err != nil {
+		return nil, nil, err
+	}
+	if rating == nil {
+		// no cell could be removed, full grid is rated.
+		if rating, err = Rate(ctx, g); err != nil {
+			return nil, nil, err
+		}
+	}
+	return g, rating, nil
+}
+
+func levelIndex(level string) int {
+	for idx, l := range Levels() {
+		if l == level {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, level := range []string{LevelEasy, LevelMedium, ""} {
+		g, err := Generate(context.Background(), r, level)
+		if err != nil {
+			t.Errorf("Generate should pass, but err: %v", err)
+			continue
+		}
+		if g.SolutionCellCount() != 0 || g.FilledCellCount() < 17 {
+			t.Errorf("Generated game should have at least 17 givens, but has: %d", g.FilledCellCount())
+		}
+		count, err := NewEngine(g.Clone()).CountSolutions(context.Background(), 2)
+		if err != nil || count != 1 {
+			t.Errorf("Generated game should have unique solution, but has: %d, err: %v", count, err)
+		}
+		rating, err := Rate(context.Background(), g)
+		if err != nil {
+			t.Errorf("Rate should pass, but err: %v", err)
+		}
+		if level != "" && rating.Level != level {
+			t.Errorf("Generated game has level %s, but expected: %s", rating.Level, level)
+		}
+	}
+	if _, err := Generate(context.Background(), r, "impossible"); err != ErrUnknownLevel {
+		t.Errorf("Generate should return ErrUnknownLevel, but err: %v", err)
+	}
+}
+
+func TestGenerateGeometry(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, geometry := range []*structures.Geometry{structures.Geometry4x4, structures.Geometry6x6} {
+		g, err := GenerateGeometry(context.Background(), r, geometry, LevelEasy)
+		if err != nil {
+			t.Errorf("GenerateGeometry should pass, but err: %v", err)
+			continue
+		}
+		if g.Geometry() != geometry {
+			t.Errorf("Generated game is %s, but expected: %s", g.Geometry(), geometry)
+		}
+		count, err := NewEngine(g.Clone()).CountSolutions(context.Background(), 2)
+		if err != nil || count != 1 {
+			t.Errorf("Generated game should have unique solution, but has: %d, err: %v", count, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+)
+
+//Observer is notified about changes of engine state, it allows tracing,
+// metrics and user interfaces to follow solving process. Observers are called
+// synchronously in order of registration, engine waits until they return.
+type Observer interface {
+	//OnPlace is called when value is placed into cell by logical strategy.
+	OnPlace(step Step)
+	//OnEliminate is called when candidates are eliminated by logical strategy.
+	OnEliminate(step Step)
+	//OnGuess is called when value is guessed, node is ID of guess in plan.
+	OnGuess(step Step, node string)
+	//OnBacktrack is called when engine returns to untried guess of plan node.
+	OnBacktrack(node string)
+	//OnContradiction is called when some cell has no candidate, node is ID of
+	// the last guess (empty when no guess was made).
+	OnContradiction(node string)
+	//OnFinish is called when game is solved.
+	OnFinish(result *Result)
+}
+
+//NopObserver implements Observer with empty callbacks, it can be embedded into
+// observers interested only in some callbacks.
+type NopObserver struct{}
+
+//OnPlace does nothing.
+func (NopObserver) OnPlace(step Step) {}
+
+//OnEliminate does nothing.
+func (NopObserver) OnEliminate(step Step) {}
+
+//OnGuess does nothing.
+func (NopObserver) OnGuess(step Step, node string) {}
+
+//OnBacktrack does nothing.
+func (NopObserver) OnBacktrack(node string) {}
+
+//OnContradiction does nothing.
+func (NopObserver) OnContradiction(node string) {}
+
+//OnFinish does nothing.
+func (NopObserver) OnFinish(result *Result) {}
+
+//AddObserver method registers observer of engine.
+func (e *Engine) AddObserver(o Observer) {
+	e.observers = append(e.observers, o)
+}
+
+//RemoveObserver method unregisters observer of engine.
+func (e *Engine) RemoveObserver(o Observer) {
+	observers := make([]Observer, 0, len(e.observers))
+	for _, registered := range e.observers {
+		if registered != o {
+			observers = append(observers, registered)
+		}
+	}
+	e.observers = observers
+}
+
+//currentNode returns ID of current plan node, it is empty when no guess was made.
+func (e *Engine) currentNode() string {
+	if e.p == nil || e.p.current == nil {
+		return ""
+	}
+	return e.p.current.ID
+}
+
+//notifyStep method notifies observers about step made by engine and about
+// finished game.
+func (e *Engine) notifyStep(step Step) {
+	if len(e.observers) == 0 {
+		return
+	}
+	node := e.currentNode()
+	for _, o := range e.observers {
+		switch {
+		case step.Strategy == StrategyGuess:
+			o.OnGuess(step, node)
+		case step.IsPlacement():
+			o.OnPlace(step)
+		default:
+			o.OnEliminate(step)
+		}
+	}
+	if e.IsFinished() {
+		result := e.Result()
+		for _, o := range e.observers {
+			o.OnFinish(result)
+		}
+	}
+}
+
+//notifyBacktrack method notifies observers about return to plan node.
+func (e *Engine) notifyBacktrack(node string) {
+	for _, o := range e.observers {
+		o.OnBacktrack(node)
+	}
+}
+
+//notifyContradiction method notifies observers about contradiction.
+func (e *Engine) notifyContradiction() {
+	node := e.currentNode()
+	for _, o := range e.observers {
+		o.OnContradiction(node)
+	}
+}
+
+//tracer writes every notification as one line.
+type tracer struct {
+	w io.Writer
+}
+
+//NewTracer returns observer which writes solving process to w, e.g.
+// "place a1 = 8 (naked-single)", "guess g8 = 3 (candidates 39, node 1:g8=3)"
+// or "backtrack 1:g8=9".
+func NewTracer(w io.Writer) Observer {
+	return tracer{w}
+}
+
+func (t tracer) OnPlace(step Step) {
+	fmt.Fprintf(t.w, "place %s\n", step)
+}
+
+func (t tracer) OnEliminate(step Step) {
+	fmt.Fprintf(t.w, "eliminate %s\n", step)
+}
+
+func (t tracer) OnGuess(step Step, node string) {
+	fmt.Fprintf(t.w, "guess %s = %d (candidates %s, node %s)\n", step.CellID, step.Value,
+		valuesText(step.Candidates, ""), node)
+}
+
+func (t tracer) OnBacktrack(node string) {
+	fmt.Fprintf(t.w, "backtrack %s\n", node)
+}
+
+func (t tracer) OnContradiction(node string) {
+	if node == "" {
+		fmt.Fprintln(t.w, "contradiction")
+		return
+	}
+	fmt.Fprintf(t.w, "contradiction after %s\n", node)
+}
+
+func (t tracer) OnFinish(result *Result) {
+	fmt.Fprintf(t.w, "finish %d steps, %d guesses\n", len(result.Steps), result.Guesses)
+}
+
+//Metrics is observer which counts notifications, Strategies contains count of
+// steps made by every strategy.
+type Metrics struct {
+	Placements     int            `json:"placements"`
+	Eliminations   int            `json:"eliminations"`
+	Guesses        int            `json:"guesses"`
+	Backtracks     int            `json:"backtracks"`
+	Contradictions int            `json:"contradictions"`
+	Solutions      int            `json:"solutions"`
+	Strategies     map[string]int `json:"strategies"`
+}
+
+//NewMetrics creates Metrics object with zero counts.
+func NewMetrics() *Metrics {
+	return &Metrics{Strategies: make(map[string]int)}
+}
+
+//OnPlace counts placement.
+func (m *Metrics) OnPlace(step Step) {
+	m.Placements++
+	m.Strategies[step.Strategy]++
+}
+
+//OnEliminate counts elimination.
+func (m *Metrics) OnEliminate(step Step) {
+	m.Eliminations++
+	m.Strategies[step.Strategy]++
+}
+
+//OnGuess counts guess.
+func (m *Metrics) OnGuess(step Step, node string) {
+	m.Guesses++
+	m.Strategies[step.Strategy]++
+}
+
+//OnBacktrack counts backtrack.
+func (m *Metrics) OnBacktrack(node string) {
+	m.Backtracks++
+}
+
+//OnContradiction counts contradiction.
+func (m *Metrics) OnContradiction(node string) {
+	m.Contradictions++
+}
+
+//OnFinish counts solution.
+func (m *Metrics) OnFinish(result *Result) {
+	m.Solutions++
+}
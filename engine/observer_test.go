@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//recorder records names of called callbacks.
+type recorder struct {
+	NopObserver
+	calls []string
+}
+
+func (r *recorder) OnPlace(step Step) {
+	r.calls = append(r.calls, "place "+step.CellID)
+}
+
+func (r *recorder) OnFinish(result *Result) {
+	r.calls = append(r.calls, "finish")
+}
+
+func TestObserverOfRun(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	r := &recorder{}
+	removed := &recorder{}
+	engine.AddObserver(r)
+	engine.AddObserver(removed)
+	engine.RemoveObserver(removed)
+	if _, err = engine.Run(); err != nil {
+		t.Errorf("Engine.Run should pass, but err: %v", err)
+	}
+	if len(r.calls) != 46 || r.calls[0] != "place a1" || r.calls[45] != "finish" {
+		t.Errorf("Observer should be called for 45 placements and finish, but calls: %v", r.calls)
+	}
+	if len(removed.calls) != 0 {
+		t.Errorf("Removed observer should not be called, but calls: %v", removed.calls)
+	}
+	if clone := engine.Clone(); len(clone.observers) != 0 {
+		t.Errorf("Clone should not have observers, but has: %v", clone.observers)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	metrics := NewMetrics()
+	engine.AddObserver(metrics)
+	result, err := engine.Solve(context.Background())
+	if err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+		return
+	}
+	if metrics.Guesses != result.Guesses || metrics.Strategies[StrategyGuess] != result.Guesses {
+		t.Errorf("Metrics count %d guesses, but expected: %d", metrics.Guesses, result.Guesses)
+	}
+	if metrics.Contradictions == 0 || metrics.Backtracks == 0 || metrics.Solutions != 1 {
+		t.Errorf("Metrics should count contradictions, backtracks and one solution, but are: %+v", metrics)
+	}
+	if metrics.Placements+metrics.Guesses < 81-22 || metrics.Eliminations == 0 {
+		t.Errorf("Metrics should count placements and eliminations, but are: %+v", metrics)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	var buf bytes.Buffer
+	engine.AddObserver(NewTracer(&buf))
+	if _, err = engine.Solve(context.Background()); err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+	}
+	trace := buf.String()
+	for _, expected := range []string{"guess g8 = 3 (candidates 39, node 1:g8=3)\n", "place i6 = 5 (hidden-single, row 6)\n",
+		"eliminate naked-pair, row 9: ", "contradiction after 4:c3=4\nbacktrack 4:c3=6\n", "finish "} {
+		if !strings.Contains(trace, expected) {
+			t.Errorf("Trace should contain %q, but is: %s", expected, trace)
+		}
+	}
+}
+
+func TestSetEventHandlerReplacesHandler(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	first, second := 0, 0
+	engine.SetEventHandler(func(Event) { first++ })
+	engine.SetEventHandler(func(Event) { second++ })
+	engine.MakeStep()
+	engine.SetEventHandler(nil)
+	engine.MakeStep()
+	if first != 0 || second != 1 || len(engine.observers) != 0 {
+		t.Errorf("Only the last handler should be called once, but calls: %d, %d", first, second)
+	}
+}
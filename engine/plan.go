@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"strings"
+
 	"github.com/chytilp/sudoku/tree"
 )
 
@@ -24,18 +26,25 @@ func NewPlan() *Plan {
 }
 
 //AddNodes method add nodes to plan.
-func (p *Plan) AddNodes(solutionNode string, otherNodes []string) {
+func (p *Plan) AddNodes(solutionNode string, otherNodes []string) error {
 	n := p.createNode(solutionNode, true)
 	parentID := ""
 	if p.current != nil {
 		parentID = p.current.ID
 	}
-	p.solutionTree.AddNode(n, parentID)
+	err := p.solutionTree.AddNode(n, parentID)
+	if err != nil {
+		return err
+	}
 	p.current = n
 	for _, nID := range otherNodes {
 		otherNode := p.createNode(nID, false)
-		p.solutionTree.AddNode(otherNode, parentID)
+		err = p.solutionTree.AddNode(otherNode, parentID)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 //FindNearest method returns nearest undone node.
@@ -64,6 +73,28 @@ func (p *Plan) SetCurrent(node *tree.Node) {
 	p.current = node
 }
 
+//String returns plan as indented tree, children of node are indented under it.
+// Every line contains node ID and its state, current node is marked by *.
+func (p *Plan) String() string {
+	var lines []string
+	var walk func(nodes []*tree.Node, indent string)
+	walk = func(nodes []*tree.Node, indent string) {
+		for _, n := range nodes {
+			line := indent + n.ID
+			if p.isNodeDone(n) {
+				line += " (done)"
+			}
+			if n == p.current {
+				line += " *"
+			}
+			lines = append(lines, line)
+			walk(n.Children, indent+"  ")
+		}
+	}
+	walk(p.solutionTree.RootNodes(), "")
+	return strings.Join(lines, "\n")
+}
+
 func (p *Plan) findNearestRecursive(node *tree.Node) *tree.Node {
 	var children []*tree.Node
 	if node == nil {
@@ -95,12 +126,16 @@ func (p *Plan) createNode(id string, done bool) *tree.Node {
 }
 
 //SetNodesDone method set data to done in certain nodes.
-func (p *Plan) SetNodesDone(doneNodes []string) {
+func (p *Plan) SetNodesDone(doneNodes []string) error {
 	p.setNodesUndoneRecursive(nil)
 	for _, nID := range doneNodes {
 		n := p.FindNode(nID)
+		if n == nil {
+			return &tree.NodeNotFoundError{ID: nID}
+		}
 		n.Data = doneTrue
 	}
+	return nil
 }
 
 func (p *Plan) setNodesUndoneRecursive(node *tree.Node) {
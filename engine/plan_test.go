@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -120,3 +121,30 @@ func getDoneNodeIds(nodes []*tree.Node, ids []string) []string {
 	}
 	return ids
 }
+
+func TestPlanSetNodesDoneUnknownNode(t *testing.T) {
+	p := createPlan()
+	err := p.SetNodesDone([]string{"a", "x"})
+	var notFoundErr *tree.NodeNotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.ID != "x" {
+		t.Errorf("Plan SetNodesDone should return NodeNotFoundError for x, but returned: %v", err)
+	}
+	err = p.AddNodes("b", nil)
+	var duplicateErr *tree.DuplicateNodeError
+	if !errors.As(err, &duplicateErr) {
+		t.Errorf("Plan AddNodes should return DuplicateNodeError, but returned: %v", err)
+	}
+}
+
+func TestPlanString(t *testing.T) {
+	p := NewPlan()
+	p.AddNodes("a", []string{"b"})
+	p.AddNodes("a1", []string{"a2"})
+	expected := "a (done)\n" +
+		"  a1 (done) *\n" +
+		"  a2\n" +
+		"b"
+	if text := p.String(); text != expected {
+		t.Errorf("Plan.String returns: %q, but expected: %q", text, expected)
+	}
+}
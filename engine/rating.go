@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Levels of game difficulty.
+const (
+	LevelEasy    string = "easy"
+	LevelMedium  string = "medium"
+	LevelHard    string = "hard"
+	LevelExpert  string = "expert"
+	LevelExtreme string = "extreme"
+)
+
+//Errors for rating.
+var (
+	ErrUnknownLevel error = errors.New("Unknown level, allowed easy, medium, hard, expert or extreme")
+)
+
+//levels contains level for every score (difficulty of the hardest strategy).
+var levels = []string{LevelEasy, LevelEasy, LevelMedium, LevelHard, LevelExpert, LevelExtreme}
+
+//Rating represents difficulty of the game for human solver. Score is difficulty
+// of the hardest strategy needed to solve the game (1 - 4, 5 when guessing is
+// needed), Strategies contains how many times every strategy was used.
+type Rating struct {
+	Score      int            `json:"score"`
+	Level      string         `json:"level"`
+	Steps      int            `json:"steps"`
+	Guesses    int            `json:"guesses"`
+	Strategies map[string]int `json:"strategies"`
+}
+
+//Levels returns all levels ordered from the easiest one.
+func Levels() []string {
+	return []string{LevelEasy, LevelMedium, LevelHard, LevelExpert, LevelExtreme}
+}
+
+//ParseLevel returns level by its name.
+func ParseLevel(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, level := range Levels() {
+		if level == name {
+			return level, nil
+		}
+	}
+	return "", ErrUnknownLevel
+}
+
+//Rate solves copy of the game by all strategies and rates its difficulty.
+func Rate(ctx context.Context, g *structures.Game) (*Rating, error) {
+	e := NewEngine(g.Clone())
+	result, err := e.Solve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	difficulty := make(map[string]int)
+	for _, s := range allStrategies {
+		difficulty[s.Name()] = s.Difficulty()
+	}
+	difficulty[StrategyGuess] = guessDifficulty
+	rating := Rating{Steps: len(result.Steps), Guesses: result.Guesses, Strategies: make(map[string]int)}
+	for _, step := range result.Steps {
+		rating.Strategies[step.Strategy]++
+		if difficulty[step.Strategy] > rating.Score {
+			rating.Score = difficulty[step.Strategy]
+		}
+	}
+	rating.Level = levels[rating.Score]
+	return &rating, nil
+}
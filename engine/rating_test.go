@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		game     string
+		score    int
+		level    string
+		strategy string
+	}{
+		{game1, 1, LevelEasy, StrategyNakedSingle},
+		{"..92..6.7.1..6......2....1......2.7....3...25.4.6..9.3...59.2..96.....4.....1....", 3, LevelHard,
+			StrategyPointing},
+		{hardGame, 5, LevelExtreme, StrategyGuess},
+	}
+	for _, test := range tests {
+		g, err := structures.Load(strings.NewReader(test.game))
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		rating, err := Rate(context.Background(), g)
+		if err != nil {
+			t.Errorf("Rate should pass, but err: %v", err)
+			continue
+		}
+		if rating.Score != test.score || rating.Level != test.level || rating.Strategies[test.strategy] == 0 {
+			t.Errorf("Game rating is %+v, but expected score %d, level %s with strategy %s", rating,
+				test.score, test.level, test.strategy)
+		}
+		if g.SolutionCellCount() != 0 {
+			t.Error("Rate should not change the game")
+		}
+	}
+}
+
+func TestRateTransformed(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, game := range []string{game1, hardGame} {
+		g, err := structures.Load(strings.NewReader(game))
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		expected, err := Rate(context.Background(), g)
+		if err != nil {
+			t.Errorf("Rate should pass, but err: %v", err)
+			continue
+		}
+		for i := 0; i < 5; i++ {
+			transformed, err := g.Transform(structures.RandomTransform(r))
+			if err != nil {
+				t.Errorf("Game should be transformed, but err: %v", err)
+				continue
+			}
+			rating, err := Rate(context.Background(), transformed)
+			if err != nil || rating.Score != expected.Score || rating.Level != expected.Level {
+				t.Errorf("Rating of transformed game is %+v, but expected: %+v (err: %v)", rating, expected, err)
+			}
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel(" Hard"); err != nil || level != LevelHard {
+		t.Errorf("ParseLevel returns %s, but expected: %s, err: %v", level, LevelHard, err)
+	}
+	if _, err := ParseLevel("impossible"); err != ErrUnknownLevel {
+		t.Errorf("ParseLevel should return ErrUnknownLevel, but err: %v", err)
+	}
+}
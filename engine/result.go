@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Step represents one step made by engine - value placed into cell (with
+// candidates the cell had before the step) or candidates eliminated from cells.
+// Strategy is name of strategy which found the step, Unit describes where it
+// was found (row 1, column a, square 1) when it is important and Cells are
+// cells of the pattern which causes eliminations.
+type Step struct {
+	CellID       string            `json:"cell,omitempty"`
+	Value        uint8             `json:"value,omitempty"`
+	Candidates   structures.Values `json:"candidates,omitempty"`
+	Strategy     string            `json:"strategy"`
+	Unit         string            `json:"unit,omitempty"`
+	Cells        []string          `json:"cells,omitempty"`
+	Eliminations []Elimination     `json:"eliminations,omitempty"`
+}
+
+//Elimination represents candidates removed from one cell.
+type Elimination struct {
+	CellID string            `json:"cell"`
+	Values structures.Values `json:"values"`
+}
+
+//IsPlacement returns if step places value into cell.
+func (s Step) IsPlacement() bool {
+	return s.CellID != ""
+}
+
+//String returns human readable description of the step, e.g.
+// "a1 = 8 (hidden-single, row 1)" or "naked-pair, row 1: c1 -37, d1 -3".
+func (s Step) String() string {
+	source := s.Strategy
+	if s.Unit != "" {
+		source += ", " + s.Unit
+	}
+	if s.IsPlacement() {
+		return fmt.Sprintf("%s = %d (%s)", s.CellID, s.Value, source)
+	}
+	return fmt.Sprintf("%s: %s", source, s.eliminationsText())
+}
+
+//eliminationsText returns removed candidates, e.g. "c1 -37, d1 -3".
+func (s Step) eliminationsText() string {
+	removed := make([]string, len(s.Eliminations))
+	for idx, e := range s.Eliminations {
+		removed[idx] = e.CellID + " -" + valuesText(e.Values, "")
+	}
+	return strings.Join(removed, ", ")
+}
+
+//Explanation returns description of the step in sentences, it explains why
+// the strategy could be used.
+func (s Step) Explanation() string {
+	var reason string
+	switch s.Strategy {
+	case StrategyNakedSingle:
+		reason = fmt.Sprintf("%d is the only candidate of %s.", s.Value, s.CellID)
+	case StrategyHiddenSingle:
+		reason = fmt.Sprintf("%d can be placed only into %s in %s (candidates of %s were %s).", s.Value,
+			s.CellID, s.Unit, s.CellID, valuesText(s.Candidates, " "))
+	case StrategyGuess:
+		reason = fmt.Sprintf("No logical step was found, %d was guessed from candidates %s of %s.", s.Value,
+			valuesText(s.Candidates, " "), s.CellID)
+	case StrategyNakedPair:
+		reason = fmt.Sprintf("Cells %s in %s can contain only values %s, so these values can not be "+
+			"in other cells of %s.", strings.Join(s.Cells, " and "), s.Unit, valuesText(s.eliminated(), " and "),
+			s.Unit)
+	case StrategyPointing:
+		reason = fmt.Sprintf("Value %s can be in %s only in cells %s, they lie in one line, so the value "+
+			"can not be in other cells of the line.", valuesText(s.eliminated(), ""), s.Unit,
+			strings.Join(s.Cells, " "))
+	case StrategyInniesOuties:
+		reason = fmt.Sprintf("Cells %s are %s, the sum follows from the sum of the unit and sums of cages, "+
+			"so they can contain only values of combinations with this sum.", strings.Join(s.Cells, " "), s.Unit)
+	case StrategyXWing:
+		reason = fmt.Sprintf("Value %s can be in %s only in cells %s, they form rectangle, so the value "+
+			"can not be in other cells of its crossing lines.", valuesText(s.eliminated(), ""), s.Unit,
+			strings.Join(s.Cells, " "))
+	default:
+		reason = "Step was found by strategy " + s.Strategy + "."
+	}
+	if s.IsPlacement() {
+		return fmt.Sprintf("%s = %d: %s", s.CellID, s.Value, reason)
+	}
+	return fmt.Sprintf("%s Removed: %s.", reason, s.eliminationsText())
+}
+
+//eliminated returns all values removed by the step.
+func (s Step) eliminated() structures.Values {
+	var mask uint32
+	for _, e := range s.Eliminations {
+		mask |= valuesMask(e.Values)
+	}
+	return maskValues(mask)
+}
+
+func valuesText(values structures.Values, sep string) string {
+	texts := make([]string, len(values))
+	for idx, v := range values {
+		texts[idx] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(texts, sep)
+}
+
+//Result represents state of solving process of the game.
+//
+// JSON representation:
+//
+//	{
+//	  "solved": true,
+//	  "emptyCells": 0,
+//	  "guesses": 0,
+//	  "steps": [{"cell": "a1", "value": 8, "candidates": [8], "strategy": "naked-single"}],
+//	  "game": {...}  // see structures.Game JSON schema
+//	}
+type Result struct {
+	Solved     bool             `json:"solved"`
+	EmptyCells int              `json:"emptyCells"`
+	Guesses    int              `json:"guesses"`
+	Steps      []Step           `json:"steps"`
+	Game       *structures.Game `json:"game"`
+}
+
+//Result returns current result of the solving process.
+func (e *Engine) Result() *Result {
+	steps := make([]Step, len(e.steps))
+	copy(steps, e.steps)
+	return &Result{
+		Solved:     e.IsFinished(),
+		EmptyCells: e.game.EmptyCellCount(),
+		Guesses:    e.guesses,
+		Steps:      steps,
+		Game:       e.game,
+	}
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestResultAfterRun(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	ok, err := engine.Run()
+	if err != nil {
+		t.Errorf("Engine.Run should pass, but err: %v", err)
+	}
+	if !*ok {
+		t.Error("Engine.Run should finish game.")
+	}
+	result := engine.Result()
+	if !result.Solved || result.EmptyCells != 0 {
+		t.Errorf("Result should be solved, but is: %v, empty cells: %d", result.Solved, result.EmptyCells)
+	}
+	if len(result.Steps) != 45 {
+		t.Errorf("Result has %d steps, but expected: %d", len(result.Steps), 45)
+	}
+	for _, step := range result.Steps {
+		if len(step.Candidates) != 1 || step.Candidates[0] != step.Value {
+			t.Errorf("Result step %+v should have only its value as candidate", step)
+		}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Errorf("Result should be marshaled, but err: %v", err)
+	}
+	var decoded Result
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Result should be unmarshaled, but err: %v", err)
+	}
+	if decoded.Game.Line() != g.Line() || len(decoded.Steps) != len(result.Steps) {
+		t.Errorf("Result decoded as %+v, but expected: %+v", decoded, result)
+	}
+}
+
+func TestSolveGameFromJSONWithEmptyCell(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Game should be marshaled, but err: %v", err)
+	}
+	// b1 is empty, cell with value 0 means empty cell.
+	data = []byte(strings.Replace(string(data), `"cells":[`, `"cells":[{"id":"b1","value":0},`, 1))
+	var decoded structures.Game
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+		return
+	}
+	if decoded.EmptyCellCount() != g.EmptyCellCount() || decoded.Line() != hardGame {
+		t.Errorf("Game decoded as %s with %d empty cells, but expected: %s with %d", decoded.Line(),
+			decoded.EmptyCellCount(), hardGame, g.EmptyCellCount())
+	}
+	if _, err = NewEngine(&decoded).Solve(context.Background()); err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+	}
+}
+
+func TestStepExplanation(t *testing.T) {
+	tests := []struct {
+		step     Step
+		expected string
+	}{
+		{Step{CellID: "a1", Value: 8, Candidates: structures.Values{8}, Strategy: StrategyNakedSingle},
+			"a1 = 8: 8 is the only candidate of a1."},
+		{Step{CellID: "i1", Value: 7, Candidates: structures.Values{3, 7}, Strategy: StrategyHiddenSingle,
+			Unit: "row 1"}, "i1 = 7: 7 can be placed only into i1 in row 1 (candidates of i1 were 3 7)."},
+		{Step{Strategy: StrategyNakedPair, Unit: "row 1", Cells: []string{"a1", "b1"},
+			Eliminations: []Elimination{{"c1", structures.Values{1, 2}}, {"d1", structures.Values{2}}}},
+			"Cells a1 and b1 in row 1 can contain only values 1 and 2, so these values can not be in other " +
+				"cells of row 1. Removed: c1 -12, d1 -2."},
+		{Step{Strategy: StrategyPointing, Unit: "square 1", Cells: []string{"a1", "b1"},
+			Eliminations: []Elimination{{"d1", structures.Values{5}}}},
+			"Value 5 can be in square 1 only in cells a1 b1, they lie in one line, so the value can not be " +
+				"in other cells of the line. Removed: d1 -5."},
+		{Step{Strategy: StrategyInniesOuties, Unit: "innies of row 1 (sum 5)", Cells: []string{"i1"},
+			Eliminations: []Elimination{{"i1", structures.Values{1, 2}}}},
+			"Cells i1 are innies of row 1 (sum 5), the sum follows from the sum of the unit and sums of cages, " +
+				"so they can contain only values of combinations with this sum. Removed: i1 -12."},
+	}
+	for _, test := range tests {
+		if text := test.step.Explanation(); text != test.expected {
+			t.Errorf("Step.Explanation returns: %q, but expected: %q", text, test.expected)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//snapshot represents state of engine before guess, engine returns to it when
+// guess leads to contradiction.
+type snapshot struct {
+	game       *structures.Game
+	eliminated map[int]uint32
+	steps      int
+}
+
+//Solve method solves the game by logical strategies, when they do not find
+// next step, engine guesses value of cell with lowest number of candidates.
+// It returns ErrUnsolvable when game has no solution and ctx.Err() when ctx
+// is done before game is solved.
+func (e *Engine) Solve(ctx context.Context) (*Result, error) {
+	solved := false
+	err := e.search(ctx, func() bool {
+		solved = true
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !solved {
+		return nil, ErrUnsolvable
+	}
+	return e.Result(), nil
+}
+
+//SolveUnique method solves the game like Solve, but it checks that game has
+// only one solution. It returns ErrUnsolvable when game has no solution and
+// ErrMultipleSolutions when it has more solutions.
+func (e *Engine) SolveUnique(ctx context.Context) (*Result, error) {
+	count, err := e.CountSolutions(ctx, 2)
+	if err != nil {
+		return nil, err
+	}
+	switch count {
+	case 0:
+		return nil, ErrUnsolvable
+	case 1:
+		return e.Result(), nil
+	}
+	return nil, ErrMultipleSolutions
+}
+
+//CountSolutions method counts solutions of the game, counting stops when
+// limit (if it is > 0) is reached. Game is left in state of last found solution
+// or last contradiction.
+func (e *Engine) CountSolutions(ctx context.Context, limit int) (int, error) {
+	count := 0
+	var last *structures.Game
+	err := e.search(ctx, func() bool {
+		count++
+		last = e.game.Clone()
+		return limit <= 0 || count < limit
+	})
+	if last != nil {
+		*e.game = *last
+	}
+	return count, err
+}
+
+//search method solves the game by strategies and guessing. Guesses are stored
+// as nodes of plan, when contradiction is found, engine backtracks to nearest
+// untried guess. Function found is called for every solution, search continues
+// only when it returns true. Search returns nil when all guesses are tried.
+func (e *Engine) search(ctx context.Context, found func() bool) error {
+	e.p = NewPlan()
+	snapshots := make(map[string]snapshot)
+	guesses := make(map[string]Step)
+	branch := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		stepOk, err := e.MakeStep()
+		if err != nil && !errors.Is(err, ErrUnsolvable) {
+			return err
+		}
+		deadEnd := err != nil
+		if !deadEnd && e.IsFinished() {
+			if !found() {
+				return nil
+			}
+			deadEnd = true
+		}
+		if deadEnd {
+			node := e.p.FindNearest()
+			if node == nil {
+				return nil
+			}
+			parentID := ""
+			if node.Parent != nil {
+				parentID = node.Parent.ID
+			}
+			e.restore(snapshots[parentID])
+			e.notifyBacktrack(node.ID)
+			e.p.SetCurrent(node)
+			if err = e.apply(guesses[node.ID]); err != nil {
+				return err
+			}
+			continue
+		}
+		if *stepOk {
+			continue
+		}
+		// no logical step was found, guess value of the best cell.
+		b := newBoard(e.game, e.eliminated)
+		idx := b.bestCell()
+		values := maskValues(b.candidates[idx])
+		if e.random != nil {
+			e.random.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+		}
+		branch++
+		parentID := ""
+		if e.p.current != nil {
+			parentID = e.p.current.ID
+		}
+		snapshots[parentID] = e.snapshot()
+		ids := make([]string, len(values))
+		for i, value := range values {
+			ids[i] = fmt.Sprintf("%d:%s=%d", branch, b.cellID(idx), value)
+			guesses[ids[i]] = *b.placement(idx, value, StrategyGuess)
+		}
+		if err = e.p.AddNodes(ids[0], ids[1:]); err != nil {
+			return err
+		}
+		if err = e.apply(guesses[ids[0]]); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *Engine) snapshot() snapshot {
+	eliminated := make(map[int]uint32, len(e.eliminated))
+	for idx, mask := range e.eliminated {
+		eliminated[idx] = mask
+	}
+	return snapshot{game: e.game.Clone(), eliminated: eliminated, steps: len(e.steps)}
+}
+
+//restore method returns engine to snapshot, snapshot can be restored repeatedly.
+func (e *Engine) restore(s snapshot) {
+	*e.game = *s.game.Clone()
+	e.eliminated = make(map[int]uint32, len(s.eliminated))
+	for idx, mask := range s.eliminated {
+		e.eliminated[idx] = mask
+	}
+	e.steps = e.steps[:s.steps]
+}
@@ -0,0 +1,256 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+const hardGame string = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+
+func TestEngineSolveWithGuessing(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	result, err := engine.Solve(context.Background())
+	if err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+	}
+	checkGameIsFinished(t, engine)
+	expected := "812753649943682175675491283154237896369845721287169534521974368438526917796318452"
+	if g.Line() != expected {
+		t.Errorf("Game is solved as %s, but expected: %s", g.Line(), expected)
+	}
+	if result.Guesses == 0 || len(result.Steps) < 60 {
+		t.Errorf("Result should have guesses and steps, but is: %d guesses, %d steps", result.Guesses,
+			len(result.Steps))
+	}
+	plan, _ := engine.MakePlan()
+	if plan.FindNearest() == nil {
+		t.Error("Plan should contain guesses made by engine")
+	}
+}
+
+func TestEngineSolveUnsolvable(t *testing.T) {
+	// a1 can not be 2-8 (row 1), 9 and 1 (column a).
+	g, err := structures.ParseLine(".2345678.9........1........" + strings.Repeat(".", 54))
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	_, err = NewEngine(g).Solve(context.Background())
+	if !errors.Is(err, ErrUnsolvable) {
+		t.Errorf("Engine.Solve should return ErrUnsolvable, but err: %v", err)
+	}
+}
+
+func TestEngineSolveCanceled(t *testing.T) {
+	g, err := structures.ParseLine(hardGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = NewEngine(g).Solve(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Engine.Solve should return context.Canceled, but err: %v", err)
+	}
+}
+
+func TestEngineSolveUnique(t *testing.T) {
+	solved := "812753649943682175675491283154237896369845721287169534521974368438526917796318452"
+	tests := []struct {
+		line     string
+		expected error
+	}{
+		{hardGame, nil},
+		// values 2 and 3 in c1, f1, c2, f2 can be swapped.
+		{"81.75.64994.68.175" + solved[18:], ErrMultipleSolutions},
+		{".2345678.9........1........" + strings.Repeat(".", 54), ErrUnsolvable},
+	}
+	for _, test := range tests {
+		g, err := structures.ParseLine(test.line)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		result, err := NewEngine(g).SolveUnique(context.Background())
+		if !errors.Is(err, test.expected) {
+			t.Errorf("Engine.SolveUnique of %s should return %v, but err: %v", test.line, test.expected, err)
+		}
+		if err == nil && (!result.Solved || g.Line() != solved) {
+			t.Errorf("Game should be solved as %s, but is: %s", solved, g.Line())
+		}
+	}
+}
+
+func TestEngineCountSolutions(t *testing.T) {
+	// rows 1-8 of solution, cells of the last row are determined by columns.
+	solved := "812753649943682175675491283154237896369845721287169534521974368438526917"
+	tests := []struct {
+		line     string
+		limit    int
+		expected int
+	}{
+		{hardGame, 0, 1},
+		{solved + "7963184..", 0, 1},
+		{solved + ".........", 0, 1},
+		{".................................................................................", 5, 5},
+	}
+	for _, test := range tests {
+		g, err := structures.ParseLine(test.line)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+		}
+		count, err := NewEngine(g).CountSolutions(context.Background(), test.limit)
+		if err != nil {
+			t.Errorf("Engine.CountSolutions should pass, but err: %v", err)
+		}
+		if count != test.expected {
+			t.Errorf("Game %s has %d solutions, but expected: %d", test.line, count, test.expected)
+		}
+	}
+}
+
+func TestEngineSolveGeometries(t *testing.T) {
+	lines := []string{
+		".......4..61.5....3...2.......2.6.3.",
+		"..74C.BA2....19256.4..C.........14..7...A.......6.2...7.......39...1..42.5..7A.....3A...85....6B.9......8A........6..C..5..6.....1BA.CB.2...3...",
+		".B.....D9....4..E...9.1.....C3AF...4..GE.382.....8.C67.35...1G....2A..6...3....D.F8.C..B24..A..5C.9.3...7.5...F..6.D.G...........DAG5F91C..B......76..4......F..894...2C6...7...1.........E4G..9.....3.6....F.8.F1.8E.A4.D...9.G....2.....9...E6.G.E.8D.....5..B",
+	}
+	for _, line := range lines {
+		g, err := structures.ParseLine(line)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		if _, err = NewEngine(g).Solve(context.Background()); err != nil {
+			t.Errorf("Engine.Solve of %s game should pass, but err: %v", g.Geometry(), err)
+			continue
+		}
+		report, err := g.ValidationReport()
+		if err != nil || g.EmptyCellCount() != 0 || !report.IsValid() {
+			t.Errorf("Game %s should be solved without conflicts, but is: %s", g.Geometry(), g.Line())
+		}
+	}
+}
+
+//killerGame is killer sudoku without givens, its solution is solution of game1.
+const killerGame string = `12: i2 i1
+15: f4 g4 h4 i4
+13: c3 c2
+21: e5 d5 e6 c5
+15: g7 h7 h8
+11: b1 b2
+10: h2 h1
+6: c6 d6
+13: d7 c7 e7
+22: h6 i6 h5 i7
+13: a3 a4
+19: e2 e3 e4
+10: b9 a9
+8: c1 d1
+7: h9 g9
+11: i9 i8
+17: d4 d3
+18: c9 c8 d8
+9: d9 e9
+11: a2 a1
+9: e8 f8
+6: g6 f6
+23: b7 b6 b8 b5
+14: a5 a6 a7 a8
+8: b4 c4
+15: g5 f5
+12: e1 f1 f2 f3
+15: g2 g3 g1 h3
+1: b3
+9: f9
+8: g8
+1: i5
+7: d2
+8: i3
+8: f7
+`
+
+func TestEngineSolveKiller(t *testing.T) {
+	g, err := structures.ReadKiller(strings.NewReader(killerGame))
+	if err != nil {
+		t.Errorf("Killer game should be succesfully created, but err: %v", err)
+		return
+	}
+	count, err := NewEngine(g.Clone()).CountSolutions(context.Background(), 2)
+	if err != nil || count != 1 {
+		t.Errorf("Killer game should have 1 solution, but has %d (err: %v)", count, err)
+	}
+	solution, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	if _, err = NewEngine(solution).Solve(context.Background()); err != nil {
+		t.Errorf("Engine.Solve should pass, but err: %v", err)
+	}
+	if _, err = NewEngine(g).Solve(context.Background()); err != nil {
+		t.Errorf("Engine.Solve of killer game should pass, but err: %v", err)
+	}
+	if g.Line() != solution.Line() {
+		t.Errorf("Killer game is solved as %s, but expected: %s", g.Line(), solution.Line())
+	}
+	if len(g.Cages()) != 35 {
+		t.Errorf("Solved killer game should keep its cages, but has: %d", len(g.Cages()))
+	}
+}
+
+func TestEngineSolveKillerUnsolvable(t *testing.T) {
+	g, err := structures.ReadKiller(strings.NewReader(strings.Repeat(".", 80) + "3\n3: h9 i9\n"))
+	if err != nil {
+		t.Errorf("Killer game should be succesfully created, but err: %v", err)
+		return
+	}
+	if _, err = NewEngine(g).Hint(); !errors.Is(err, ErrUnsolvable) {
+		t.Errorf("Engine.Hint should return ErrUnsolvable, but returned: %v", err)
+	}
+}
+
+func TestEngineSolveVariants(t *testing.T) {
+	tests := []struct {
+		variant  structures.Variant
+		line     string
+		expected string
+	}{
+		{structures.VariantDiagonal,
+			"..4.2........873.4...........5.......3....1..........9.42......19....7.....7.3...",
+			"384621597926587314517439862875914236439256178261378459742195683193862745658743921"},
+		{structures.VariantHyper,
+			".8..2.5.....4...38..7.3......8.....2...2....1..9......6.1.....3.......1.......7..",
+			"384621597926475138517938426138546972475293861269817354641759283752384619893162745"},
+	}
+	for _, test := range tests {
+		g, err := structures.ParseLine(test.line)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		// the game has more solutions without units of its variant.
+		if count, _ := NewEngine(g.Clone()).CountSolutions(context.Background(), 2); count != 2 {
+			t.Errorf("Classic game should have 2 solutions, but has: %d", count)
+		}
+		g.SetVariant(test.variant)
+		if count, _ := NewEngine(g.Clone()).CountSolutions(context.Background(), 2); count != 1 {
+			t.Errorf("Game of %s variant should have unique solution, but has: %d", test.variant, count)
+		}
+		engine := NewEngine(g)
+		if _, err = engine.Solve(context.Background()); err != nil {
+			t.Errorf("Engine.Solve should pass, but err: %v", err)
+		}
+		checkGameIsFinished(t, engine)
+		if g.Line() != test.expected {
+			t.Errorf("Game of %s variant is solved as %s, but expected: %s", test.variant, g.Line(),
+				test.expected)
+		}
+	}
+}
@@ -0,0 +1,447 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Names of strategies.
+const (
+	StrategyNakedSingle  string = "naked-single"
+	StrategyHiddenSingle string = "hidden-single"
+	StrategyNakedPair    string = "naked-pair"
+	StrategyPointing     string = "pointing"
+	StrategyInniesOuties string = "innies-outies"
+	StrategyXWing        string = "x-wing"
+	StrategyGuess        string = "guess"
+)
+
+//Difficulty of guessing, it is harder than any logical strategy.
+const guessDifficulty int = 5
+
+//Strategy represents logical technique which finds next step of solving process.
+type Strategy interface {
+	//Name returns name of the strategy.
+	Name() string
+	//Difficulty returns how hard is the strategy for human solver (1 - 4).
+	Difficulty() int
+	//find returns next step found by the strategy or nil.
+	find(b *board) *Step
+}
+
+//allStrategies contains all strategies ordered from the easiest one.
+var allStrategies = []Strategy{nakedSingle{}, hiddenSingle{}, nakedPair{}, pointing{}, inniesOuties{}, xWing{}}
+
+//Strategies returns all strategies ordered from the easiest one.
+func Strategies() []Strategy {
+	return append([]Strategy{}, allStrategies...)
+}
+
+//ParseStrategies returns strategies from comma separated list of their names
+// (case and dashes are ignored), strategies are ordered from the easiest one.
+func ParseStrategies(names string) ([]Strategy, error) {
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !isStrategy(name) {
+			return nil, &UnknownStrategyError{Name: name}
+		}
+		selected[normalizeName(name)] = true
+	}
+	var result []Strategy
+	for _, s := range allStrategies {
+		if selected[normalizeName(s.Name())] {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func isStrategy(name string) bool {
+	for _, s := range allStrategies {
+		if normalizeName(s.Name()) == normalizeName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+//normalizeName returns strategy name in lower case without dashes, so xwing,
+// XWing and x-wing are the same strategy.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Replace(name, "-", "", -1))
+}
+
+//nakedSingle finds empty cell with only one candidate.
+type nakedSingle struct{}
+
+func (nakedSingle) Name() string {
+	return StrategyNakedSingle
+}
+
+func (nakedSingle) Difficulty() int {
+	return 1
+}
+
+func (s nakedSingle) find(b *board) *Step {
+	for idx, mask := range b.candidates {
+		if b.values[idx] == structures.EmptyCellValue && bitCount(mask) == 1 {
+			return b.placement(idx, maskValues(mask)[0], s.Name())
+		}
+	}
+	return nil
+}
+
+//hiddenSingle finds value which can be placed only into one cell of unit.
+type hiddenSingle struct{}
+
+func (hiddenSingle) Name() string {
+	return StrategyHiddenSingle
+}
+
+func (hiddenSingle) Difficulty() int {
+	return 2
+}
+
+func (s hiddenSingle) find(b *board) *Step {
+	for u, unit := range b.units {
+		for v := uint8(1); int(v) <= b.size; v++ {
+			found := -1
+			count := 0
+			for _, idx := range unit {
+				if b.candidates[idx]&(1<<v) != 0 {
+					found = idx
+					count++
+				}
+			}
+			if count == 1 {
+				step := b.placement(found, v, s.Name())
+				step.Unit = b.unitName(u)
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+//nakedPair finds two cells of unit with the same two candidates, these values
+// are removed from other cells of the unit.
+type nakedPair struct{}
+
+func (nakedPair) Name() string {
+	return StrategyNakedPair
+}
+
+func (nakedPair) Difficulty() int {
+	return 3
+}
+
+func (s nakedPair) find(b *board) *Step {
+	for u, unit := range b.units {
+		for i, first := range unit {
+			mask := b.candidates[first]
+			if bitCount(mask) != 2 {
+				continue
+			}
+			for _, second := range unit[i+1:] {
+				if b.candidates[second] != mask {
+					continue
+				}
+				var others []int
+				for _, idx := range unit {
+					if idx != first && idx != second {
+						others = append(others, idx)
+					}
+				}
+				step := b.elimination(others, mask, s.Name(), b.unitName(u), []int{first, second})
+				if step != nil {
+					return step
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//pointing finds value whose candidates in box (square or window) lie in one
+// line (row, column or diagonal), the value is removed from other cells of the line.
+type pointing struct{}
+
+func (pointing) Name() string {
+	return StrategyPointing
+}
+
+func (pointing) Difficulty() int {
+	return 3
+}
+
+func (s pointing) find(b *board) *Step {
+	for u, unit := range b.units {
+		if !isBox(b.unitTypes[u]) {
+			continue
+		}
+		for v := uint8(1); int(v) <= b.size; v++ {
+			var cells []int
+			for _, idx := range unit {
+				if b.candidates[idx]&(1<<v) != 0 {
+					cells = append(cells, idx)
+				}
+			}
+			if len(cells) < 2 {
+				continue
+			}
+			for line, lineCells := range b.units {
+				if !isLine(b.unitTypes[line]) || !allInUnit(cells, lineCells) {
+					continue
+				}
+				var others []int
+				for _, idx := range lineCells {
+					if !unitContains(unit, idx) {
+						others = append(others, idx)
+					}
+				}
+				if step := b.elimination(others, 1<<v, s.Name(), b.unitName(u), cells); step != nil {
+					return step
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//inniesOuties finds cells of unit which are not in cages lying whole in the
+// unit (innies) in killer game, their sum is sum of the unit without sums of
+// these cages. When innies are in cages which cross the unit, cells of these
+// cages outside the unit (outies) have known sum too. Candidates of innies and
+// outies are restricted to values of combinations with their sum.
+type inniesOuties struct{}
+
+func (inniesOuties) Name() string {
+	return StrategyInniesOuties
+}
+
+func (inniesOuties) Difficulty() int {
+	return 3
+}
+
+func (s inniesOuties) find(b *board) *Step {
+	if len(b.cages) == 0 {
+		return nil
+	}
+	for u, unit := range b.units {
+		innies, sum, inside := b.innies(unit)
+		if len(innies) == 0 {
+			continue
+		}
+		if inside > 0 {
+			name := fmt.Sprintf("innies of %s (sum %d)", b.unitName(u), sum)
+			if step := b.restriction(innies, b.sumCandidates(innies, sum), s.Name(), name); step != nil {
+				return step
+			}
+		}
+		outies, outiesSum, ok := b.outies(unit, innies, sum)
+		if !ok || !b.distinct(outies) {
+			continue
+		}
+		name := fmt.Sprintf("outies of %s (sum %d)", b.unitName(u), outiesSum)
+		if step := b.restriction(outies, b.sumCandidates(outies, outiesSum), s.Name(), name); step != nil {
+			return step
+		}
+	}
+	return nil
+}
+
+//innies method returns cells of unit which are not in cages lying whole in the
+// unit, their sum and count of such cages.
+func (b *board) innies(unit []int) ([]int, int, int) {
+	sum := b.size * (b.size + 1) / 2
+	count := 0
+	inside := make(map[int]bool)
+	for _, c := range b.cages {
+		if !allInUnit(c.cells, unit) {
+			continue
+		}
+		count++
+		sum -= c.sum
+		for _, idx := range c.cells {
+			inside[idx] = true
+		}
+	}
+	var cells []int
+	for _, idx := range unit {
+		if !inside[idx] {
+			cells = append(cells, idx)
+		}
+	}
+	return cells, sum, count
+}
+
+//outies method returns cells outside of unit which are in cages of innies and
+// their sum, sum is sum of innies. It returns false when some innie is in no cage.
+func (b *board) outies(unit []int, innies []int, sum int) ([]int, int, bool) {
+	crossing := make(map[int]bool)
+	for _, idx := range innies {
+		if b.cageOf[idx] < 0 {
+			return nil, 0, false
+		}
+		crossing[b.cageOf[idx]] = true
+	}
+	var cells []int
+	outiesSum := -sum
+	for ci, c := range b.cages {
+		if !crossing[ci] {
+			continue
+		}
+		outiesSum += c.sum
+		for _, idx := range c.cells {
+			if !unitContains(unit, idx) {
+				cells = append(cells, idx)
+			}
+		}
+	}
+	return cells, outiesSum, true
+}
+
+//distinct method returns if cells must have distinct values - there is only
+// one cell or all cells are in one unit.
+func (b *board) distinct(cells []int) bool {
+	if len(cells) == 1 {
+		return true
+	}
+	for _, unit := range b.units {
+		if allInUnit(cells, unit) {
+			return true
+		}
+	}
+	return false
+}
+
+//xWing finds value which has in two rows (columns) candidates only in the same
+// two columns (rows), the value is removed from other cells of these columns (rows).
+type xWing struct{}
+
+func (xWing) Name() string {
+	return StrategyXWing
+}
+
+func (xWing) Difficulty() int {
+	return 4
+}
+
+func (s xWing) find(b *board) *Step {
+	rows, columns := b.unitsOf(structures.UnitRow), b.unitsOf(structures.UnitColumn)
+	for v := uint8(1); int(v) <= b.size; v++ {
+		for _, lines := range [][2][]int{{rows, columns}, {columns, rows}} {
+			base, crossing := lines[0], lines[1]
+			// positions of value candidates in every row (column) as mask.
+			positions := make([]uint32, b.size)
+			for i := 0; i < b.size; i++ {
+				for j, idx := range b.units[base[i]] {
+					if b.candidates[idx]&(1<<v) != 0 {
+						positions[i] |= 1 << j
+					}
+				}
+			}
+			for i := 0; i < b.size; i++ {
+				if bitCount(positions[i]) != 2 {
+					continue
+				}
+				for k := i + 1; k < b.size; k++ {
+					if positions[k] != positions[i] {
+						continue
+					}
+					var others []int
+					for j := 0; j < b.size; j++ {
+						if positions[i]&(1<<j) == 0 {
+							continue
+						}
+						for _, idx := range b.units[crossing[j]] {
+							if !unitContains(b.units[base[i]], idx) && !unitContains(b.units[base[k]], idx) {
+								others = append(others, idx)
+							}
+						}
+					}
+					var corners []int
+					for _, line := range []int{base[i], base[k]} {
+						for j, idx := range b.units[line] {
+							if positions[i]&(1<<j) != 0 {
+								corners = append(corners, idx)
+							}
+						}
+					}
+					unit := b.unitName(base[i]) + ", " + b.unitName(base[k])
+					if step := b.elimination(others, 1<<v, s.Name(), unit, corners); step != nil {
+						return step
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//elimination creates step which removes values of mask from candidates of cells,
+// pattern contains cells which cause the elimination. It returns nil when no
+// candidate is removed.
+func (b *board) elimination(cells []int, mask uint32, strategy string, unit string, pattern []int) *Step {
+	var eliminations []Elimination
+	for _, idx := range cells {
+		if removed := b.candidates[idx] & mask; removed != 0 {
+			eliminations = append(eliminations, Elimination{CellID: b.cellID(idx), Values: maskValues(removed)})
+		}
+	}
+	if len(eliminations) == 0 {
+		return nil
+	}
+	ids := make([]string, len(pattern))
+	for i, idx := range pattern {
+		ids[i] = b.cellID(idx)
+	}
+	return &Step{Strategy: strategy, Unit: unit, Cells: ids, Eliminations: eliminations}
+}
+
+//restriction creates step which keeps in candidates of empty cells only values
+// of possible masks, cells are also pattern of the step. It returns nil when no
+// candidate is removed.
+func (b *board) restriction(cells []int, possible []uint32, strategy string, unit string) *Step {
+	var eliminations []Elimination
+	ids := make([]string, len(cells))
+	for i, idx := range cells {
+		ids[i] = b.cellID(idx)
+		if b.values[idx] != structures.EmptyCellValue {
+			continue
+		}
+		if removed := b.candidates[idx] &^ possible[i]; removed != 0 {
+			eliminations = append(eliminations, Elimination{CellID: ids[i], Values: maskValues(removed)})
+		}
+	}
+	if len(eliminations) == 0 {
+		return nil
+	}
+	return &Step{Strategy: strategy, Unit: unit, Cells: ids, Eliminations: eliminations}
+}
+
+func allInUnit(cells []int, unit []int) bool {
+	for _, idx := range cells {
+		if !unitContains(unit, idx) {
+			return false
+		}
+	}
+	return true
+}
+
+//isBox returns if units of the type are boxes (squares and windows).
+func isBox(unitType structures.UnitType) bool {
+	return unitType == structures.UnitSquare || unitType == structures.UnitWindow
+}
+
+//isLine returns if units of the type are lines (rows, columns and diagonals).
+func isLine(unitType structures.UnitType) bool {
+	return unitType == structures.UnitRow || unitType == structures.UnitColumn ||
+		unitType == structures.UnitDiagonal
+}
@@ -0,0 +1,187 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//fullBoard creates board with empty cells which have all candidates.
+func fullBoard() *board {
+	// game without cells can always be created.
+	g, _ := structures.NewGameFromCells(nil)
+	return newBoard(g, nil)
+}
+
+func TestStrategyHiddenSingle(t *testing.T) {
+	g, err := structures.NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	engine.SetStrategies([]Strategy{hiddenSingle{}})
+	step, err := engine.Hint()
+	if err != nil {
+		t.Errorf("Engine.Hint should pass, but err: %v", err)
+	}
+	expected := "i1 = 7 (hidden-single, row 1)"
+	if step == nil || step.String() != expected {
+		t.Errorf("Engine.Hint returns %v, but expected: %s", step, expected)
+	}
+}
+
+func TestStrategyEliminations(t *testing.T) {
+	pair := fullBoard()
+	pair.candidates[0] = valuesMask([]uint8{1, 2})
+	pair.candidates[1] = valuesMask([]uint8{1, 2})
+	point := fullBoard()
+	for _, idx := range []int{2, 9, 10, 11, 18, 19, 20} {
+		point.candidates[idx] &^= 1 << 5
+	}
+	wing := fullBoard()
+	for _, row := range []int{0, 4} {
+		for col := 0; col < 9; col++ {
+			if col != 1 && col != 5 {
+				wing.candidates[row*9+col] &^= 1 << 3
+			}
+		}
+	}
+	tests := []struct {
+		strategy Strategy
+		b        *board
+		expected string
+		removed  int
+	}{
+		{nakedPair{}, pair, "naked-pair, row 1: c1 -12", 7},
+		{pointing{}, point, "pointing, square 1: d1 -5", 6},
+		{xWing{}, wing, "x-wing, row 1, row 5: b2 -3", 14},
+	}
+	for _, test := range tests {
+		step := test.strategy.find(test.b)
+		if step == nil {
+			t.Errorf("Strategy %s should find step", test.strategy.Name())
+			continue
+		}
+		if step.IsPlacement() || len(step.Eliminations) != test.removed {
+			t.Errorf("Strategy %s step %v should remove %d candidates", test.strategy.Name(), step,
+				test.removed)
+		}
+		step.Eliminations = step.Eliminations[:1]
+		if step.String() != test.expected {
+			t.Errorf("Strategy %s step is %s, but expected: %s", test.strategy.Name(), step, test.expected)
+		}
+	}
+}
+
+func TestStrategyEliminationsAreApplied(t *testing.T) {
+	g, err := structures.NewGameFromCells(nil)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	engine := NewEngine(g)
+	err = engine.apply(Step{Strategy: StrategyNakedPair, Eliminations: []Elimination{{"c1", structures.Values{1, 2}}}})
+	if err != nil {
+		t.Errorf("Engine.apply should pass, but err: %v", err)
+	}
+	candidates, _ := engine.nextStepCandidates()
+	if !reflect.DeepEqual(candidates["c1"], []uint8{3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("Cell c1 candidates are %v, eliminated values should be removed", candidates["c1"])
+	}
+}
+
+func TestParseStrategies(t *testing.T) {
+	strategies, err := ParseStrategies("x-wing, naked-single,,hidden-single")
+	if err != nil {
+		t.Errorf("ParseStrategies should pass, but err: %v", err)
+	}
+	var names []string
+	for _, s := range strategies {
+		names = append(names, s.Name())
+	}
+	expected := []string{StrategyNakedSingle, StrategyHiddenSingle, StrategyXWing}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("ParseStrategies returns %v, but expected: %v", names, expected)
+	}
+	strategies, err = ParseStrategies("XWing,nakedpair")
+	if err != nil || len(strategies) != 2 || strategies[0].Name() != StrategyNakedPair ||
+		strategies[1].Name() != StrategyXWing {
+		t.Errorf("ParseStrategies should ignore case and dashes, but returns: %v, err: %v", strategies, err)
+	}
+	_, err = ParseStrategies("naked-single,swordfish")
+	var unknown *UnknownStrategyError
+	if !errors.As(err, &unknown) || unknown.Name != "swordfish" {
+		t.Errorf("ParseStrategies should return UnknownStrategyError, but err: %v", err)
+	}
+}
+
+func TestStrategyInniesOuties(t *testing.T) {
+	innies, err := structures.NewGameFromCells(nil)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	outies := innies.Clone()
+	cages := []struct {
+		g     *structures.Game
+		sum   int
+		cells []string
+	}{
+		{innies, 10, []string{"a1", "b1", "c1", "d1"}},
+		{innies, 30, []string{"e1", "f1", "g1", "h1"}},
+		{outies, 35, []string{"a1", "b1", "c1", "d1", "e1", "f1", "g1"}},
+		{outies, 9, []string{"h1", "h2"}},
+		{outies, 11, []string{"i1", "i2"}},
+	}
+	for _, c := range cages {
+		if err = c.g.AddCage(&structures.Cage{Sum: c.sum, Cells: c.cells}); err != nil {
+			t.Errorf("Game.AddCage should pass, but err: %v", err)
+		}
+	}
+	tests := []struct {
+		b        *board
+		expected string
+	}{
+		{newBoard(innies, nil), "innies-outies, innies of row 1 (sum 5): i1 -12346789"},
+		// 5 is eliminated from innies h1 and i1, so the pair h2 and i2 can not contain 5.
+		{newBoard(outies, map[int]uint32{7: 1 << 5, 8: 1 << 5}),
+			"innies-outies, outies of row 1 (sum 10): h2 -5, i2 -5"},
+	}
+	for _, test := range tests {
+		step := inniesOuties{}.find(test.b)
+		if step == nil || step.String() != test.expected {
+			t.Errorf("Strategy innies-outies step is %v, but expected: %s", step, test.expected)
+		}
+	}
+	if step := (inniesOuties{}).find(fullBoard()); step != nil {
+		t.Errorf("Strategy innies-outies should not find step in game without cages, but found: %v", step)
+	}
+}
+
+func TestStrategyVariantUnits(t *testing.T) {
+	g, _ := structures.NewGameFromCells(nil)
+	g.SetVariant(structures.VariantDiagonal | structures.VariantHyper)
+	// 7 is only in c3 of window 1 (b2 - d4).
+	single := newBoard(g, nil)
+	for _, idx := range []int{10, 11, 12, 19, 21, 28, 29, 30} {
+		single.candidates[idx] &^= 1 << 7
+	}
+	// 5 is only in a1 and b2 of square 1, they lie in diagonal 1.
+	point := newBoard(g, nil)
+	for _, idx := range []int{1, 2, 9, 11, 18, 19, 20} {
+		point.candidates[idx] &^= 1 << 5
+	}
+	expected := "c3 = 7 (hidden-single, window 1)"
+	if step := (hiddenSingle{}).find(single); step == nil || step.String() != expected {
+		t.Errorf("Strategy hidden-single step is %v, but expected: %s", step, expected)
+	}
+	step := pointing{}.find(point)
+	if step == nil || len(step.Eliminations) != 6 || step.Eliminations[0].CellID != "d4" {
+		t.Errorf("Strategy pointing step is %v, but expected elimination of 5 from d4 - i9", step)
+	}
+	// 20 peers of classic game and 12 cells of diagonals outside square 5.
+	if len(single.peers[40]) != 32 {
+		t.Errorf("Cell e5 has %d peers, but expected: 32", len(single.peers[40]))
+	}
+}
@@ -1,9 +1,340 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/render"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Exit codes of sudoku command.
+const (
+	exitOk      int = 0
+	exitFailure int = 1 // game is invalid, unsolvable or has more solutions
+	exitUsage   int = 2
+	exitError   int = 3 // input can not be read or parsed, output can not be written
+	exitTimeout int = 4
 )
 
+//stdinPath is file name which means standard input.
+const stdinPath string = "-"
+
+//command represents one subcommand of sudoku command.
+type command struct {
+	name    string
+	summary string
+	run     func(env *environment, args []string) int
+}
+
+//environment represents standard input and outputs of sudoku command.
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+//options represents flags shared by subcommands.
+type options struct {
+	format     string
+	output     string
+	strategies string
+	timeout    time.Duration
+}
+
 func main() {
-	fmt.Println("call of sudoku solver will be here.")
+	os.Exit(run(os.Args[1:], &environment{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+//commands returns all subcommands.
+func commands() []command {
+	return []command{
+		{"solve", "solve games and write their solutions", runSolve},
+		{"validate", "check that games are valid and have unique solution", runValidate},
+		{"rate", "rate difficulty of games", runRate},
+		{"hint", "show next logical step of games", runHint},
+		{"generate", "generate new games", runGenerate},
+		{"count", "count solutions of games", runCount},
+		{"convert", "convert games to another format", runConvert},
+		{"booklet", "write games and their solutions as PDF booklet", runBooklet},
+		{"export", "draw game as SVG or PNG image or HTML page", runExport},
+		{"transform", "rotate, mirror or randomly transform games", runTransform},
+		{"play", "play game in terminal (needs stty command)", runPlay},
+		{"repl", "inspect game and solver state by commands", runRepl},
+		{"serve", "run HTTP server with REST API", runServe},
+		{"import", "add games to puzzle library", runImport},
+		{"query", "find games in puzzle library", runQuery},
+		{"daily", "pick daily games of every level", runDaily},
+	}
+}
+
+//run runs subcommand given by args and returns exit code.
+func run(args []string, env *environment) int {
+	if len(args) == 0 {
+		usage(env.stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(env.stdout)
+		return exitOk
+	}
+	for _, c := range commands() {
+		if c.name == args[0] {
+			return c.run(env, args[1:])
+		}
+	}
+	fmt.Fprintf(env.stderr, "sudoku: unknown command %s\n", args[0])
+	usage(env.stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: sudoku <command> [flags] [file...]")
+	fmt.Fprintln(w, "\nGames are read from files or from standard input (no file or -).\n\nCommands:")
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nRun sudoku <command> -h for flags of the command.")
+}
+
+//newFlagSet creates flag set of subcommand with shared flags, flags which are
+// not used by the subcommand are not registered.
+func newFlagSet(env *environment, name string, opts *options, shared ...string) *flag.FlagSet {
+	fs := flag.NewFlagSet("sudoku "+name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	formats := make([]string, 0, len(structures.Formats()))
+	for _, f := range structures.Formats() {
+		formats = append(formats, string(f))
+	}
+	for _, flagName := range shared {
+		switch flagName {
+		case "format":
+			fs.StringVar(&opts.format, "format", "", "input format, detected when empty ("+
+				strings.Join(formats, ", ")+")")
+		case "output":
+			fs.StringVar(&opts.output, "output", "", "output format, input format when empty")
+		case "strategies":
+			fs.StringVar(&opts.strategies, "strategies", "", "comma separated strategies, all when empty ("+
+				strategyNames()+")")
+		case "timeout":
+			fs.DurationVar(&opts.timeout, "timeout", 0, "time limit for one game, e.g. 10s (no limit when 0)")
+		}
+	}
+	return fs
+}
+
+//parseFlags parses flags of subcommand, it returns exit code and false when
+// subcommand should not continue.
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOk, false
+		}
+		return exitUsage, false
+	}
+	return exitOk, true
+}
+
+func strategyNames() string {
+	var names []string
+	for _, s := range engine.Strategies() {
+		names = append(names, s.Name())
+	}
+	return strings.Join(append(names, engine.StrategyGuess), ", ")
+}
+
+//engineStrategies returns logical strategies selected by --strategies flag and
+// if guessing is allowed.
+func (o *options) engineStrategies() ([]engine.Strategy, bool, error) {
+	if strings.TrimSpace(o.strategies) == "" {
+		return engine.Strategies(), true, nil
+	}
+	guess := false
+	var names []string
+	for _, name := range strings.Split(o.strategies, ",") {
+		if strings.TrimSpace(name) == engine.StrategyGuess {
+			guess = true
+			continue
+		}
+		names = append(names, name)
+	}
+	strategies, err := engine.ParseStrategies(strings.Join(names, ","))
+	return strategies, guess, err
+}
+
+//outputFormat returns format given by --output flag or fallback when flag is empty.
+func (o *options) outputFormat(fallback structures.Format) (structures.Format, error) {
+	if o.output == "" {
+		return fallback, nil
+	}
+	return structures.ParseFormat(o.output)
+}
+
+//context returns context limited by --timeout flag.
+func (o *options) context() (context.Context, context.CancelFunc) {
+	if o.timeout > 0 {
+		return context.WithTimeout(context.Background(), o.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+//readPuzzles reads puzzles from files (standard input when paths are empty),
+// it returns puzzles and format of the first file.
+func readPuzzles(env *environment, paths []string, format string) ([]*structures.Puzzle, structures.Format, error) {
+	var inputFormat structures.Format
+	if format != "" {
+		f, err := structures.ParseFormat(format)
+		if err != nil {
+			return nil, "", err
+		}
+		inputFormat = f
+	}
+	if len(paths) == 0 {
+		paths = []string{stdinPath}
+	}
+	var result []*structures.Puzzle
+	var firstFormat structures.Format
+	for _, path := range paths {
+		var data []byte
+		var err error
+		if path == stdinPath {
+			data, err = io.ReadAll(env.stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		f := inputFormat
+		if f == "" {
+			f = structures.DetectFormat(data)
+		}
+		if firstFormat == "" {
+			firstFormat = f
+		}
+		puzzles, err := parsePuzzles(data, f)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", path, err)
+		}
+		result = append(result, puzzles...)
+	}
+	return result, firstFormat, nil
+}
+
+//parsePuzzles parses puzzles in format, only line format can contain more puzzles.
+func parsePuzzles(data []byte, format structures.Format) ([]*structures.Puzzle, error) {
+	switch format {
+	case structures.FormatLine:
+		var result []*structures.Puzzle
+		reader := structures.NewCollectionReader(bytes.NewReader(data))
+		for reader.Next() {
+			result = append(result, reader.Puzzle())
+		}
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		if len(result) == 0 {
+			return nil, structures.ErrParseLineLength
+		}
+		return result, nil
+	case structures.FormatSDK:
+		p, err := structures.ReadSDK(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return []*structures.Puzzle{p}, nil
+	}
+	g, err := structures.LoadFormat(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, err
+	}
+	return []*structures.Puzzle{{Game: g}}, nil
+}
+
+//writePuzzles writes puzzles in format, metadata are kept in line and SadMan
+// formats, puzzles in other formats are separated by empty line.
+func writePuzzles(w io.Writer, puzzles []*structures.Puzzle, format structures.Format) error {
+	if format == structures.FormatLine {
+		cw := structures.NewCollectionWriter(w)
+		for _, p := range puzzles {
+			if err := cw.Write(p); err != nil {
+				return err
+			}
+		}
+		return cw.Flush()
+	}
+	for idx, p := range puzzles {
+		if idx > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		var err error
+		if format == structures.FormatSDK {
+			err = structures.WriteSDK(w, p)
+		} else {
+			err = structures.Save(w, p.Game, format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//label returns name of puzzle used in messages, it is empty for single puzzle.
+func label(puzzles []*structures.Puzzle, idx int) string {
+	if len(puzzles) == 1 {
+		return ""
+	}
+	if puzzles[idx].Name != "" {
+		return puzzles[idx].Name + ": "
+	}
+	return fmt.Sprintf("#%d: ", idx+1)
+}
+
+//exitCode returns exit code for error of game processing.
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.Is(err, engine.ErrUnsolvable), errors.Is(err, engine.ErrMultipleSolutions),
+		errors.Is(err, engine.ErrNotFinished), errors.Is(err, engine.ErrGeneratorLevel):
+		return exitFailure
+	}
+	return exitError
+}
+
+//fail writes error of subcommand (name is name of its flag set) and returns
+// its exit code.
+func fail(env *environment, name string, err error) int {
+	fmt.Fprintf(env.stderr, "%s: %v\n", name, err)
+	var unknown *engine.UnknownStrategyError
+	if errors.Is(err, structures.ErrUnknownFormat) || errors.Is(err, engine.ErrUnknownLevel) ||
+		errors.Is(err, ErrInvalidCount) || errors.Is(err, ErrPlayStdin) || errors.Is(err, ErrGameNumber) ||
+		errors.Is(err, ErrReplStdin) || errors.Is(err, ErrPuzzleSources) || errors.Is(err, ErrExportFormat) ||
+		errors.Is(err, ErrMirrorAxis) || errors.Is(err, ErrInvalidDate) || errors.Is(err, ErrPageSize) ||
+		errors.Is(err, render.ErrBookletInvalidLayout) || errors.Is(err, render.ErrBookletPageTooSmall) ||
+		errors.Is(err, structures.ErrUnsupportedGeometry) || errors.Is(err, structures.ErrStandardOnly) ||
+		errors.Is(err, structures.ErrCageDigits) || errors.As(err, &unknown) {
+		return exitUsage
+	}
+	return exitCode(err)
+}
+
+//worse returns the more serious exit code.
+func worse(a int, b int) int {
+	if b > a {
+		return b
+	}
+	return a
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	easyGame   string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	easySolved string = "825314697396782415417965328971853246543276981682491573269138754154627839738549162"
+	hardGame   string = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+	hardSolved string = "812753649943682175675491283154237896369845721287169534521974368438526917796318452"
+)
+
+//runCommand runs sudoku command with standard input and returns exit code and outputs.
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &environment{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		args     []string
+		code     int
+		expected string
+	}{
+		{nil, exitUsage, ""},
+		{[]string{"help"}, exitOk, "Commands:"},
+		{[]string{"bogus"}, exitUsage, ""},
+		{[]string{"solve", "-h"}, exitOk, ""},
+		{[]string{"solve", "--unknown"}, exitUsage, ""},
+		{[]string{"solve", "--format", "xls"}, exitUsage, ""},
+		{[]string{"solve", "--strategies", "swordfish"}, exitUsage, ""},
+		{[]string{"generate", "--level", "impossible"}, exitUsage, ""},
+		{[]string{"generate", "--count", "0"}, exitUsage, ""},
+		{[]string{"convert"}, exitUsage, ""},
+	}
+	for _, test := range tests {
+		code, stdout, _ := runCommand(easyGame, test.args...)
+		if code != test.code {
+			t.Errorf("sudoku %v returns exit code %d, but expected: %d", test.args, code, test.code)
+		}
+		if !strings.Contains(stdout, test.expected) {
+			t.Errorf("sudoku %v writes %q, it should contain: %q", test.args, stdout, test.expected)
+		}
+	}
+}
+
+func TestReadPuzzlesFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	collection := filepath.Join(dir, "games.txt")
+	err := os.WriteFile(collection, []byte("# games\n"+easyGame+"\teasy\n"+hardGame+"\thard\n"), 0600)
+	if err != nil {
+		t.Errorf("File should be written, but err: %v", err)
+	}
+	grid := filepath.Join(dir, "game.sudoku")
+	g, _ := structures.ParseLine(easyGame)
+	if err = os.WriteFile(grid, []byte(g.GameVisual()), 0600); err != nil {
+		t.Errorf("File should be written, but err: %v", err)
+	}
+	env := &environment{stdin: strings.NewReader(hardGame)}
+	puzzles, format, err := readPuzzles(env, []string{collection, grid, stdinPath}, "")
+	if err != nil {
+		t.Errorf("readPuzzles should pass, but err: %v", err)
+	}
+	if format != structures.FormatLine || len(puzzles) != 4 {
+		t.Errorf("readPuzzles returns %d puzzles in %s, but expected 4 in %s", len(puzzles), format,
+			structures.FormatLine)
+	}
+	for idx, line := range []string{easyGame, hardGame, easyGame, hardGame} {
+		if idx < len(puzzles) && puzzles[idx].Game.Line() != line {
+			t.Errorf("Puzzle %d is %s, but expected: %s", idx, puzzles[idx].Game.Line(), line)
+		}
+	}
+	if len(puzzles) > 1 && label(puzzles, 1) != "hard: " {
+		t.Errorf("Puzzle label is %q, but expected: %q", label(puzzles, 1), "hard: ")
+	}
+	_, _, err = readPuzzles(env, []string{filepath.Join(dir, "missing.txt")}, "")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("readPuzzles should return os.ErrNotExist, but err: %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{engine.ErrUnsolvable, exitFailure},
+		{engine.ErrNotFinished, exitFailure},
+		{context.DeadlineExceeded, exitTimeout},
+		{structures.ErrParseLineLength, exitError},
+	}
+	for _, test := range tests {
+		if code := exitCode(test.err); code != test.expected {
+			t.Errorf("exitCode(%v) = %d, but expected: %d", test.err, code, test.expected)
+		}
+	}
+}
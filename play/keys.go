@@ -0,0 +1,116 @@
+package play
+
+import (
+	"bufio"
+)
+
+//KeyCode represents kind of pressed key.
+type KeyCode uint8
+
+//Supported keys, printable characters have code KeyRune.
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyDelete
+	KeyBackspace
+	KeyEnter
+	KeyEscape
+	KeyCtrlC
+	KeyUnknown
+)
+
+//Control characters and escape sequences sent by terminal.
+const (
+	charCtrlC     byte = 3
+	charBackspace byte = 8
+	charEnter     byte = '\r'
+	charNewLine   byte = '\n'
+	charEscape    byte = 27
+	charDelete    byte = 127
+	csiPrefix     byte = '['
+	ss3Prefix     byte = 'O'
+)
+
+//Key represents one pressed key.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+//RuneKey creates Key object for printable character.
+func RuneKey(r rune) Key {
+	return Key{Code: KeyRune, Rune: r}
+}
+
+//ReadKey reads one key from terminal in raw mode, arrow keys and delete are
+// decoded from their escape sequences. Escape key is recognized only when no
+// other byte is buffered after it.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	switch b {
+	case charCtrlC:
+		return Key{Code: KeyCtrlC}, nil
+	case charBackspace, charDelete:
+		return Key{Code: KeyBackspace}, nil
+	case charEnter, charNewLine:
+		return Key{Code: KeyEnter}, nil
+	case charEscape:
+		if r.Buffered() == 0 {
+			return Key{Code: KeyEscape}, nil
+		}
+		return readEscapeSequence(r)
+	}
+	if b < 0x80 {
+		return RuneKey(rune(b)), nil
+	}
+	if err = r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	char, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return RuneKey(char), nil
+}
+
+//readEscapeSequence decodes sequence following escape character.
+func readEscapeSequence(r *bufio.Reader) (Key, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if prefix != csiPrefix && prefix != ss3Prefix {
+		return Key{Code: KeyUnknown}, nil
+	}
+	var params []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		// parameters are digits and ;, the sequence ends with other character.
+		if (b >= '0' && b <= '9') || b == ';' {
+			params = append(params, b)
+			continue
+		}
+		switch {
+		case b == 'A':
+			return Key{Code: KeyUp}, nil
+		case b == 'B':
+			return Key{Code: KeyDown}, nil
+		case b == 'C':
+			return Key{Code: KeyRight}, nil
+		case b == 'D':
+			return Key{Code: KeyLeft}, nil
+		case b == '~' && string(params) == "3":
+			return Key{Code: KeyDelete}, nil
+		}
+		return Key{Code: KeyUnknown}, nil
+	}
+}
@@ -0,0 +1,37 @@
+package play
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	input := "5\x1b[A\x1b[B\x1b[C\x1b[D\x1bOA\x1b[3~\x7f\r\x03ž\x1b[1;5C"
+	expected := []Key{
+		RuneKey('5'), {Code: KeyUp}, {Code: KeyDown}, {Code: KeyRight}, {Code: KeyLeft}, {Code: KeyUp},
+		{Code: KeyDelete}, {Code: KeyBackspace}, {Code: KeyEnter}, {Code: KeyCtrlC}, RuneKey('ž'),
+		{Code: KeyRight},
+	}
+	r := bufio.NewReader(strings.NewReader(input))
+	for idx, e := range expected {
+		k, err := ReadKey(r)
+		if err != nil {
+			t.Errorf("ReadKey should pass, but err: %v", err)
+		}
+		if k != e {
+			t.Errorf("Key %d is %+v, but expected: %+v", idx, k, e)
+		}
+	}
+}
+
+func TestReadKeyEscape(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b"))
+	if k, err := ReadKey(r); err != nil || k.Code != KeyEscape {
+		t.Errorf("ReadKey should return Escape key, but returns: %+v, err: %v", k, err)
+	}
+	r = bufio.NewReader(strings.NewReader("\x1b[15~"))
+	if k, err := ReadKey(r); err != nil || k.Code != KeyUnknown {
+		t.Errorf("ReadKey should return unknown key, but returns: %+v, err: %v", k, err)
+	}
+}
@@ -0,0 +1,307 @@
+package play
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Model represents state of played game - entered values, pencil marks, cursor,
+// undo history and timer. Values entered by player are solution cells of the game.
+type Model struct {
+	game       *structures.Game
+	row, col   int
+	pencil     bool
+	history    []*structures.Game
+	message    string
+	highlights map[string]bool
+	start      time.Time
+	end        time.Time
+	now        func() time.Time
+}
+
+//NewModel creates Model object for copy of the game, timer starts immediately.
+func NewModel(g *structures.Game) *Model {
+	return newModel(g, time.Now)
+}
+
+func newModel(g *structures.Game, now func() time.Time) *Model {
+	m := Model{game: g.Clone(), now: now, highlights: make(map[string]bool)}
+	m.start = now()
+	m.checkSolved()
+	return &m
+}
+
+//Game returns current state of the game.
+func (m *Model) Game() *structures.Game {
+	return m.game
+}
+
+//Cursor returns id of cell under cursor.
+func (m *Model) Cursor() string {
+	return m.cellID(m.row, m.col)
+}
+
+//Solved returns if all cells are filled without conflict.
+func (m *Model) Solved() bool {
+	return !m.end.IsZero()
+}
+
+//Elapsed returns time spent on the game, timer stops when game is solved.
+func (m *Model) Elapsed() time.Duration {
+	if m.Solved() {
+		return m.end.Sub(m.start)
+	}
+	return m.now().Sub(m.start)
+}
+
+//HandleKey method changes model by pressed key, it returns false when player
+// quits the game.
+func (m *Model) HandleKey(k Key) bool {
+	switch k.Code {
+	case KeyCtrlC, KeyEscape:
+		return false
+	case KeyUp:
+		m.move(-1, 0)
+	case KeyDown:
+		m.move(1, 0)
+	case KeyLeft:
+		m.move(0, -1)
+	case KeyRight:
+		m.move(0, 1)
+	case KeyDelete, KeyBackspace:
+		m.erase()
+	case KeyRune:
+		return m.handleRune(k.Rune)
+	}
+	return true
+}
+
+func (m *Model) handleRune(r rune) bool {
+	switch {
+	case m.isValue(r):
+		value, _ := m.game.Geometry().ParseValue(r)
+		if m.pencil {
+			m.toggleMark(value)
+		} else {
+			m.enter(value)
+		}
+	case r == '0' || r == '.' || r == ' ':
+		m.erase()
+	case r == 'k':
+		m.move(-1, 0)
+	case r == 'j':
+		m.move(1, 0)
+	case r == 'h':
+		m.move(0, -1)
+	case r == 'l':
+		m.move(0, 1)
+	case r == 'p':
+		m.pencil = !m.pencil
+		m.message = ""
+	case r == '?':
+		m.hint()
+	case r == 'u':
+		m.undo()
+	case r == 'q':
+		return false
+	}
+	return true
+}
+
+//isValue method returns if rune is value of the grid (digit or letter for
+// values greater than 9), empty cell is not value.
+func (m *Model) isValue(r rune) bool {
+	value, ok := m.game.Geometry().ParseValue(r)
+	return ok && value != structures.EmptyCellValue
+}
+
+//move method moves cursor, it wraps around grid edges.
+func (m *Model) move(rows int, cols int) {
+	size := int(m.game.Geometry().Size)
+	m.row = (m.row + rows + size) % size
+	m.col = (m.col + cols + size) % size
+}
+
+//editable returns if cell under cursor can be changed, message is set when not.
+func (m *Model) editable() bool {
+	if m.Solved() {
+		m.message = "Game is solved, press u to undo or q to quit."
+		return false
+	}
+	if c, err := m.game.Cell(m.Cursor()); err == nil && !c.SolutionCell() {
+		m.message = fmt.Sprintf("Cell %s is given.", m.Cursor())
+		return false
+	}
+	return true
+}
+
+func (m *Model) enter(value uint8) {
+	if !m.editable() {
+		return
+	}
+	cell, err := m.game.Geometry().NewSolutionCell(m.Cursor(), value)
+	if err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.save()
+	m.game.RemoveSolutionCells([]string{cell.Id})
+	if err = m.game.AddCell(cell); err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.checkSolved()
+}
+
+func (m *Model) erase() {
+	if !m.editable() {
+		return
+	}
+	_, err := m.game.Cell(m.Cursor())
+	marks, hasMarks := m.game.PencilMarks(m.Cursor())
+	if err != nil && (!hasMarks || len(marks) == 0) {
+		return
+	}
+	m.save()
+	if err == nil {
+		m.game.RemoveSolutionCells([]string{m.Cursor()})
+	} else {
+		m.game.ClearPencilMarks(m.Cursor())
+	}
+}
+
+func (m *Model) toggleMark(value uint8) {
+	if !m.editable() {
+		return
+	}
+	if _, err := m.game.Cell(m.Cursor()); err == nil {
+		m.message = fmt.Sprintf("Cell %s is filled, erase it first.", m.Cursor())
+		return
+	}
+	marks, _ := m.game.PencilMarks(m.Cursor())
+	var toggled []uint8
+	found := false
+	for _, v := range marks {
+		if v == value {
+			found = true
+			continue
+		}
+		toggled = append(toggled, v)
+	}
+	if !found {
+		toggled = append(toggled, value)
+	}
+	m.save()
+	if len(toggled) == 0 {
+		m.game.ClearPencilMarks(m.Cursor())
+		return
+	}
+	if err := m.game.SetPencilMarks(m.Cursor(), toggled); err != nil {
+		m.message = err.Error()
+	}
+}
+
+//save method stores state of the game for undo, message and hint are cleared
+// because game is going to change.
+func (m *Model) save() {
+	m.history = append(m.history, m.game.Clone())
+	m.message = ""
+	m.highlights = make(map[string]bool)
+}
+
+func (m *Model) undo() {
+	if len(m.history) == 0 {
+		m.message = "Nothing to undo."
+		return
+	}
+	m.game = m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	m.message = ""
+	m.highlights = make(map[string]bool)
+	m.end = time.Time{}
+	m.checkSolved()
+}
+
+//hint method shows next logical step found by engine and highlights its cells.
+func (m *Model) hint() {
+	m.highlights = make(map[string]bool)
+	if m.Solved() {
+		return
+	}
+	// pencil marks are player's notes, they can be partial or wrong, so hint
+	// is searched from computed candidates.
+	game := m.game.Clone()
+	for _, id := range game.EmptyCells() {
+		game.ClearPencilMarks(id)
+	}
+	step, err := engine.NewEngine(game).Hint()
+	switch {
+	case err != nil:
+		m.message = "Game has no solution from this state, undo some entries."
+	case step == nil:
+		m.message = "No logical step found."
+	default:
+		m.message = "Hint: " + step.String()
+		if step.IsPlacement() {
+			m.highlights[step.CellID] = true
+		}
+		for _, e := range step.Eliminations {
+			m.highlights[e.CellID] = true
+		}
+	}
+}
+
+//conflicts returns cells which take part in some conflict and description
+// of units with conflicts.
+func (m *Model) conflicts() (map[string]bool, string) {
+	cells := make(map[string]bool)
+	report, err := m.game.ValidationReport()
+	if err != nil {
+		return cells, err.Error()
+	}
+	if len(report.Conflicts) == 0 {
+		return cells, ""
+	}
+	var units []string
+	found := make(map[structures.UnitType]bool)
+	for _, c := range report.Conflicts {
+		if !found[c.Unit] {
+			found[c.Unit] = true
+			units = append(units, c.Unit.String()+"s")
+		}
+	}
+	for _, id := range report.ConflictCells() {
+		cells[id] = true
+	}
+	return cells, "Conflict in " + strings.Join(units, ", ") + "."
+}
+
+//checkSolved method stops timer when all cells are filled without conflict.
+func (m *Model) checkSolved() {
+	if m.game.EmptyCellCount() != 0 {
+		return
+	}
+	if _, description := m.conflicts(); description != "" {
+		return
+	}
+	m.end = m.now()
+	m.message = "Solved in " + formatDuration(m.Elapsed()) + "!"
+}
+
+//cellID method returns id of cell in 0-based row and column.
+func (m *Model) cellID(row int, col int) string {
+	return m.game.Geometry().CellID(uint8(row+1), uint8(col+1))
+}
+
+//formatDuration returns duration as m:ss or h:mm:ss.
+func formatDuration(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
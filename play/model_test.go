@@ -0,0 +1,142 @@
+package play
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	easyGame   string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	easySolved string = "825314697396782415417965328971853246543276981682491573269138754154627839738549162"
+)
+
+//clock represents time controlled by test.
+type clock struct {
+	current time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.current
+}
+
+func createModel(t *testing.T, line string) (*Model, *clock) {
+	g, err := structures.ParseLine(line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	c := &clock{current: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)}
+	return newModel(g, c.now), c
+}
+
+//press sends keys to model, digits and letters are sent as runes.
+func press(m *Model, keys ...interface{}) bool {
+	for _, k := range keys {
+		var ok bool
+		switch key := k.(type) {
+		case Key:
+			ok = m.HandleKey(key)
+		case rune:
+			ok = m.HandleKey(RuneKey(key))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestModelMoveAndEnter(t *testing.T) {
+	m, _ := createModel(t, easyGame)
+	press(m, '8', Key{Code: KeyRight}, '2', Key{Code: KeyLeft}, Key{Code: KeyLeft})
+	if m.Cursor() != "i1" {
+		t.Errorf("Cursor is at %s, but expected: i1", m.Cursor())
+	}
+	press(m, 'l', 'l', 'l', '4')
+	if m.Cursor() != "c1" || !strings.Contains(m.message, "c1 is given") {
+		t.Errorf("Given cell c1 should not be changed, message: %s", m.message)
+	}
+	line := m.Game().Line()
+	if line[:2] != "82" || m.Game().SolutionCellCount() != 2 {
+		t.Errorf("Game should have entered values 8 and 2, but is: %s", line)
+	}
+	press(m, 'h', '3', Key{Code: KeyBackspace})
+	if m.Game().Line()[:2] != "8." {
+		t.Errorf("Value of b1 should be erased, but game is: %s", m.Game().Line())
+	}
+}
+
+func TestModelUndo(t *testing.T) {
+	m, _ := createModel(t, easyGame)
+	press(m, '8', '2', 'l', '2')
+	press(m, 'u')
+	if m.Game().Line()[:2] != "2." {
+		t.Errorf("Undo should remove b1 value, but game is: %s", m.Game().Line())
+	}
+	press(m, 'u', 'u', 'u')
+	if m.Game().Line() != easyGame || m.message != "Nothing to undo." {
+		t.Errorf("Undo should return original game, but game is: %s, message: %s", m.Game().Line(), m.message)
+	}
+}
+
+func TestModelPencilMarks(t *testing.T) {
+	m, _ := createModel(t, easyGame)
+	press(m, 'p', '8', '2', '7', '2')
+	marks, ok := m.Game().PencilMarks("a1")
+	if !ok || len(marks) != 2 || marks[0] != 7 || marks[1] != 8 {
+		t.Errorf("Cell a1 should have marks 7 8, but has: %v", marks)
+	}
+	press(m, 'p', '8', 'p', '1')
+	if !strings.Contains(m.message, "is filled") {
+		t.Errorf("Marks of filled cell should not be changed, message: %s", m.message)
+	}
+	press(m, 'p', Key{Code: KeyDelete}, Key{Code: KeyDelete})
+	if _, ok = m.Game().PencilMarks("a1"); ok {
+		t.Error("Marks of a1 should be cleared after value is erased")
+	}
+}
+
+func TestModelHint(t *testing.T) {
+	m, _ := createModel(t, easyGame)
+	press(m, '?')
+	if m.message != "Hint: a1 = 8 (naked-single)" || !m.highlights["a1"] {
+		t.Errorf("Hint should show a1 = 8, but message is: %s", m.message)
+	}
+	press(m, '5')
+	if len(m.highlights) != 0 {
+		t.Error("Hint should be cleared after move")
+	}
+	press(m, '?')
+	if !strings.Contains(m.message, "no solution") {
+		t.Errorf("Hint should report wrong entries, but message is: %s", m.message)
+	}
+}
+
+func TestModelHintIgnoresPencilMarks(t *testing.T) {
+	m, _ := createModel(t, easyGame)
+	press(m, 'p', '2', 'l', '7', 'p', '?')
+	if m.message != "Hint: a1 = 8 (naked-single)" || !m.highlights["a1"] {
+		t.Errorf("Hint should ignore pencil marks and show a1 = 8, but message is: %s", m.message)
+	}
+	if marks, ok := m.Game().PencilMarks("a1"); !ok || len(marks) != 1 || marks[0] != 2 {
+		t.Errorf("Cell a1 should keep its marks 2, but has: %v", marks)
+	}
+}
+
+func TestModelSolvedStopsTimer(t *testing.T) {
+	m, c := createModel(t, easySolved[:80]+".")
+	c.current = c.current.Add(90 * time.Second)
+	if m.Elapsed() != 90*time.Second || m.Solved() {
+		t.Errorf("Timer should run, but elapsed: %s", m.Elapsed())
+	}
+	press(m, Key{Code: KeyUp}, Key{Code: KeyLeft}, '2')
+	c.current = c.current.Add(time.Minute)
+	if !m.Solved() || m.Elapsed() != 90*time.Second || m.message != "Solved in 1:30!" {
+		t.Errorf("Game should be solved in 1:30, but elapsed: %s, message: %s", m.Elapsed(), m.message)
+	}
+	if press(m, 'q') {
+		t.Error("Key q should quit the game")
+	}
+}
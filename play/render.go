@@ -0,0 +1,128 @@
+package play
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//ANSI escape sequences used for drawing.
+const (
+	escClearScreen string = "\x1b[H\x1b[2J"
+	escHideCursor  string = "\x1b[?25l"
+	escShowCursor  string = "\x1b[?25h"
+	escReset       string = "\x1b[0m"
+	escBold        string = "\x1b[1m"
+	escBlue        string = "\x1b[34m"
+	escReverse     string = "\x1b[7m"
+	escRedBack     string = "\x1b[41m"
+	escYellowBack  string = "\x1b[43m"
+	escDim         string = "\x1b[2m"
+)
+
+//Parts of grid drawing.
+const (
+	gridBoxSep  string = "|"
+	gridCorner  string = "+"
+	gridLine    string = "---"
+	markedCell  string = "+"
+	helpLineFmt string = "arrows/hjkl move  1-%s enter  0/del erase  p pencil  ? hint  u undo  q quit"
+)
+
+//Render returns screen with grid and status lines. Givens are bold, entered
+// values blue, cells in conflict have red background, hinted cells yellow
+// background and cursor is in reverse video. Empty cell with pencil marks is
+// shown as +, marks of cell under cursor are written below the grid.
+func (m *Model) Render() string {
+	var screen strings.Builder
+	screen.WriteString(escClearScreen)
+	conflicts, conflictMessage := m.conflicts()
+	title := "Sudoku"
+	if m.Solved() {
+		title += " - solved"
+	}
+	fmt.Fprintf(&screen, "%-26s%8s\n\n", title, formatDuration(m.Elapsed()))
+	geometry := m.game.Geometry()
+	size := int(geometry.Size)
+	boxRows, boxColumns := int(geometry.BoxRows), int(geometry.BoxColumns)
+	// row numbers are right aligned in label followed by space.
+	label := len(fmt.Sprint(size)) + 1
+	columns := strings.Repeat(" ", label)
+	separator := strings.Repeat(" ", label) + gridCorner
+	for col := 0; col < size; col++ {
+		if col%boxColumns == 0 {
+			columns += " "
+		}
+		columns += fmt.Sprintf(" %c ", 'a'+col)
+		if col%boxColumns == boxColumns-1 {
+			separator += strings.Repeat(gridLine, boxColumns) + gridCorner
+		}
+	}
+	screen.WriteString(strings.TrimRight(columns, " ") + "\n")
+	for row := 0; row < size; row++ {
+		if row%boxRows == 0 {
+			screen.WriteString(separator + "\n")
+		}
+		fmt.Fprintf(&screen, "%*d ", label-1, row+1)
+		for col := 0; col < size; col++ {
+			if col%boxColumns == 0 {
+				screen.WriteString(gridBoxSep)
+			}
+			screen.WriteString(m.renderCell(row, col, conflicts))
+		}
+		screen.WriteString(gridBoxSep + "\n")
+	}
+	screen.WriteString(separator + "\n\n")
+	status := "Cell " + m.Cursor()
+	if marks, ok := m.game.PencilMarks(m.Cursor()); ok && len(marks) > 0 {
+		status += "  marks: " + marksText(marks)
+	}
+	if m.pencil {
+		status += "  [pencil]"
+	}
+	screen.WriteString(status + "\n")
+	message := m.message
+	if message == "" {
+		message = conflictMessage
+	}
+	screen.WriteString(message + "\n")
+	screen.WriteString(escDim + fmt.Sprintf(helpLineFmt, geometry.ValueText(geometry.Size)) + escReset + "\n")
+	return screen.String()
+}
+
+func (m *Model) renderCell(row int, col int, conflicts map[string]bool) string {
+	id := m.cellID(row, col)
+	text := structures.EmptyCellTextValue
+	style := ""
+	if c, err := m.game.Cell(id); err == nil && c.Value() != structures.EmptyCellValue {
+		text = c.TextValue()
+		if c.SolutionCell() {
+			style += escBlue
+		} else {
+			style += escBold
+		}
+	} else if marks, ok := m.game.PencilMarks(id); ok && len(marks) > 0 {
+		text = markedCell
+	}
+	switch {
+	case row == m.row && col == m.col:
+		style += escReverse
+	case conflicts[id]:
+		style += escRedBack
+	case m.highlights[id]:
+		style += escYellowBack
+	}
+	if style == "" {
+		return " " + text + " "
+	}
+	return style + " " + text + " " + escReset
+}
+
+func marksText(marks []uint8) string {
+	values := make([]string, len(marks))
+	for idx, v := range marks {
+		values[idx] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(values, " ")
+}
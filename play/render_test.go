@@ -0,0 +1,62 @@
+package play
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModelRender(t *testing.T) {
+	m, c := createModel(t, easyGame)
+	press(m, 'l', '5', 'l', 'l', 'p', '3', '7')
+	c.current = c.current.Add(3725 * time.Second)
+	screen := m.Render()
+	expected := []string{
+		escClearScreen,
+		"Sudoku                     1:02:05\n",
+		"    a  b  c   d  e  f   g  h  i\n  +---------+---------+---------+\n",
+		"1 | . " + escBlue + escRedBack + " 5 " + escReset + escBold + escRedBack + " 5 " + escReset + "|" +
+			escReverse + " + " + escReset,
+		"Cell d1  marks: 3 7  [pencil]\n",
+		"Conflict in rows, columns, squares.\n",
+		"arrows/hjkl move  1-9 enter  0/del erase  p pencil  ? hint  u undo  q quit",
+	}
+	for _, text := range expected {
+		if !strings.Contains(screen, text) {
+			t.Errorf("Screen should contain %q, but is:\n%s", text, screen)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0:00"},
+		{59 * time.Second, "0:59"},
+		{754 * time.Second, "12:34"},
+		{3661 * time.Second, "1:01:01"},
+	}
+	for _, test := range tests {
+		if got := formatDuration(test.d); got != test.expected {
+			t.Errorf("formatDuration(%s) = %s, but expected: %s", test.d, got, test.expected)
+		}
+	}
+}
+
+func TestModelRenderSmallGrid(t *testing.T) {
+	m, _ := createModel(t, "1....2...3.2....")
+	press(m, 'j', '4')
+	screen := m.Render()
+	expected := []string{
+		"    a  b   c  d\n  +------+------+\n",
+		"2 |" + escBlue + escReverse + " 4 " + escReset + escBold + " 2 " + escReset + "| .  . |\n",
+		"1-4 enter",
+	}
+	for _, text := range expected {
+		if !strings.Contains(screen, text) {
+			t.Errorf("Screen should contain %q, but is:\n%s", text, screen)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package play
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//sttyCommand is command used for switching terminal mode.
+var sttyCommand string = "stty"
+
+//Errors for terminal.
+var (
+	ErrNotTerminal error = errors.New("Input is not a terminal")
+	ErrNoStty      error = errors.New("Terminal mode can not be switched, stty command was not found")
+)
+
+//keyEvent represents key read from terminal or error of reading.
+type keyEvent struct {
+	key Key
+	err error
+}
+
+//Run plays the game in terminal - tty is switched to raw mode by stty command
+// (ErrNoStty is returned when it is not installed), keys are read from it and
+// screen is redrawn to out after every key and every second. It returns final
+// model when player quits or input ends.
+func Run(g *structures.Game, tty *os.File, out io.Writer) (*Model, error) {
+	if info, err := tty.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, ErrNotTerminal
+	}
+	restore, err := makeRaw(tty)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	io.WriteString(out, escHideCursor)
+	defer io.WriteString(out, escShowCursor)
+
+	m := NewModel(g)
+	keys := make(chan keyEvent)
+	done := make(chan struct{})
+	defer close(done)
+	go readKeys(tty, keys, done)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if _, err = io.WriteString(out, m.Render()); err != nil {
+			return m, err
+		}
+		select {
+		case event := <-keys:
+			if event.err == io.EOF {
+				return m, nil
+			}
+			if event.err != nil {
+				return m, event.err
+			}
+			if !m.HandleKey(event.key) {
+				return m, nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+//readKeys sends keys read from r to keys until reading fails or done is closed.
+// Blocked read of key is not interrupted by done, reader ends after it.
+func readKeys(r io.Reader, keys chan<- keyEvent, done <-chan struct{}) {
+	br := bufio.NewReader(r)
+	for {
+		k, err := ReadKey(br)
+		select {
+		case keys <- keyEvent{k, err}:
+		case <-done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+//makeRaw switches terminal to mode where keys are read immediately without echo
+// and Ctrl-C is read as key, it returns function restoring previous mode.
+func makeRaw(tty *os.File) (func(), error) {
+	if _, err := exec.LookPath(sttyCommand); err != nil {
+		return nil, ErrNoStty
+	}
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stty(tty, "-icanon", "-echo", "-isig", "min", "1", "time", "0"); err != nil {
+		return nil, err
+	}
+	return func() {
+		stty(tty, strings.TrimSpace(state))
+	}, nil
+}
+
+//stty runs stty command on terminal and returns its output.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command(sttyCommand, args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,51 @@
+package play
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestRunRequiresTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "keys")
+	if err != nil {
+		t.Errorf("Temporary file should be created, but err: %v", err)
+	}
+	defer f.Close()
+	g, _ := structures.ParseLine(easyGame)
+	if _, err = Run(g, f, io.Discard); !errors.Is(err, ErrNotTerminal) {
+		t.Errorf("Run should return ErrNotTerminal, but err: %v", err)
+	}
+}
+
+func TestReadKeysStopsWhenDone(t *testing.T) {
+	keys := make(chan keyEvent)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		readKeys(strings.NewReader("123"), keys, done)
+		close(finished)
+	}()
+	if event := <-keys; event.err != nil || event.key != RuneKey('1') {
+		t.Errorf("First key should be 1, but is: %v, err: %v", event.key, event.err)
+	}
+	close(done)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("readKeys should end when done is closed")
+	}
+}
+
+func TestMakeRawWithoutStty(t *testing.T) {
+	defer func(command string) { sttyCommand = command }(sttyCommand)
+	sttyCommand = "missing-stty-command"
+	if _, err := makeRaw(os.Stdin); !errors.Is(err, ErrNoStty) {
+		t.Errorf("makeRaw should return ErrNoStty, but err: %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Page sizes in points.
+const (
+	A4Width      float64 = 595.28
+	A4Height     float64 = 841.89
+	LetterWidth  float64 = 612
+	LetterHeight float64 = 792
+)
+
+//Sizes of booklet page parts in points.
+const (
+	pageMargin     float64 = 36
+	titleSize      float64 = 16
+	titleHeight    float64 = 32
+	footerHeight   float64 = 24
+	labelSize      float64 = 10
+	labelHeight    float64 = 16
+	slotSpacing    float64 = 12
+	pageNumberSize float64 = 10
+)
+
+//Errors for booklet.
+var (
+	ErrBookletEmpty         error = errors.New("Booklet has no puzzle")
+	ErrBookletInvalidLayout error = errors.New("Layout must have at least one column and one row")
+	ErrBookletPageTooSmall  error = errors.New("Page is too small for layout")
+)
+
+//Layout represents grid of puzzles on one page.
+type Layout struct {
+	Columns int
+	Rows    int
+}
+
+//ParseLayout returns layout from its name - columns and rows (ex. 2x3).
+func ParseLayout(name string) (Layout, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(name)), "x")
+	if len(parts) != 2 {
+		return Layout{}, ErrBookletInvalidLayout
+	}
+	columns, err := strconv.Atoi(parts[0])
+	if err != nil || columns < 1 {
+		return Layout{}, ErrBookletInvalidLayout
+	}
+	rows, err := strconv.Atoi(parts[1])
+	if err != nil || rows < 1 {
+		return Layout{}, ErrBookletInvalidLayout
+	}
+	return Layout{Columns: columns, Rows: rows}, nil
+}
+
+//String returns name of layout (ex. 2x3).
+func (l Layout) String() string {
+	return fmt.Sprintf("%dx%d", l.Columns, l.Rows)
+}
+
+//bookletPuzzle represents one puzzle of booklet with its optional solution.
+type bookletPuzzle struct {
+	name     string
+	game     *structures.Game
+	solution *structures.Game
+}
+
+//Booklet represents printable collection of puzzles. Puzzles are printed
+// in Layout, solutions of puzzles (when they are known) are printed in answers
+// section at the end in AnswerLayout.
+type Booklet struct {
+	Title        string
+	AnswersTitle string
+	PageWidth    float64
+	PageHeight   float64
+	Layout       Layout
+	AnswerLayout Layout
+	puzzles      []bookletPuzzle
+}
+
+//NewBooklet creates Booklet object with A4 pages, 2x3 puzzles per page and 3x4
+// answers per page.
+func NewBooklet(title string) *Booklet {
+	return &Booklet{
+		Title:        title,
+		AnswersTitle: "Answers",
+		PageWidth:    A4Width,
+		PageHeight:   A4Height,
+		Layout:       Layout{Columns: 2, Rows: 3},
+		AnswerLayout: Layout{Columns: 3, Rows: 4},
+	}
+}
+
+//Add method adds puzzle to booklet, solution can be nil.
+func (b *Booklet) Add(name string, game *structures.Game, solution *structures.Game) {
+	b.puzzles = append(b.puzzles, bookletPuzzle{name: name, game: game, solution: solution})
+}
+
+//Len returns count of puzzles in booklet.
+func (b *Booklet) Len() int {
+	return len(b.puzzles)
+}
+
+//WritePDF writes booklet as PDF document.
+func (b *Booklet) WritePDF(w io.Writer) error {
+	if len(b.puzzles) == 0 {
+		return ErrBookletEmpty
+	}
+	puzzles := make([]bookletPuzzle, len(b.puzzles))
+	var answers []bookletPuzzle
+	for idx, p := range b.puzzles {
+		if p.name == "" {
+			p.name = fmt.Sprintf("#%d", idx+1)
+		}
+		puzzles[idx] = p
+		if p.solution != nil {
+			answers = append(answers, bookletPuzzle{name: p.name, game: p.solution})
+		}
+	}
+	pages, err := b.sectionPages(b.Title, puzzles, b.Layout)
+	if err != nil {
+		return err
+	}
+	if len(answers) > 0 {
+		answerPages, err := b.sectionPages(b.AnswersTitle, answers, b.AnswerLayout)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, answerPages...)
+	}
+	for idx, page := range pages {
+		page.centeredText(b.PageWidth/2, b.PageHeight-pageMargin, pageNumberSize, pdfFontRegular,
+			fmt.Sprintf("%d / %d", idx+1, len(pages)))
+	}
+	return writePDF(w, pages, b.PageWidth, b.PageHeight)
+}
+
+//sectionPages creates pages with puzzles placed in layout.
+func (b *Booklet) sectionPages(title string, puzzles []bookletPuzzle, layout Layout) ([]*pdfPage, error) {
+	if layout.Columns < 1 || layout.Rows < 1 {
+		return nil, ErrBookletInvalidLayout
+	}
+	slotWidth := (b.PageWidth - 2*pageMargin - float64(layout.Columns-1)*slotSpacing) / float64(layout.Columns)
+	slotHeight := (b.PageHeight - 2*pageMargin - titleHeight - footerHeight -
+		float64(layout.Rows-1)*slotSpacing) / float64(layout.Rows)
+	gridSize := math.Min(slotWidth, slotHeight-labelHeight)
+	perPage := layout.Columns * layout.Rows
+	var pages []*pdfPage
+	var page *pdfPage
+	for idx, p := range puzzles {
+		position := idx % perPage
+		if position == 0 {
+			page = &pdfPage{height: b.PageHeight}
+			page.text(pageMargin, pageMargin+titleSize, titleSize, pdfFontBold, title, ColorLine)
+			pages = append(pages, page)
+		}
+		cellSize := fittingCellSize(gridSize, int(p.game.Geometry().Size))
+		if cellSize < 8 {
+			return nil, ErrBookletPageTooSmall
+		}
+		s, err := newScene(p.game, Options{CellSize: cellSize})
+		if err != nil {
+			return nil, err
+		}
+		x := pageMargin + float64(position%layout.Columns)*(slotWidth+slotSpacing)
+		y := pageMargin + titleHeight + float64(position/layout.Columns)*(slotHeight+slotSpacing)
+		offset := (slotWidth - s.width) / 2
+		page.text(x+offset, y+labelSize, labelSize, pdfFontRegular, p.name, ColorLine)
+		page.drawScene(s, x+offset, y+labelHeight)
+	}
+	return pages, nil
+}
+
+//fittingCellSize returns the biggest cell size of grid with size rows which
+// fits into gridSize, scene has margin of thick line width (at least 2 points)
+// around grid.
+func fittingCellSize(gridSize float64, size int) int {
+	cellSize := int(math.Floor(gridSize / float64(size)))
+	for cellSize > 0 && float64(size*cellSize)+2*math.Max(2, float64(cellSize)/16) > gridSize {
+		cellSize--
+	}
+	return cellSize
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBookletPages(t *testing.T) {
+	b := NewBooklet("Weekly pack")
+	g := createGame(t)
+	for i := 0; i < 7; i++ {
+		b.Add("", g, g)
+	}
+	b.Add("bonus", g, nil)
+	var buffer bytes.Buffer
+	if err := b.WritePDF(&buffer); err != nil {
+		t.Errorf("Booklet should be written, but err: %v", err)
+	}
+	checkPDFStructure(t, buffer.Bytes())
+	pdf := buffer.String()
+	// 8 puzzles in 2x3 layout and 7 answers in 3x4 layout.
+	if !strings.Contains(pdf, "/Count 3") {
+		t.Error("Booklet should have 3 pages.")
+	}
+	for _, expected := range []string{"(Weekly pack)", "(Answers)", "(#7)", "(bonus)", "(3 / 3)"} {
+		if !strings.Contains(pdf, expected) {
+			t.Errorf("Booklet should contain text %s", expected)
+		}
+	}
+}
+
+func TestBookletErrors(t *testing.T) {
+	b := NewBooklet("Empty")
+	if err := b.WritePDF(&bytes.Buffer{}); !errors.Is(err, ErrBookletEmpty) {
+		t.Errorf("WritePDF should return ErrBookletEmpty, but returned: %v", err)
+	}
+	b.Add("", createGame(t), nil)
+	b.Layout = Layout{Columns: 0, Rows: 1}
+	if err := b.WritePDF(&bytes.Buffer{}); !errors.Is(err, ErrBookletInvalidLayout) {
+		t.Errorf("WritePDF should return ErrBookletInvalidLayout, but returned: %v", err)
+	}
+	b.Layout = Layout{Columns: 20, Rows: 20}
+	if err := b.WritePDF(&bytes.Buffer{}); !errors.Is(err, ErrBookletPageTooSmall) {
+		t.Errorf("WritePDF should return ErrBookletPageTooSmall, but returned: %v", err)
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	layout, err := ParseLayout(" 3X4")
+	if err != nil || layout != (Layout{Columns: 3, Rows: 4}) || layout.String() != "3x4" {
+		t.Errorf("ParseLayout returns %v, but expected: 3x4, err: %v", layout, err)
+	}
+	for _, name := range []string{"", "2", "0x3", "2x-1", "axb", "2x3x4"} {
+		if _, err = ParseLayout(name); !errors.Is(err, ErrBookletInvalidLayout) {
+			t.Errorf("ParseLayout(%q) should return ErrBookletInvalidLayout, but returned: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package render
+
+import (
+	_ "embed" // widget template is embedded into binary.
+	"errors"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//go:embed templates/widget.html
+var widgetTemplate string
+
+var widget = template.Must(template.New("widget").Parse(widgetTemplate))
+
+//Errors for HTML export.
+var (
+	ErrHTMLIncompleteSolution error = errors.New("Solution of the game has empty cells")
+)
+
+//HTMLOptions represents options of HTML export. Check button is enabled only
+// when Solution is set, Steps are shown as solution transcript.
+type HTMLOptions struct {
+	Title    string
+	Solution *structures.Game
+	Steps    []engine.Step
+}
+
+//widgetCage is cage of killer game in widget, cells are indexes in lines.
+type widgetCage struct {
+	Sum   int   `json:"sum"`
+	Cells []int `json:"cells"`
+}
+
+//widgetData is passed to JavaScript of the widget, lines have one character
+// per cell, digits are texts of values 1 - size. Shaded contains indexes of
+// cells in diagonals and windows of game variant.
+type widgetData struct {
+	Size       int           `json:"size"`
+	BoxRows    int           `json:"boxRows"`
+	BoxColumns int           `json:"boxColumns"`
+	Digits     []string      `json:"digits"`
+	Givens     string        `json:"givens"`
+	Values     string        `json:"values"`
+	Solution   string        `json:"solution"`
+	Marks      map[int][]int `json:"marks"`
+	Cages      []widgetCage  `json:"cages"`
+	Shaded     []int         `json:"shaded"`
+}
+
+//HTML writes self-contained HTML page with interactive widget for solving the game.
+func HTML(w io.Writer, g *structures.Game, opts HTMLOptions) error {
+	geometry := g.Geometry()
+	size := int(geometry.Size)
+	data := widgetData{Size: size, BoxRows: int(geometry.BoxRows), BoxColumns: int(geometry.BoxColumns),
+		Values: g.Line(), Marks: make(map[int][]int)}
+	for value := uint8(1); int(value) <= size; value++ {
+		data.Digits = append(data.Digits, geometry.ValueText(value))
+	}
+	givens := []byte(data.Values)
+	for idx := range givens {
+		id := geometry.CellID(uint8(idx/size+1), uint8(idx%size+1))
+		c, err := g.Cell(id)
+		if err == nil && c.SolutionCell() {
+			givens[idx] = structures.EmptyCellTextValue[0]
+		}
+		if marks, ok := g.PencilMarks(id); ok && err != nil {
+			for _, value := range marks {
+				data.Marks[idx] = append(data.Marks[idx], int(value))
+			}
+		}
+	}
+	data.Givens = string(givens)
+	for _, c := range g.Cages() {
+		cage := widgetCage{Sum: c.Sum}
+		for _, id := range c.Cells {
+			cell, err := geometry.NewCell(id, structures.EmptyCellValue)
+			if err != nil {
+				return err
+			}
+			cage.Cells = append(cage.Cells, int(cell.Row()-1)*size+int(cell.Column()-1))
+		}
+		data.Cages = append(data.Cages, cage)
+	}
+	for _, id := range variantCells(g) {
+		cell, err := geometry.NewCell(id, structures.EmptyCellValue)
+		if err != nil {
+			return err
+		}
+		data.Shaded = append(data.Shaded, int(cell.Row()-1)*size+int(cell.Column()-1))
+	}
+	if opts.Solution != nil {
+		if opts.Solution.EmptyCellCount() != 0 {
+			return ErrHTMLIncompleteSolution
+		}
+		data.Solution = opts.Solution.Line()
+	}
+	title := opts.Title
+	if title == "" {
+		title = "Sudoku"
+	}
+	steps := make([]string, len(opts.Steps))
+	for idx, step := range opts.Steps {
+		steps[idx] = stepText(step, geometry)
+	}
+	return widget.Execute(w, struct {
+		Title string
+		Data  widgetData
+		Steps []string
+	}{title, data, steps})
+}
+
+//stepText returns step of solution transcript with values written like in the
+// grid (letters for values greater than 9), e.g. "a1 = 8 (candidates 3, 8)" or
+// "naked-pair, row 1: c1 -12".
+func stepText(step engine.Step, geometry *structures.Geometry) string {
+	if step.IsPlacement() {
+		candidates := make([]string, len(step.Candidates))
+		for idx, value := range step.Candidates {
+			candidates[idx] = geometry.ValueText(value)
+		}
+		return step.CellID + " = " + geometry.ValueText(step.Value) + " (candidates " +
+			strings.Join(candidates, ", ") + ")"
+	}
+	source := step.Strategy
+	if step.Unit != "" {
+		source += ", " + step.Unit
+	}
+	removed := make([]string, len(step.Eliminations))
+	for idx, e := range step.Eliminations {
+		values := ""
+		for _, value := range e.Values {
+			values += geometry.ValueText(value)
+		}
+		removed[idx] = e.CellID + " -" + values
+	}
+	return source + ": " + strings.Join(removed, ", ")
+}
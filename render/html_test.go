@@ -0,0 +1,162 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const singlesGameLine string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+
+func TestHTMLWithSolutionAndSteps(t *testing.T) {
+	g, err := structures.ParseLine(singlesGameLine)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	solved, _ := structures.ParseLine(singlesGameLine)
+	e := engine.NewEngine(solved)
+	if _, err = e.Run(); err != nil {
+		t.Errorf("Engine.Run should pass, but err: %v", err)
+	}
+	var buffer bytes.Buffer
+	err = HTML(&buffer, g, HTMLOptions{Title: "Daily <easy>", Solution: solved, Steps: e.Result().Steps})
+	if err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	page := buffer.String()
+	expected := []string{
+		"<title>Daily &lt;easy&gt;</title>",
+		`"givens":"` + singlesGameLine + `"`,
+		`"solution":"` + solved.Line() + `"`,
+		"<li>a1 = 8 (candidates 8)</li>",
+		`<button type="button" id="check">Check</button>`,
+	}
+	for _, text := range expected {
+		if !strings.Contains(page, text) {
+			t.Errorf("HTML should contain %s", text)
+		}
+	}
+}
+
+func TestHTMLEliminationStep(t *testing.T) {
+	step := engine.Step{Strategy: "naked-pair", Unit: "row 1",
+		Eliminations: []engine.Elimination{{CellID: "c1", Values: structures.Values{1, 2}}}}
+	var buffer bytes.Buffer
+	if err := HTML(&buffer, createGame(t), HTMLOptions{Steps: []engine.Step{step}}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	if expected := "<li>naked-pair, row 1: c1 -12</li>"; !strings.Contains(buffer.String(), expected) {
+		t.Errorf("HTML should contain %s", expected)
+	}
+}
+
+func TestHTMLLetterValueSteps(t *testing.T) {
+	g, err := structures.ParseLine(strings.Repeat(".", 256))
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	steps := []engine.Step{
+		{Strategy: "naked-single", CellID: "a1", Value: 10, Candidates: structures.Values{10}},
+		{Strategy: "naked-pair", Unit: "row 1", Eliminations: []engine.Elimination{{CellID: "c1",
+			Values: structures.Values{1, 16}}}},
+	}
+	var buffer bytes.Buffer
+	if err = HTML(&buffer, g, HTMLOptions{Steps: steps}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	for _, expected := range []string{"<li>a1 = A (candidates A)</li>", "<li>naked-pair, row 1: c1 -1G</li>"} {
+		if !strings.Contains(buffer.String(), expected) {
+			t.Errorf("HTML should contain %s", expected)
+		}
+	}
+}
+
+func TestHTMLWithoutSolution(t *testing.T) {
+	g := createGame(t)
+	g.SetPencilMarks("c1", []uint8{3, 7})
+	var buffer bytes.Buffer
+	if err := HTML(&buffer, g, HTMLOptions{}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	page := buffer.String()
+	expected := []string{
+		"<title>Sudoku</title>",
+		`"givens":"` + game1Line + `"`,
+		`"values":"` + g.Line() + `"`,
+		`"marks":{"2":[3,7]}`,
+		`id="check" disabled>`,
+	}
+	for _, text := range expected {
+		if !strings.Contains(page, text) {
+			t.Errorf("HTML should contain %s", text)
+		}
+	}
+	if strings.Contains(page, "<details>") {
+		t.Error("HTML should not contain solution steps.")
+	}
+	if err := HTML(&buffer, g, HTMLOptions{Solution: g}); !errors.Is(err, ErrHTMLIncompleteSolution) {
+		t.Errorf("HTML should return ErrHTMLIncompleteSolution, but returned: %v", err)
+	}
+}
+
+func TestHTMLGeometry(t *testing.T) {
+	g, err := structures.ParseLine("1....2...3.2....")
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	var buffer bytes.Buffer
+	if err = HTML(&buffer, g, HTMLOptions{}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	page := buffer.String()
+	expected := []string{
+		`"size":4,"boxRows":2,"boxColumns":2,"digits":["1","2","3","4"]`,
+		`data-digit="4">4</button>`,
+	}
+	for _, text := range expected {
+		if !strings.Contains(page, text) {
+			t.Errorf("HTML should contain %s", text)
+		}
+	}
+	if strings.Contains(page, `data-digit="5"`) {
+		t.Error("HTML of 4x4 game should not contain digit 5.")
+	}
+}
+
+func TestHTMLCages(t *testing.T) {
+	g, err := structures.ReadKiller(strings.NewReader("3: a1 b1\n17: i8 i9\n"))
+	if err != nil {
+		t.Errorf("Killer game should be succesfully created, but err: %v", err)
+		return
+	}
+	var buffer bytes.Buffer
+	if err = HTML(&buffer, g, HTMLOptions{}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	expected := `"cages":[{"sum":3,"cells":[0,1]},{"sum":17,"cells":[71,80]}]`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("HTML should contain %s", expected)
+	}
+}
+
+func TestHTMLVariant(t *testing.T) {
+	g, err := structures.ParseLine("1....2...3.2....")
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	g.SetVariant(structures.VariantDiagonal)
+	var buffer bytes.Buffer
+	if err = HTML(&buffer, g, HTMLOptions{}); err != nil {
+		t.Errorf("HTML should be written, but err: %v", err)
+	}
+	expected := `"shaded":[0,5,10,15,3,6,9,12]`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("HTML should contain %s", expected)
+	}
+}
@@ -0,0 +1,123 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+//Metrics of standard PDF Helvetica font (in 1/1000 of font size).
+const (
+	helveticaDigitWidth float64 = 556
+	helveticaCapHeight  float64 = 718
+	helveticaAvgWidth   float64 = 520
+)
+
+//Names of standard PDF fonts, they do not have to be embedded.
+const (
+	pdfFontRegular string = "F1"
+	pdfFontBold    string = "F2"
+)
+
+//pdfPage represents one page of PDF document, it contains drawing commands
+// in page coordinates with origin in top left corner.
+type pdfPage struct {
+	height  float64
+	content strings.Builder
+}
+
+//drawScene draws scene with its top left corner at x, y.
+func (p *pdfPage) drawScene(s *scene, x float64, y float64) {
+	for _, r := range s.rects {
+		p.fillRect(x+r.x, y+r.y, r.w, r.h, r.fill)
+	}
+	for _, t := range s.texts {
+		font := pdfFontRegular
+		if t.bold {
+			font = pdfFontBold
+		}
+		width := float64(len(t.value)) * helveticaDigitWidth * t.size / 1000
+		baseline := y + t.y + helveticaCapHeight*t.size/1000/2
+		p.text(x+t.x-width/2, baseline, t.size, font, t.value, t.fill)
+	}
+}
+
+func (p *pdfPage) fillRect(x float64, y float64, w float64, h float64, fill color.RGBA) {
+	fmt.Fprintf(&p.content, "%s rg %s %s %s %s re f\n", pdfColor(fill), number(x),
+		number(p.height-y-h), number(w), number(h))
+}
+
+//text writes text with baseline at y.
+func (p *pdfPage) text(x float64, y float64, size float64, font string, value string, fill color.RGBA) {
+	fmt.Fprintf(&p.content, "BT %s rg /%s %s Tf %s %s Td (%s) Tj ET\n", pdfColor(fill), font,
+		number(size), number(x), number(p.height-y), pdfString(value))
+}
+
+//centeredText writes text centered at x, width is estimated from average glyph width.
+func (p *pdfPage) centeredText(x float64, y float64, size float64, font string, value string) {
+	width := float64(len(value)) * helveticaAvgWidth * size / 1000
+	p.text(x-width/2, y, size, font, value, ColorLine)
+}
+
+//writePDF writes pages as PDF document, all pages have the same size.
+func writePDF(w io.Writer, pages []*pdfPage, width float64, height float64) error {
+	bw := bufio.NewWriter(w)
+	offset := 0
+	var offsets []int
+	write := func(text string) {
+		n, _ := bw.WriteString(text)
+		offset += n
+	}
+	object := func(body string) {
+		offsets = append(offsets, offset)
+		write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", len(offsets), body))
+	}
+	write("%PDF-1.4\n")
+	// objects 1-4: catalog, pages, fonts; every page has page and content object.
+	kids := make([]string, len(pages))
+	for idx := range pages {
+		kids[idx] = fmt.Sprintf("%d 0 R", 5+2*idx)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(pages), number(width), number(height)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for idx, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R /Resources "+
+			"<< /Font << /%s 3 0 R /%s 4 0 R >> >> >>", 6+2*idx, pdfFontRegular, pdfFontBold))
+		content := page.content.String()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+	xref := offset
+	write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1))
+	for _, o := range offsets {
+		write(fmt.Sprintf("%010d 00000 n \n", o))
+	}
+	write(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref))
+	return bw.Flush()
+}
+
+//pdfColor returns color as PDF RGB operands.
+func pdfColor(c color.RGBA) string {
+	return fmt.Sprintf("%s %s %s", number(float64(c.R)/255), number(float64(c.G)/255), number(float64(c.B)/255))
+}
+
+//pdfString escapes text for PDF string literal, characters outside ASCII are replaced by ?.
+func pdfString(value string) string {
+	var result strings.Builder
+	for _, char := range value {
+		switch {
+		case char == '(' || char == ')' || char == '\\':
+			result.WriteRune('\\')
+			result.WriteRune(char)
+		case char < 32 || char > 126:
+			result.WriteRune('?')
+		default:
+			result.WriteRune(char)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,67 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//checkPDFStructure checks that xref table points to all objects of PDF document.
+func checkPDFStructure(t *testing.T, pdf []byte) {
+	text := string(pdf)
+	if !strings.HasPrefix(text, "%PDF-1.4\n") || !strings.HasSuffix(text, "%%EOF\n") {
+		t.Error("PDF should start with header and end with EOF marker.")
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(text)
+	if startxref == nil {
+		t.Error("PDF should contain startxref.")
+		return
+	}
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(text[xref:], "xref\n") {
+		t.Errorf("PDF startxref %d does not point to xref table", xref)
+		return
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(text[xref:], -1)
+	for idx, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		expected := fmt.Sprintf("%d 0 obj\n", idx+1)
+		if !strings.HasPrefix(text[offset:], expected) {
+			t.Errorf("PDF xref entry %d points to %q", idx+1, text[offset:offset+10])
+		}
+	}
+	for _, stream := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllStringSubmatch(text, -1) {
+		if length, _ := strconv.Atoi(stream[1]); length != len(stream[2]) {
+			t.Errorf("PDF stream has length %d, but declared %d", len(stream[2]), length)
+		}
+	}
+}
+
+func TestPDFWriteStructure(t *testing.T) {
+	page := &pdfPage{height: 100}
+	page.fillRect(10, 10, 20, 20, ColorHighlight)
+	page.text(10, 50, 12, pdfFontBold, "Title (1)", ColorLine)
+	var buffer bytes.Buffer
+	if err := writePDF(&buffer, []*pdfPage{page, {height: 100}}, 100, 100); err != nil {
+		t.Errorf("PDF should be written, but err: %v", err)
+	}
+	checkPDFStructure(t, buffer.Bytes())
+	if !strings.Contains(buffer.String(), "/Count 2") {
+		t.Error("PDF should have 2 pages.")
+	}
+	if !strings.Contains(buffer.String(), "1 0.9 0.5 rg 10 70 20 20 re f") {
+		t.Errorf("PDF should contain highlighted rectangle in bottom left coordinates:\n%s", buffer.String())
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := map[string]string{"Title (1)": `Title \(1\)`, `a\b`: `a\\b`, "Sudoku č": "Sudoku ?"}
+	for input, expected := range tests {
+		if got := pdfString(input); got != expected {
+			t.Errorf("pdfString(%q) = %q, but expected: %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Image returns game as raster image.
+func Image(g *structures.Game, opts Options) (*image.RGBA, error) {
+	s, err := newScene(g, opts)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(s.width)), int(math.Ceil(s.height))))
+	for _, r := range s.rects {
+		fillRect(img, r)
+	}
+	for _, t := range s.texts {
+		drawText(img, t)
+	}
+	return img, nil
+}
+
+//PNG writes game as PNG image.
+func PNG(w io.Writer, g *structures.Game, opts Options) error {
+	img, err := Image(g, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+func fillRect(img *image.RGBA, r rect) {
+	bounds := image.Rect(int(math.Round(r.x)), int(math.Round(r.y)),
+		int(math.Round(r.x+r.w)), int(math.Round(r.y+r.h)))
+	draw.Draw(img, bounds, image.NewUniform(r.fill), image.Point{}, draw.Src)
+}
+
+//drawText draws text with bitmap font, glyph height is scaled to 70 % of text size
+// and bold text is drawn with wider strokes.
+func drawText(img *image.RGBA, t text) {
+	pixel := math.Floor(t.size * 0.7 / float64(glyphHeight))
+	if pixel < 1 {
+		pixel = 1
+	}
+	stroke := pixel
+	if t.bold {
+		stroke = pixel + math.Max(1, math.Floor(pixel/2))
+	}
+	runes := []rune(t.value)
+	spacing := pixel
+	width := float64(len(runes))*float64(glyphWidth)*pixel + float64(len(runes)-1)*spacing
+	x := math.Round(t.x - width/2)
+	y := math.Round(t.y - float64(glyphHeight)*pixel/2)
+	for _, char := range runes {
+		glyph, ok := glyphs[char]
+		if ok {
+			for row, line := range glyph {
+				for col, dot := range line {
+					if dot == '#' {
+						fillRect(img, rect{x + float64(col)*pixel, y + float64(row)*pixel, stroke, pixel, t.fill})
+					}
+				}
+			}
+		}
+		x += float64(glyphWidth)*pixel + spacing
+	}
+}
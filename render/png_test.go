@@ -0,0 +1,71 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestPNGDecodes(t *testing.T) {
+	g := createGame(t)
+	var buffer bytes.Buffer
+	if err := PNG(&buffer, g, Options{CellSize: 30}); err != nil {
+		t.Errorf("PNG should be written, but err: %v", err)
+	}
+	img, err := png.Decode(&buffer)
+	if err != nil {
+		t.Errorf("PNG should be decoded, but err: %v", err)
+		return
+	}
+	if img.Bounds().Dx() != 9*30+2*2 || img.Bounds().Dy() != 9*30+2*2 {
+		t.Errorf("PNG size is %v, but expected: %d", img.Bounds(), 9*30+2*2)
+	}
+}
+
+func TestPNGHighlightColor(t *testing.T) {
+	g := createGame(t)
+	img, err := Image(g, Options{CellSize: 48, Highlights: []Highlight{{CellID: "c1"}}})
+	if err != nil {
+		t.Errorf("Image should be created, but err: %v", err)
+	}
+	// center of c1 is empty highlighted cell.
+	r, gr, b, _ := img.At(3+2*48+24, 3+24).RGBA()
+	if uint8(r>>8) != ColorHighlight.R || uint8(gr>>8) != ColorHighlight.G || uint8(b>>8) != ColorHighlight.B {
+		t.Errorf("Highlighted cell has color %d,%d,%d", r>>8, gr>>8, b>>8)
+	}
+	// center of a1 is part of given 8.
+	r, _, _, _ = img.At(3+24, 3+24).RGBA()
+	if r != 0 {
+		t.Errorf("Given digit should be black, but red component is %d", r>>8)
+	}
+}
+
+func TestPNGLetterValues(t *testing.T) {
+	g, err := structures.ParseLine("ABCDEFG" + strings.Repeat(".", 249))
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	img, err := Image(g, Options{CellSize: 48})
+	if err != nil {
+		t.Errorf("Image should be created, but err: %v", err)
+		return
+	}
+	for column := 0; column < 7; column++ {
+		// inside of the cell without its borders.
+		ink := 0
+		for x := 3 + column*48 + 6; x < 3+(column+1)*48-6; x++ {
+			for y := 3 + 6; y < 3+48-6; y++ {
+				if r, _, _, _ := img.At(x, y).RGBA(); r>>8 < 128 {
+					ink++
+				}
+			}
+		}
+		if ink == 0 {
+			t.Errorf("Cell of value %c should have ink pixels", 'A'+column)
+		}
+	}
+}
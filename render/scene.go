@@ -0,0 +1,276 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Default colors of rendered game.
+var (
+	ColorBackground color.RGBA = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	ColorLine       color.RGBA = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	ColorGiven      color.RGBA = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+	ColorEntry      color.RGBA = color.RGBA{R: 31, G: 79, B: 191, A: 255}
+	ColorPencilMark color.RGBA = color.RGBA{R: 96, G: 96, B: 96, A: 255}
+	ColorHighlight  color.RGBA = color.RGBA{R: 255, G: 230, B: 128, A: 255}
+	ColorCage       color.RGBA = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	ColorVariant    color.RGBA = color.RGBA{R: 224, G: 224, B: 224, A: 255}
+)
+
+//Sizes of cage outline and sum relative to cell size.
+const (
+	cageInset   float64 = 0.08
+	cageDash    float64 = 0.12
+	cageGap     float64 = 0.08
+	cageSumSize float64 = 0.26
+)
+
+//Highlight represents highlighted cell (Value is 0) or candidate of the cell.
+type Highlight struct {
+	CellID string
+	Value  uint8
+	Color  color.RGBA
+}
+
+//Options represents options of rendering.
+type Options struct {
+	CellSize    int
+	PencilMarks bool
+	Highlights  []Highlight
+}
+
+//DefaultOptions returns options used when no options are specified.
+func DefaultOptions() Options {
+	return Options{CellSize: 48}
+}
+
+//rect represents filled rectangle.
+type rect struct {
+	x, y, w, h float64
+	fill       color.RGBA
+}
+
+//text represents text centered at x, y.
+type text struct {
+	x, y, size float64
+	value      string
+	bold       bool
+	fill       color.RGBA
+}
+
+//scene represents game drawing independent on output format, all lines are
+// axis aligned so they are stored as rectangles.
+type scene struct {
+	width, height float64
+	rects         []rect
+	texts         []text
+}
+
+//newScene creates drawing of the game, pencil marks are laid out in cell as
+// cells in square of the grid. Cells of diagonals and windows of game variant
+// are shaded. Cages of killer game are drawn as dashed outlines inside their
+// cells with sum in the top left corner.
+func newScene(g *structures.Game, opts Options) (*scene, error) {
+	if opts.CellSize <= 0 {
+		opts.CellSize = DefaultOptions().CellSize
+	}
+	cell := float64(opts.CellSize)
+	thick := cell / 16
+	if thick < 2 {
+		thick = 2
+	}
+	thin := thick / 3
+	margin := thick
+	geometry := g.Geometry()
+	size := int(geometry.Size)
+	grid := float64(size) * cell
+	// pencil mark of value v is in sub-cell (v-1)%columns, (v-1)/columns.
+	columns := int(geometry.BoxColumns)
+	subWidth, subHeight := cell/float64(columns), cell/float64(geometry.BoxRows)
+	markSize := subWidth
+	if subHeight < markSize {
+		markSize = subHeight
+	}
+	s := scene{width: grid + 2*margin, height: grid + 2*margin}
+	s.rects = append(s.rects, rect{0, 0, s.width, s.height, ColorBackground})
+	for _, id := range variantCells(g) {
+		c, err := geometry.NewCell(id, 0)
+		if err != nil {
+			return nil, err
+		}
+		x := margin + float64(c.Column()-1)*cell
+		y := margin + float64(c.Row()-1)*cell
+		s.rects = append(s.rects, rect{x, y, cell, cell, ColorVariant})
+	}
+
+	candidates, err := g.Candidates()
+	if err != nil {
+		return nil, err
+	}
+	highlighted := make(map[string]map[uint8]bool)
+	for _, h := range opts.Highlights {
+		c, err := geometry.NewCell(h.CellID, 0)
+		if err != nil {
+			return nil, err
+		}
+		fill := h.Color
+		if fill.A == 0 {
+			fill = ColorHighlight
+		}
+		x := margin + float64(c.Column()-1)*cell
+		y := margin + float64(c.Row()-1)*cell
+		if h.Value == 0 {
+			s.rects = append(s.rects, rect{x, y, cell, cell, fill})
+			continue
+		}
+		sx := x + float64(int(h.Value-1)%columns)*subWidth
+		sy := y + float64(int(h.Value-1)/columns)*subHeight
+		s.rects = append(s.rects, rect{sx, sy, subWidth, subHeight, fill})
+		if highlighted[c.Id] == nil {
+			highlighted[c.Id] = make(map[uint8]bool)
+		}
+		highlighted[c.Id][h.Value] = true
+	}
+
+	for r := 1; r <= size; r++ {
+		for col := 1; col <= size; col++ {
+			id := geometry.CellID(uint8(r), uint8(col))
+			x := margin + float64(col-1)*cell
+			y := margin + float64(r-1)*cell
+			if c, err := g.Cell(id); err == nil && c.Value() != structures.EmptyCellValue {
+				fill := ColorGiven
+				if c.SolutionCell() {
+					fill = ColorEntry
+				}
+				s.texts = append(s.texts, text{x + cell/2, y + cell/2, cell * 0.7, c.TextValue(),
+					!c.SolutionCell(), fill})
+				continue
+			}
+			for _, value := range candidates[id] {
+				if !opts.PencilMarks && !highlighted[id][value] {
+					continue
+				}
+				sx := x + float64(int(value-1)%columns)*subWidth + subWidth/2
+				sy := y + float64(int(value-1)/columns)*subHeight + subHeight/2
+				s.texts = append(s.texts, text{sx, sy, markSize * 0.7, geometry.ValueText(value), false,
+					ColorPencilMark})
+			}
+		}
+	}
+
+	for _, c := range g.Cages() {
+		if err := s.addCage(geometry, c, margin, cell, math.Max(thin, 1)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i <= size; i++ {
+		vertical, horizontal := thin, thin
+		if i%columns == 0 {
+			vertical = thick
+		}
+		if i%int(geometry.BoxRows) == 0 {
+			horizontal = thick
+		}
+		pos := margin + float64(i)*cell
+		s.rects = append(s.rects, rect{pos - vertical/2, margin - thick/2, vertical, grid + thick, ColorLine})
+		s.rects = append(s.rects, rect{margin - thick/2, pos - horizontal/2, grid + thick, horizontal, ColorLine})
+	}
+	return &s, nil
+}
+
+//variantCells returns ids of cells in diagonals and windows of game variant,
+// every cell only once.
+func variantCells(g *structures.Game) []string {
+	var result []string
+	found := make(map[string]bool)
+	for _, u := range g.Units() {
+		switch u.Type {
+		case structures.UnitRow, structures.UnitColumn, structures.UnitSquare:
+			continue
+		}
+		for _, id := range u.Cells {
+			if !found[id] {
+				found[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+//addCage method draws dashed outline of the cage and its sum. Outline is inset
+// into cells, so neighbouring cages are separated.
+func (s *scene) addCage(geometry *structures.Geometry, c *structures.Cage, margin float64, cell float64,
+	width float64) error {
+	// in contains rows and columns of cage cells.
+	in := make(map[[2]int]bool)
+	positions := make([][2]int, len(c.Cells))
+	for idx, id := range c.Cells {
+		cc, err := geometry.NewCell(id, structures.EmptyCellValue)
+		if err != nil {
+			return err
+		}
+		positions[idx] = [2]int{int(cc.Row()), int(cc.Column())}
+		in[positions[idx]] = true
+	}
+	inset := cell * cageInset
+	for _, position := range positions {
+		r, col := position[0], position[1]
+		x, y := margin+float64(col-1)*cell, margin+float64(r-1)*cell
+		// sides are given by direction to the neighbour cell, the side is drawn
+		// when neighbour is not in the cage.
+		for _, side := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			if in[[2]int{r + side[0], col + side[1]}] {
+				continue
+			}
+			along := [2]int{side[1] * side[1], side[0] * side[0]}
+			start, line := x, y+inset
+			switch side {
+			case [2]int{1, 0}:
+				line = y + cell - inset
+			case [2]int{0, -1}:
+				start, line = y, x+inset
+			case [2]int{0, 1}:
+				start, line = y, x+cell-inset
+			}
+			from := start + cageEnd(in, r, col, side, along, -1, inset)
+			to := start + cell + cageEnd(in, r, col, side, along, 1, inset)
+			for pos := from; pos < to; pos += cell * (cageDash + cageGap) {
+				length := math.Min(cell*cageDash, to-pos)
+				if along[1] == 1 {
+					s.rects = append(s.rects, rect{pos, line - width/2, length, width, ColorCage})
+				} else {
+					s.rects = append(s.rects, rect{line - width/2, pos, width, length, ColorCage})
+				}
+			}
+		}
+	}
+	label := strconv.Itoa(c.Sum)
+	size := cell * cageSumSize
+	x := margin + float64(positions[0][1]-1)*cell + inset
+	y := margin + float64(positions[0][0]-1)*cell + inset
+	w := size * (0.6*float64(len(label)) + 0.3)
+	s.rects = append(s.rects, rect{x - width, y - width, w + width, size + width, ColorBackground})
+	s.texts = append(s.texts, text{x + w/2, y + size/2, size, label, false, ColorCage})
+	return nil
+}
+
+//cageEnd returns shift of the end of the cage side from the cell corner, dir
+// is -1 for start and 1 for end of the side. Side ends inside the cell when
+// the next cell along it is not in the cage, it goes over the corner to the
+// side of diagonal cell when both next and diagonal cells are in the cage,
+// otherwise it ends in the corner and continues by side of the next cell.
+func cageEnd(in map[[2]int]bool, r int, col int, side [2]int, along [2]int, dir int, inset float64) float64 {
+	next := [2]int{r + dir*along[0], col + dir*along[1]}
+	switch {
+	case !in[next]:
+		return -float64(dir) * inset
+	case in[[2]int{next[0] + side[0], next[1] + side[1]}]:
+		return float64(dir) * inset
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+const game1Line string = "8..94...5....5.2..1.96.2...5.1.....446.....532.....8.1...4.91.7..4.6....9...17..6"
+
+func createGame(t *testing.T) *structures.Game {
+	g, err := structures.ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	c, _ := structures.NewSolutionCell("b1", 2)
+	g.AddCell(c)
+	return g
+}
+
+func TestSceneTexts(t *testing.T) {
+	g := createGame(t)
+	s, err := newScene(g, DefaultOptions())
+	if err != nil {
+		t.Errorf("Scene should be created, but err: %v", err)
+	}
+	if len(s.texts) != 31 {
+		t.Errorf("Scene has %d texts, but expected: %d", len(s.texts), 31)
+	}
+	bold := 0
+	for _, text := range s.texts {
+		if text.bold {
+			bold++
+		}
+	}
+	if bold != 30 {
+		t.Errorf("Scene has %d bold texts (givens), but expected: %d", bold, 30)
+	}
+	if s.width != 9*48+2*3 {
+		t.Errorf("Scene width is %v, but expected: %v", s.width, 9*48+2*3)
+	}
+}
+
+func TestScenePencilMarksAndHighlights(t *testing.T) {
+	g := createGame(t)
+	opts := DefaultOptions()
+	opts.Highlights = []Highlight{{CellID: "c1"}, {CellID: "f1", Value: 3}}
+	s, err := newScene(g, opts)
+	if err != nil {
+		t.Errorf("Scene should be created, but err: %v", err)
+	}
+	// background, two highlights and 20 grid lines.
+	if len(s.rects) != 23 {
+		t.Errorf("Scene has %d rectangles, but expected: %d", len(s.rects), 23)
+	}
+	// highlighted candidate is drawn even without pencil marks.
+	if len(s.texts) != 32 {
+		t.Errorf("Scene has %d texts, but expected: %d", len(s.texts), 32)
+	}
+	opts.PencilMarks = true
+	s, _ = newScene(g, opts)
+	candidates, _ := g.Candidates()
+	expected := 31
+	for _, values := range candidates {
+		expected += len(values)
+	}
+	if len(s.texts) != expected {
+		t.Errorf("Scene has %d texts, but expected: %d", len(s.texts), expected)
+	}
+	opts.Highlights = []Highlight{{CellID: "x1"}}
+	if _, err = newScene(g, opts); err == nil {
+		t.Error("Scene with invalid highlight should return error.")
+	}
+}
+
+func TestSceneGeometry(t *testing.T) {
+	g, err := structures.ParseLine(".......4..61.5....3...2.......2.6.3.")
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	opts := DefaultOptions()
+	opts.PencilMarks = true
+	opts.Highlights = []Highlight{{CellID: "f6", Value: 6}}
+	s, err := newScene(g, opts)
+	if err != nil {
+		t.Errorf("Scene should be created, but err: %v", err)
+	}
+	if s.width != 6*48+2*3 {
+		t.Errorf("Scene width is %v, but expected: %v", s.width, 6*48+2*3)
+	}
+	// background, highlight and 14 grid lines.
+	if len(s.rects) != 16 {
+		t.Errorf("Scene has %d rectangles, but expected: %d", len(s.rects), 16)
+	}
+	// pencil marks are in 3 columns and 2 rows, 6 is in the bottom right corner.
+	highlight := s.rects[1]
+	if highlight.x != 3+5*48+32 || highlight.y != 3+5*48+24 || highlight.w != 16 || highlight.h != 24 {
+		t.Errorf("Highlight of candidate is %v, but expected in the bottom right corner of f6", highlight)
+	}
+	thick := 0
+	for _, r := range s.rects[2:] {
+		if r.w == 3 || r.h == 3 {
+			thick++
+		}
+	}
+	// 3 vertical lines (every 3 columns) and 4 horizontal lines (every 2 rows).
+	if thick != 7 {
+		t.Errorf("Scene has %d thick lines, but expected: %d", thick, 7)
+	}
+}
+
+func TestSceneCages(t *testing.T) {
+	g, err := structures.NewGameFromCells(nil)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	if err = g.AddCage(&structures.Cage{Sum: 3, Cells: []string{"a1", "b1"}}); err != nil {
+		t.Errorf("Game.AddCage should pass, but err: %v", err)
+	}
+	s, err := newScene(g, DefaultOptions())
+	if err != nil {
+		t.Errorf("Scene should be created, but err: %v", err)
+		return
+	}
+	margin, cell := 3.0, 48.0
+	inset := cell * cageInset
+	dashes, left, right := 0, false, false
+	for _, r := range s.rects {
+		if r.fill != ColorCage {
+			continue
+		}
+		dashes++
+		if r.x < margin || r.y < margin || r.x+r.w > margin+2*cell || r.y+r.h > margin+cell {
+			t.Errorf("Cage dash %v should be inside cells a1 and b1", r)
+		}
+		left = left || r.x+r.w/2 == margin+inset
+		right = right || r.x+r.w/2 == margin+2*cell-inset
+	}
+	if dashes == 0 || !left || !right {
+		t.Errorf("Cage should be drawn by dashes on both sides, but has %d dashes", dashes)
+	}
+	if len(s.texts) != 1 || s.texts[0].value != "3" || s.texts[0].fill != ColorCage {
+		t.Errorf("Scene should contain only cage sum 3, but texts are: %v", s.texts)
+	}
+}
+
+func TestSceneVariant(t *testing.T) {
+	g := createGame(t)
+	g.SetVariant(structures.VariantDiagonal | structures.VariantHyper)
+	s, err := newScene(g, DefaultOptions())
+	if err != nil {
+		t.Errorf("Scene should be created, but err: %v", err)
+		return
+	}
+	shaded := 0
+	for _, r := range s.rects {
+		if r.fill == ColorVariant {
+			shaded++
+		}
+	}
+	// 17 cells of diagonals and 36 cells of windows, 12 of them are on diagonals.
+	if shaded != 41 || s.rects[1].fill != ColorVariant {
+		t.Errorf("Scene has %d shaded cells, but expected 41 below other drawing", shaded)
+	}
+}
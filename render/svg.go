@@ -0,0 +1,48 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//SVG writes game as SVG image.
+func SVG(w io.Writer, g *structures.Game, opts Options) error {
+	s, err := newScene(g, opts)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		number(s.width), number(s.height), number(s.width), number(s.height))
+	for _, r := range s.rects {
+		fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+			number(r.x), number(r.y), number(r.w), number(r.h), hexColor(r.fill))
+	}
+	for _, t := range s.texts {
+		weight := "normal"
+		if t.bold {
+			weight = "bold"
+		}
+		fmt.Fprintf(bw, `<text x="%s" y="%s" font-family="sans-serif" font-size="%s" font-weight="%s" `+
+			`fill="%s" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+			number(t.x), number(t.y), number(t.size), weight, hexColor(t.fill), t.value)
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+//number formats coordinate without unnecessary decimal places.
+func number(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+//hexColor returns color in #rrggbb format.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,45 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVGIsValidXML(t *testing.T) {
+	g := createGame(t)
+	var buffer bytes.Buffer
+	opts := DefaultOptions()
+	opts.PencilMarks = true
+	if err := SVG(&buffer, g, opts); err != nil {
+		t.Errorf("SVG should be written, but err: %v", err)
+	}
+	svg := buffer.String()
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			if err != io.EOF {
+				t.Errorf("SVG should be valid XML, but err: %v", err)
+			}
+			break
+		}
+	}
+	if !strings.Contains(svg, `font-weight="bold" fill="#000000" text-anchor="middle" dominant-baseline="central">8</text>`) {
+		t.Error("SVG should contain bold given 8.")
+	}
+	if !strings.Contains(svg, `font-weight="normal" fill="#1f4fbf" text-anchor="middle" dominant-baseline="central">2</text>`) {
+		t.Error("SVG should contain normal entry 2.")
+	}
+}
+
+func TestSVGNumber(t *testing.T) {
+	tests := map[float64]string{1.5: "1.5", 48: "48", 16.666666: "16.67"}
+	for value, expected := range tests {
+		if got := number(value); got != expected {
+			t.Errorf("number(%v) = %s, but expected: %s", value, got, expected)
+		}
+	}
+}
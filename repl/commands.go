@@ -0,0 +1,298 @@
+package repl
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//command represents one REPL command.
+type command struct {
+	name      string
+	args      string
+	help      string
+	needsGame bool
+	run       func(r *REPL, args []string) error
+}
+
+//commands returns all REPL commands in order shown by help.
+func commands() []command {
+	return []command{
+		{"help", "", "show commands", false, runHelp},
+		{"load", "<file|line>", "load game from file or from single line (81 characters for 9x9)", false, runLoad},
+		{"show", "[cands]", "show game, with cands show candidates of empty cells", true, runShow},
+		{"cands", "<cell>", "show candidates of cell", true, runCands},
+		{"set", "<cell> <value>", "put value into cell, value 0 clears it", true, runSet},
+		{"step", "[count]", "make next steps by selected strategies", true, runStep},
+		{"hint", "", "show next step without making it", true, runHint},
+		{"run", "", "make steps until strategies find no step", true, runRun},
+		{"solve", "", "solve game with guessing and backtracking", true, runSolve},
+		{"strategy", "[names|all]", "show or select comma separated strategies", false, runStrategy},
+		{"plan", "", "show plan of guesses made by solve", true, runPlan},
+		{"steps", "", "list steps made by engine", true, runSteps},
+		{"explain", "", "explain last step", true, runExplain},
+		{"undo", "", "return state before last change", true, runUndo},
+		{"quit", "", "end session", false, nil},
+	}
+}
+
+func runHelp(r *REPL, args []string) error {
+	for _, c := range commands() {
+		r.printf("  %-9s %-15s %s\n", c.name, c.args, c.help)
+	}
+	return nil
+}
+
+func runLoad(r *REPL, args []string) error {
+	if len(args) != 1 {
+		return ErrArguments
+	}
+	var g *structures.Game
+	f, err := os.Open(args[0])
+	if err == nil {
+		defer f.Close()
+		g, err = structures.Load(f)
+	} else if isLine(args[0]) {
+		g, err = structures.ParseLine(args[0])
+	}
+	if err != nil {
+		return err
+	}
+	r.Load(g)
+	r.printf("%s", g.GameVisual())
+	return nil
+}
+
+func runShow(r *REPL, args []string) error {
+	switch {
+	case len(args) == 0:
+		r.printf("%s", r.engine.Game().GameVisual())
+	case len(args) == 1 && args[0] == "cands":
+		g := r.engine.Game().Clone()
+		for id, values := range r.engine.Candidates() {
+			if err := g.SetPencilMarks(id, values); err != nil {
+				return err
+			}
+		}
+		r.printf("%s", g.CandidateVisual())
+	default:
+		return ErrArguments
+	}
+	return nil
+}
+
+func runCands(r *REPL, args []string) error {
+	if len(args) != 1 {
+		return ErrArguments
+	}
+	cell, err := r.engine.Game().Geometry().NewCell(args[0], structures.EmptyCellValue)
+	if err != nil {
+		return err
+	}
+	if c, err := r.engine.Game().Cell(cell.Id); err == nil {
+		r.printf("%s is filled with %s\n", cell.Id, c.TextValue())
+		return nil
+	}
+	r.printf("%s: %s\n", cell.Id, valuesText(r.engine.Candidates()[cell.Id], cell.Geometry()))
+	return nil
+}
+
+func runSet(r *REPL, args []string) error {
+	if len(args) != 2 {
+		return ErrArguments
+	}
+	g := r.engine.Game()
+	value, err := strconv.Atoi(args[1])
+	if err != nil || value < 0 || value > int(g.Geometry().Size) {
+		return ErrArguments
+	}
+	cell, err := g.Geometry().NewSolutionCell(args[0], uint8(value))
+	if err != nil {
+		return err
+	}
+	if c, err := g.Cell(cell.Id); err == nil && !c.SolutionCell() {
+		return &structures.DuplicateCellError{ID: cell.Id}
+	}
+	r.save()
+	g.RemoveSolutionCells([]string{cell.Id})
+	if value != 0 {
+		return g.AddCell(cell)
+	}
+	return nil
+}
+
+func runStep(r *REPL, args []string) error {
+	count := 1
+	if len(args) > 1 {
+		return ErrArguments
+	}
+	if len(args) == 1 {
+		var err error
+		if count, err = strconv.Atoi(args[0]); err != nil || count < 1 {
+			return ErrArguments
+		}
+	}
+	_, err := r.steps(count)
+	return err
+}
+
+//steps method makes steps like makeSteps, state is saved for undo only when
+// some step was made.
+func (r *REPL) steps(count int) (int, error) {
+	r.save()
+	made, err := r.makeSteps(count)
+	if made == 0 {
+		r.history = r.history[:len(r.history)-1]
+	}
+	return made, err
+}
+
+//makeSteps method makes at most count steps (without limit when count is 0)
+// and writes them, it returns count of made steps.
+func (r *REPL) makeSteps(count int) (int, error) {
+	for made := 0; count == 0 || made < count; made++ {
+		if r.engine.IsFinished() {
+			r.printf("game is solved\n")
+			return made, nil
+		}
+		step, err := r.engine.Hint()
+		if err != nil {
+			return made, err
+		}
+		if step == nil {
+			r.printf("no step found\n")
+			return made, nil
+		}
+		if _, err = r.engine.MakeStep(); err != nil {
+			return made, err
+		}
+		r.last = step
+		r.printf("%s\n", step)
+	}
+	return count, nil
+}
+
+func runHint(r *REPL, args []string) error {
+	step, err := r.engine.Hint()
+	if err != nil {
+		return err
+	}
+	if step == nil {
+		r.printf("no step found\n")
+		return nil
+	}
+	r.printf("%s\n", step)
+	return nil
+}
+
+func runRun(r *REPL, args []string) error {
+	made, err := r.steps(0)
+	r.printf("%d steps, %d empty cells\n", made, r.engine.Game().EmptyCellCount())
+	return err
+}
+
+func runSolve(r *REPL, args []string) error {
+	r.save()
+	result, err := r.engine.Solve(context.Background())
+	if err != nil {
+		return err
+	}
+	if len(result.Steps) > 0 {
+		r.last = &result.Steps[len(result.Steps)-1]
+	}
+	r.printf("solved in %d steps with %d guesses\n", len(result.Steps), result.Guesses)
+	return nil
+}
+
+func runStrategy(r *REPL, args []string) error {
+	if len(args) > 0 {
+		names := strings.Join(args, ",")
+		strategies := engine.Strategies()
+		if names != "all" {
+			var err error
+			if strategies, err = engine.ParseStrategies(names); err != nil {
+				return err
+			}
+		}
+		r.SetStrategies(strategies)
+	}
+	var selected, all []string
+	for _, s := range r.strategies {
+		selected = append(selected, s.Name())
+	}
+	for _, s := range engine.Strategies() {
+		all = append(all, s.Name())
+	}
+	r.printf("selected: %s\navailable: %s\n", strings.Join(selected, ", "), strings.Join(all, ", "))
+	return nil
+}
+
+func runPlan(r *REPL, args []string) error {
+	plan, err := r.engine.MakePlan()
+	if err != nil {
+		return err
+	}
+	text := plan.String()
+	if text == "" {
+		text = "plan is empty, it is created by solve command"
+	}
+	r.printf("%s\n", text)
+	return nil
+}
+
+func runSteps(r *REPL, args []string) error {
+	for idx, step := range r.engine.Result().Steps {
+		r.printf("%3d. %s\n", idx+1, step)
+	}
+	return nil
+}
+
+func runExplain(r *REPL, args []string) error {
+	if r.last == nil {
+		return ErrNoStep
+	}
+	r.printf("%s\n", r.last.Explanation())
+	return nil
+}
+
+func runUndo(r *REPL, args []string) error {
+	if len(r.history) == 0 {
+		return ErrNothingToUndo
+	}
+	r.engine = r.history[len(r.history)-1]
+	r.engine.SetStrategies(r.strategies)
+	r.history = r.history[:len(r.history)-1]
+	steps := r.engine.Result().Steps
+	r.last = nil
+	if len(steps) > 0 {
+		r.last = &steps[len(steps)-1]
+	}
+	r.printf("%s", r.engine.Game().GameVisual())
+	return nil
+}
+
+//isLine returns if text has length of game of some geometry in single line format.
+func isLine(text string) bool {
+	for _, g := range structures.Geometries() {
+		if len(text) == g.Cells() {
+			return true
+		}
+	}
+	return false
+}
+
+//valuesText returns sorted values in text format of the geometry, values greater than 9 are letters.
+func valuesText(values []uint8, geometry *structures.Geometry) string {
+	sorted := append([]uint8(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	texts := make([]string, len(sorted))
+	for idx, v := range sorted {
+		texts[idx] = geometry.ValueText(v)
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,108 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		lines    []string
+		expected string
+		err      error
+	}{
+		{[]string{"cands a1"}, "a1: 8\n", nil},
+		{[]string{"cands c1"}, "c1 is filled with 5\n", nil},
+		{[]string{"cands"}, "", ErrArguments},
+		{[]string{"set a1 8", "show"}, "8.5|.1.|6..", nil},
+		{[]string{"set c1 8"}, "", &structures.DuplicateCellError{}},
+		{[]string{"set a1 10"}, "", ErrArguments},
+		{[]string{"step 2", "explain"}, "b1 = 2: 2 is the only candidate of b1.\n", nil},
+		{[]string{"step 2", "undo", "steps"}, "", nil},
+		{[]string{"explain"}, "", ErrNoStep},
+		{[]string{"hint"}, "a1 = 8 (naked-single)\n", nil},
+		{[]string{"strategy hidden-single", "step"}, "i1 = 7 (hidden-single, row 1)\n", nil},
+		{[]string{"strategy xwing"}, "selected: x-wing\n", nil},
+		{[]string{"strategy swordfish"}, "", &engine.UnknownStrategyError{}},
+		{[]string{"run"}, "45 steps, 0 empty cells\n", nil},
+		{[]string{"solve", "plan"}, "plan is empty", nil},
+		{[]string{"show cands"}, "| c8  c2  5    | 34", nil},
+		{[]string{"show values"}, "", ErrArguments},
+		{[]string{"help"}, "  strategy  [names|all]", nil},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		r := New(&out)
+		if _, err := r.Execute("load " + easyGame); err != nil {
+			t.Errorf("Game should be loaded, but err: %v", err)
+		}
+		var err error
+		for _, line := range test.lines {
+			out.Reset()
+			if _, err = r.Execute(line); err != nil {
+				break
+			}
+		}
+		if !errorIs(err, test.err) {
+			t.Errorf("Commands %v return error: %v, but expected: %v", test.lines, err, test.err)
+		}
+		if !strings.Contains(out.String(), test.expected) {
+			t.Errorf("Commands %v write %q, it should contain: %q", test.lines, out.String(), test.expected)
+		}
+	}
+}
+
+func TestCommandUndo(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	r.Execute("load " + easyGame)
+	for _, line := range []string{"set a1 8", "step 3", "step"} {
+		if _, err := r.Execute(line); err != nil {
+			t.Errorf("Command %q should pass, but err: %v", line, err)
+		}
+	}
+	for _, steps := range []int{3, 0, 0} {
+		if _, err := r.Execute("undo"); err != nil {
+			t.Errorf("Undo should pass, but err: %v", err)
+		}
+		if len(r.Engine().Result().Steps) != steps {
+			t.Errorf("Engine has %d steps after undo, but expected: %d", len(r.Engine().Result().Steps), steps)
+		}
+	}
+	if r.Engine().Game().EmptyCellCount() != 45 {
+		t.Errorf("Game has %d empty cells after undo, but expected: 45", r.Engine().Game().EmptyCellCount())
+	}
+}
+
+func TestCommandCandidatesOrder(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	if _, err := r.Execute("load " + strings.Repeat(".", 144)); err != nil {
+		t.Errorf("Game 12x12 should be loaded, but err: %v", err)
+	}
+	out.Reset()
+	if _, err := r.Execute("cands a1"); err != nil {
+		t.Errorf("Command cands should pass, but err: %v", err)
+	}
+	if expected := "a1: 1 2 3 4 5 6 7 8 9 A B C\n"; out.String() != expected {
+		t.Errorf("Command cands writes %q, but expected: %q", out.String(), expected)
+	}
+}
+
+//errorIs compares errors, typed errors are compared by type only.
+func errorIs(err, target error) bool {
+	switch target.(type) {
+	case *engine.UnknownStrategyError:
+		var unknown *engine.UnknownStrategyError
+		return errors.As(err, &unknown)
+	case *structures.DuplicateCellError:
+		var duplicate *structures.DuplicateCellError
+		return errors.As(err, &duplicate)
+	}
+	return errors.Is(err, target)
+}
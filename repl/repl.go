@@ -0,0 +1,112 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Prompt is written before every command.
+const Prompt string = "sudoku> "
+
+//Errors for REPL.
+var (
+	ErrNoGame         error = errors.New("No game is loaded, use load command")
+	ErrUnknownCommand error = errors.New("Unknown command, use help command")
+	ErrArguments      error = errors.New("Invalid arguments")
+	ErrNothingToUndo  error = errors.New("Nothing to undo")
+	ErrNoStep         error = errors.New("No step was made yet")
+)
+
+//REPL represents line oriented session for debugging of solver state. It holds
+// engine with loaded game, history of engine states for undo and the last step.
+type REPL struct {
+	out        io.Writer
+	engine     *engine.Engine
+	history    []*engine.Engine
+	last       *engine.Step
+	strategies []engine.Strategy
+}
+
+//New creates REPL object writing to out.
+func New(out io.Writer) *REPL {
+	return &REPL{out: out, strategies: engine.Strategies()}
+}
+
+//Load method starts work with the game, history and last step are cleared.
+func (r *REPL) Load(g *structures.Game) {
+	r.engine = engine.NewEngine(g)
+	r.engine.SetStrategies(r.strategies)
+	r.history = nil
+	r.last = nil
+}
+
+//SetStrategies method selects strategies used by step commands.
+func (r *REPL) SetStrategies(strategies []engine.Strategy) {
+	r.strategies = strategies
+	if r.engine != nil {
+		r.engine.SetStrategies(strategies)
+	}
+}
+
+//Engine returns engine of the session, it is nil when no game is loaded.
+func (r *REPL) Engine() *engine.Engine {
+	return r.engine
+}
+
+//Execute method executes one command line, it returns false when session
+// should end. Empty line and line starting with # are ignored.
+func (r *REPL) Execute(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return true, nil
+	}
+	name := strings.ToLower(fields[0])
+	if name == "quit" || name == "exit" {
+		return false, nil
+	}
+	for _, c := range commands() {
+		if c.name != name {
+			continue
+		}
+		if c.needsGame && r.engine == nil {
+			return true, ErrNoGame
+		}
+		return true, c.run(r, fields[1:])
+	}
+	return true, ErrUnknownCommand
+}
+
+//Run method reads commands from in and executes them until quit command or end
+// of input. Errors of commands are written to out, session continues.
+func (r *REPL) Run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		io.WriteString(r.out, Prompt)
+		if !scanner.Scan() {
+			io.WriteString(r.out, "\n")
+			return scanner.Err()
+		}
+		next, err := r.Execute(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+		if !next {
+			return nil
+		}
+	}
+}
+
+//save method stores engine state for undo before it is changed.
+func (r *REPL) save() {
+	r.history = append(r.history, r.engine.Clone())
+}
+
+func (r *REPL) printf(format string, args ...interface{}) {
+	fmt.Fprintf(r.out, format, args...)
+}
@@ -0,0 +1,56 @@
+package repl
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const easyGame string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+
+func TestExecute(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	tests := []struct {
+		line string
+		next bool
+		err  error
+	}{
+		{"", true, nil},
+		{"# comment", true, nil},
+		{"show", true, ErrNoGame},
+		{"fly", true, ErrUnknownCommand},
+		{"load " + easyGame, true, nil},
+		{"SHOW", true, nil},
+		{"quit", false, nil},
+	}
+	for _, test := range tests {
+		next, err := r.Execute(test.line)
+		if next != test.next || !errors.Is(err, test.err) {
+			t.Errorf("Execute(%q) returns %v, %v, but expected: %v, %v", test.line, next, err, test.next, test.err)
+		}
+	}
+	if r.Engine() == nil || r.Engine().Game().EmptyCellCount() != 45 {
+		t.Errorf("REPL should have engine with loaded game, but has: %v", r.Engine())
+	}
+}
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	r := New(&out)
+	err := r.Run(strings.NewReader("load " + easyGame + "\nundo\nstep\nquit\nstep\n"))
+	if err != nil {
+		t.Errorf("Run should pass, but err: %v", err)
+	}
+	text := out.String()
+	for _, expected := range []string{Prompt + "..5|.1.|6..", "error: " + ErrNothingToUndo.Error(),
+		Prompt + "a1 = 8 (naked-single)\n" + Prompt} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Run writes %q, it should contain: %q", text, expected)
+		}
+	}
+	if len(r.Engine().Result().Steps) != 1 {
+		t.Errorf("Run should stop at quit command, but steps: %v", r.Engine().Result().Steps)
+	}
+}
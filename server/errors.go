@@ -0,0 +1,19 @@
+package server
+
+import (
+	"fmt"
+)
+
+//GameError is returned when game in request body can not be read.
+type GameError struct {
+	Err error
+}
+
+func (e *GameError) Error() string {
+	return fmt.Sprintf("invalid game: %v", e.Err)
+}
+
+//Unwrap returns underlying error of game error.
+func (e *GameError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/daily"
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//ValidateResponse is JSON response of validate endpoint. Solutions are counted
+// only up to 2 and only when game has no conflict.
+type ValidateResponse struct {
+	Valid           bool     `json:"valid"`
+	Conflicts       []string `json:"conflicts,omitempty"`
+	EmptyCandidates []string `json:"emptyCandidates,omitempty"`
+	Solutions       int      `json:"solutions"`
+}
+
+//HintResponse is JSON response of hint endpoint, step is null when no logical
+// step was found.
+type HintResponse struct {
+	Step        *engine.Step `json:"step"`
+	Text        string       `json:"text,omitempty"`
+	Explanation string       `json:"explanation,omitempty"`
+}
+
+//CountResponse is JSON response of count endpoint, count equals limit when
+// counting was stopped at limit.
+type CountResponse struct {
+	Count int `json:"count"`
+	Limit int `json:"limit"`
+}
+
+//GenerateResponse is JSON response of generate endpoint.
+type GenerateResponse struct {
+	Game   *structures.Game `json:"game"`
+	Rating *engine.Rating   `json:"rating"`
+}
+
+//DailyResponse is JSON response of daily endpoint, ID is id of puzzle in store.
+type DailyResponse struct {
+	Date  string           `json:"date"`
+	Level string           `json:"level"`
+	ID    int              `json:"id"`
+	Game  *structures.Game `json:"game"`
+	Score int              `json:"score"`
+}
+
+//handleSolve solves game by selected strategies, response is engine.Result or
+// solved game in output format.
+func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) error {
+	output, err := outputFormat(r)
+	if err != nil {
+		return err
+	}
+	selected, guess, err := strategies(r)
+	if err != nil {
+		return err
+	}
+	g, err := s.readGame(r)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	e := engine.NewEngine(g)
+	e.SetStrategies(selected)
+	if guess {
+		_, err = e.Solve(ctx)
+	} else {
+		_, err = e.Run()
+	}
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return writeGame(w, g, output)
+	}
+	return writeJSON(w, http.StatusOK, e.Result())
+}
+
+//handleValidate checks that game has no conflict and has unique solution,
+// invalid game is not an error of request.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) error {
+	g, err := s.readGame(r)
+	if err != nil {
+		return err
+	}
+	report, err := g.ValidationReport()
+	if err != nil {
+		return err
+	}
+	response := ValidateResponse{EmptyCandidates: report.EmptyCandidates}
+	for _, c := range report.Conflicts {
+		response.Conflicts = append(response.Conflicts, c.String())
+	}
+	if report.IsValid() {
+		ctx, cancel := s.context(r)
+		defer cancel()
+		if response.Solutions, err = engine.NewEngine(g).CountSolutions(ctx, 2); err != nil {
+			return err
+		}
+		response.Valid = response.Solutions == 1
+	}
+	return writeJSON(w, http.StatusOK, response)
+}
+
+//handleRate rates difficulty of game, response is engine.Rating.
+func (s *Server) handleRate(w http.ResponseWriter, r *http.Request) error {
+	g, err := s.readGame(r)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	rating, err := engine.Rate(ctx, g)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, rating)
+}
+
+//handleHint returns next logical step of game.
+func (s *Server) handleHint(w http.ResponseWriter, r *http.Request) error {
+	selected, _, err := strategies(r)
+	if err != nil {
+		return err
+	}
+	g, err := s.readGame(r)
+	if err != nil {
+		return err
+	}
+	e := engine.NewEngine(g)
+	e.SetStrategies(selected)
+	step, err := e.Hint()
+	if err != nil {
+		return err
+	}
+	response := HintResponse{Step: step}
+	if step != nil {
+		response.Text = step.String()
+		response.Explanation = step.Explanation()
+	}
+	return writeJSON(w, http.StatusOK, response)
+}
+
+//handleCount counts solutions of game up to limit argument, limit can not be
+// greater than limit of server.
+func (s *Server) handleCount(w http.ResponseWriter, r *http.Request) error {
+	limit := s.options.CountLimit
+	if text := r.URL.Query().Get("limit"); text != "" {
+		value, err := strconv.Atoi(text)
+		if err != nil || value < 1 {
+			return ErrInvalidArgument
+		}
+		if value < limit {
+			limit = value
+		}
+	}
+	g, err := s.readGame(r)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	count, err := engine.NewEngine(g).CountSolutions(ctx, limit)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: count, Limit: limit})
+}
+
+//handleGenerate generates game of level argument (any level when empty), seed
+// argument makes result reproducible.
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	output, err := outputFormat(r)
+	if err != nil {
+		return err
+	}
+	level := strings.TrimSpace(query.Get("level"))
+	if level != "" {
+		if level, err = engine.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+	seed := time.Now().UnixNano()
+	if text := query.Get("seed"); text != "" {
+		if seed, err = strconv.ParseInt(text, 10, 64); err != nil {
+			return ErrInvalidArgument
+		}
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	g, err := engine.Generate(ctx, rand.New(rand.NewSource(seed)), level)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return writeGame(w, g, output)
+	}
+	rating, err := engine.Rate(ctx, g)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, GenerateResponse{Game: g, Rating: rating})
+}
+
+//handleDaily returns daily puzzle of level argument published at date argument
+// (today when it is empty), puzzle is picked when it was not published yet.
+func (s *Server) handleDaily(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	output, err := outputFormat(r)
+	if err != nil {
+		return err
+	}
+	date := time.Now()
+	if text := query.Get("date"); text != "" {
+		if date, err = time.Parse(daily.DateLayout, text); err != nil {
+			return ErrInvalidArgument
+		}
+	}
+	ctx, cancel := s.context(r)
+	defer cancel()
+	record, err := s.options.Daily.Puzzle(ctx, date, query.Get("level"))
+	if err != nil {
+		return err
+	}
+	g, err := structures.ParseLine(record.Game)
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return writeGame(w, g, output)
+	}
+	return writeJSON(w, http.StatusOK, DailyResponse{Date: date.Format(daily.DateLayout), Level: record.Level,
+		ID: record.ID, Game: g, Score: record.Score})
+}
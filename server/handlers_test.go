@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/daily"
+	"github.com/chytilp/sudoku/store"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	hardSolved string = "812753649943682175675491283154237896369845721287169534521974368438526917796318452"
+	easyGame   string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+)
+
+func TestHandlers(t *testing.T) {
+	// values 2 and 3 in c1, f1, c2, f2 can be swapped.
+	twoSolutions := "81.75.64994.68.175" + hardSolved[18:]
+	tests := []struct {
+		method   string
+		target   string
+		body     string
+		status   int
+		expected string
+	}{
+		{http.MethodPost, "/solve?output=line", hardGame, http.StatusOK, hardSolved + "\n"},
+		{http.MethodPost, "/solve", hardGame, http.StatusOK, `"solved":true`},
+		{http.MethodPost, "/solve?strategies=naked-single,guess&output=json", hardGame, http.StatusOK,
+			`"values":"` + hardSolved + `"`},
+		{http.MethodPost, "/solve?strategies=naked-single", hardGame, http.StatusUnprocessableEntity,
+			`{"error":"Game was not finished by logical strategies"}`},
+		{http.MethodPost, "/solve?strategies=swordfish", hardGame, http.StatusBadRequest, "unknown strategy"},
+		{http.MethodPost, "/solve?output=xml", hardGame, http.StatusBadRequest, "Unknown game format"},
+		{http.MethodGet, "/solve", "", http.StatusMethodNotAllowed, "Method is not allowed"},
+		{http.MethodPost, "/validate", hardGame, http.StatusOK, `{"valid":true,"solutions":1}`},
+		{http.MethodPost, "/validate", twoSolutions, http.StatusOK, `{"valid":false,"solutions":2}`},
+		{http.MethodPost, "/validate", "55" + hardGame[2:], http.StatusOK,
+			`"conflicts":["row 1: value 5 in cells [a1 b1]",`},
+		{http.MethodPost, "/rate", easyGame, http.StatusOK, `"level":"easy"`},
+		{http.MethodPost, "/hint", easyGame, http.StatusOK, `"text":"a1 = 8 (naked-single)"`},
+		{http.MethodPost, "/hint", hardGame, http.StatusOK, `{"step":null}`},
+		{http.MethodPost, "/count?limit=7", strings.Repeat(".", 81), http.StatusOK, `{"count":7,"limit":7}`},
+		{http.MethodPost, "/count?limit=0", hardGame, http.StatusBadRequest, "Invalid query argument"},
+		{http.MethodGet, "/generate?level=easy&seed=1", "", http.StatusOK, `"level":"easy"`},
+		{http.MethodGet, "/generate?level=trivial", "", http.StatusBadRequest, "Unknown level"},
+		{http.MethodGet, "/generate?seed=x", "", http.StatusBadRequest, "Invalid query argument"},
+		{http.MethodGet, "/unknown", "", http.StatusNotFound, ""},
+	}
+	s := New(Options{})
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+		if w.Code != test.status {
+			t.Errorf("%s %s returns status %d (%s), but expected: %d", test.method, test.target, w.Code,
+				w.Body.String(), test.status)
+		}
+		if !strings.Contains(w.Body.String(), test.expected) {
+			t.Errorf("%s %s returns %q, it should contain: %q", test.method, test.target, w.Body.String(),
+				test.expected)
+		}
+	}
+}
+
+func TestHandleGenerateSeed(t *testing.T) {
+	s := New(Options{})
+	var games []string
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/generate?seed=42", nil))
+		var response GenerateResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Errorf("Generate response should be decoded, but err: %v", err)
+			return
+		}
+		games = append(games, response.Game.Line())
+	}
+	if games[0] != games[1] {
+		t.Errorf("Games generated with the same seed should be equal, but are: %v", games)
+	}
+}
+
+func TestHandleDaily(t *testing.T) {
+	library, err := store.Open(filepath.Join(t.TempDir(), "puzzles.db"))
+	if err != nil {
+		t.Errorf("Store should be opened, but err: %v", err)
+		return
+	}
+	defer library.Close()
+	g, _ := structures.ParseLine(hardGame)
+	if _, err = library.Add(context.Background(), &structures.Puzzle{Game: g}); err != nil {
+		t.Errorf("Puzzle should be added, but err: %v", err)
+		return
+	}
+	s := New(Options{Daily: daily.NewScheduler(library, 0)})
+	tests := []struct {
+		target   string
+		status   int
+		expected string
+	}{
+		{"/daily?level=extreme&date=2021-03-14", http.StatusOK, `{"date":"2021-03-14","level":"extreme","id":1,`},
+		{"/daily?level=extreme&date=2021-03-14&output=line", http.StatusOK, hardGame + "\n"},
+		{"/daily?level=trivial", http.StatusBadRequest, "Unknown level"},
+		{"/daily?level=easy&date=14.3.2021", http.StatusBadRequest, "Invalid query argument"},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.target, nil))
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.expected) {
+			t.Errorf("GET %s returns status %d and %q, but expected: %d and %q", test.target, w.Code,
+				w.Body.String(), test.status, test.expected)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/daily"
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/session"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Default limits of server.
+const (
+	DefaultMaxBodySize int64         = 64 << 10
+	DefaultTimeout     time.Duration = 10 * time.Second
+	DefaultCountLimit  int           = 1000
+)
+
+//Timeouts of HTTP connection, write timeout is added to solve timeout.
+const (
+	readTimeout  time.Duration = 10 * time.Second
+	writeTimeout time.Duration = 10 * time.Second
+	idleTimeout  time.Duration = time.Minute
+)
+
+//Content types of responses.
+const (
+	contentTypeJSON string = "application/json"
+	contentTypeText string = "text/plain; charset=utf-8"
+)
+
+//Errors for server.
+var (
+	ErrBodyTooLarge    error = errors.New("Request body is too large")
+	ErrEmptyBody       error = errors.New("Request body is empty")
+	ErrMethod          error = errors.New("Method is not allowed")
+	ErrInvalidArgument error = errors.New("Invalid query argument")
+	ErrStreaming       error = errors.New("Streaming is not supported")
+)
+
+//Options represents limits of server, zero values are replaced by defaults.
+type Options struct {
+	//MaxBodySize is maximal size of request body in bytes.
+	MaxBodySize int64
+	//Timeout limits time spent by solving, rating, counting and generating in one request.
+	Timeout time.Duration
+	//CountLimit is maximal count of solutions counted by count endpoint.
+	CountLimit int
+	//Sessions is service of play sessions, session endpoints are available only
+	// when it is set.
+	Sessions *session.Service
+	//Daily is scheduler of daily puzzles, daily endpoint is available only when
+	// it is set.
+	Daily *daily.Scheduler
+}
+
+//Server represents REST API for solving, validating, rating and generating games.
+//
+// Endpoints (games are sent in request body as JSON or in any text format):
+//
+//	POST /solve?strategies=naked-single,guess&output=line
+//	POST /validate
+//	POST /rate
+//	POST /hint?strategies=hidden-single
+//	POST /count?limit=10
+//	GET  /generate?level=easy&seed=1&output=sdk
+//	GET  /stream?game=8..........36...&delay=200ms (SSE or WebSocket)
+//	POST /sessions {"puzzle": "id"} (see handleSession for session endpoints)
+//	GET  /daily?level=easy&date=2021-03-14&output=line
+//
+// Input format is given by format argument, by application/json content type
+// or it is detected. Responses are JSON, games are returned in text format
+// when output argument is given. Errors are returned as {"error": "message"}.
+type Server struct {
+	options Options
+	mux     *http.ServeMux
+}
+
+//New creates server with given options.
+func New(options Options) *Server {
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultMaxBodySize
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.CountLimit <= 0 {
+		options.CountLimit = DefaultCountLimit
+	}
+	s := &Server{options: options, mux: http.NewServeMux()}
+	s.handle("/solve", s.handleSolve, http.MethodPost)
+	s.handle("/validate", s.handleValidate, http.MethodPost)
+	s.handle("/rate", s.handleRate, http.MethodPost)
+	s.handle("/hint", s.handleHint, http.MethodPost)
+	s.handle("/count", s.handleCount, http.MethodPost)
+	s.handle("/generate", s.handleGenerate, http.MethodGet, http.MethodPost)
+	s.handle("/stream", s.handleStream, http.MethodGet, http.MethodPost)
+	if options.Sessions != nil {
+		s.handle("/sessions", s.handleSessions, http.MethodPost)
+		s.handle("/sessions/", s.handleSession, http.MethodGet, http.MethodPost, http.MethodDelete)
+	}
+	if options.Daily != nil {
+		s.handle("/daily", s.handleDaily, http.MethodGet)
+	}
+	return s
+}
+
+//ServeHTTP method handles API request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+//HTTPServer returns HTTP server listening on addr with timeouts of connections.
+func (s *Server) HTTPServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      s.options.Timeout + writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+//handle method registers handler of path allowed only for methods.
+func (s *Server) handle(path string, handler func(w http.ResponseWriter, r *http.Request) error,
+	methods ...string) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		allowed := false
+		for _, m := range methods {
+			allowed = allowed || r.Method == m
+		}
+		if !allowed {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			writeError(w, ErrMethod)
+			return
+		}
+		if err := handler(w, r); err != nil {
+			writeError(w, err)
+		}
+	})
+}
+
+//context method returns context of request limited by solve timeout.
+func (s *Server) context(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), s.options.Timeout)
+}
+
+//readGame method reads game from request body, see parseGame.
+func (s *Server) readGame(r *http.Request) (*structures.Game, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, s.options.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	return s.parseGame(r, data)
+}
+
+//parseGame method parses game from data, format is given by format argument,
+// by JSON content type or it is detected from content.
+func (s *Server) parseGame(r *http.Request, data []byte) (*structures.Game, error) {
+	if int64(len(data)) > s.options.MaxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyBody
+	}
+	var err error
+	format := structures.DetectFormat(data)
+	if name := r.URL.Query().Get("format"); name != "" {
+		if format, err = structures.ParseFormat(name); err != nil {
+			return nil, err
+		}
+	} else if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == contentTypeJSON {
+		format = structures.FormatJSON
+	}
+	g, err := structures.LoadFormat(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, &GameError{Err: err}
+	}
+	return g, nil
+}
+
+//outputFormat returns format given by output argument, it is empty when
+// response should be JSON.
+func outputFormat(r *http.Request) (structures.Format, error) {
+	name := r.URL.Query().Get("output")
+	if name == "" {
+		return "", nil
+	}
+	return structures.ParseFormat(name)
+}
+
+//strategies returns logical strategies given by strategies argument and if
+// guessing is allowed (guess pseudo strategy), all strategies are used when
+// argument is empty.
+func strategies(r *http.Request) ([]engine.Strategy, bool, error) {
+	names := r.URL.Query().Get("strategies")
+	if strings.TrimSpace(names) == "" {
+		return engine.Strategies(), true, nil
+	}
+	guess := false
+	var logical []string
+	for _, name := range strings.Split(names, ",") {
+		if strings.TrimSpace(name) == engine.StrategyGuess {
+			guess = true
+			continue
+		}
+		logical = append(logical, name)
+	}
+	result, err := engine.ParseStrategies(strings.Join(logical, ","))
+	return result, guess, err
+}
+
+//writeJSON writes value as JSON response.
+func writeJSON(w http.ResponseWriter, status int, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+//writeGame writes game in text format as response.
+func writeGame(w http.ResponseWriter, g *structures.Game, format structures.Format) error {
+	var buf bytes.Buffer
+	if err := structures.Save(&buf, g, format); err != nil {
+		return err
+	}
+	if format == structures.FormatJSON {
+		w.Header().Set("Content-Type", contentTypeJSON)
+	} else {
+		w.Header().Set("Content-Type", contentTypeText)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+//errorResponse is JSON body of error response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+//writeError writes error response with status code of err.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusCode(err), errorResponse{Error: err.Error()})
+}
+
+//statusCode returns HTTP status code for error of request processing.
+func statusCode(err error) int {
+	if status := sessionStatusCode(err); status != 0 {
+		return status
+	}
+	var unknown *engine.UnknownStrategyError
+	var game *GameError
+	var cellID *structures.CellIDError
+	switch {
+	case errors.Is(err, ErrMethod):
+		return http.StatusMethodNotAllowed
+	case errors.Is(err, ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, engine.ErrGeneratorLevel),
+		errors.Is(err, daily.ErrNoPuzzle):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, engine.ErrUnsolvable), errors.Is(err, engine.ErrMultipleSolutions),
+		errors.Is(err, engine.ErrNotFinished):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrEmptyBody), errors.Is(err, ErrInvalidArgument), errors.Is(err, ErrWebSocketHandshake),
+		errors.Is(err, structures.ErrUnknownFormat), errors.Is(err, engine.ErrUnknownLevel),
+		errors.As(err, &unknown), errors.As(err, &game), errors.As(err, &cellID),
+		errors.Is(err, structures.ErrStandardOnly), errors.Is(err, structures.ErrUnsupportedGeometry),
+		errors.Is(err, structures.ErrUnknownVariant), errors.Is(err, structures.ErrInvalidTransform),
+		errors.Is(err, structures.ErrVariantTransform), errors.Is(err, structures.ErrCageDigits):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
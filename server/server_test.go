@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/daily"
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const hardGame string = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+
+func TestNewDefaults(t *testing.T) {
+	s := New(Options{})
+	expected := Options{MaxBodySize: DefaultMaxBodySize, Timeout: DefaultTimeout, CountLimit: DefaultCountLimit}
+	if s.options != expected {
+		t.Errorf("Server has options %+v, but expected: %+v", s.options, expected)
+	}
+	srv := s.HTTPServer(":0")
+	if srv.WriteTimeout <= DefaultTimeout || srv.ReadTimeout == 0 || srv.Handler != s {
+		t.Errorf("HTTP server should have timeouts and server as handler, but is: %+v", srv)
+	}
+}
+
+func TestReadGame(t *testing.T) {
+	s := New(Options{MaxBodySize: 100})
+	tests := []struct {
+		target      string
+		contentType string
+		body        string
+		err         error
+	}{
+		{"/solve", "text/plain", hardGame, nil},
+		{"/solve", "application/json; charset=utf-8", `{"values":"` + hardGame + `"}`, nil},
+		{"/solve?format=line", "", hardGame, nil},
+		{"/solve?format=grid", "", hardGame, &GameError{}},
+		{"/solve?format=xml", "", hardGame, structures.ErrUnknownFormat},
+		{"/solve", "", " \n", ErrEmptyBody},
+		{"/solve", "", hardGame + "\n" + hardGame, ErrBodyTooLarge},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, test.target, strings.NewReader(test.body))
+		r.Header.Set("Content-Type", test.contentType)
+		g, err := s.readGame(r)
+		var game *GameError
+		if _, ok := test.err.(*GameError); ok {
+			if !errors.As(err, &game) {
+				t.Errorf("readGame(%s) should return GameError, but err: %v", test.target, err)
+			}
+			continue
+		}
+		if !errors.Is(err, test.err) {
+			t.Errorf("readGame(%s) returns error: %v, but expected: %v", test.target, err, test.err)
+		}
+		if err == nil && g.Line() != hardGame {
+			t.Errorf("readGame(%s) returns game %s, but expected: %s", test.target, g.Line(), hardGame)
+		}
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{ErrMethod, http.StatusMethodNotAllowed},
+		{ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+		{context.DeadlineExceeded, http.StatusServiceUnavailable},
+		{engine.ErrUnsolvable, http.StatusUnprocessableEntity},
+		{&engine.UnknownStrategyError{Name: "foo"}, http.StatusBadRequest},
+		{&GameError{Err: structures.ErrParseLineLength}, http.StatusBadRequest},
+		{structures.ErrStandardOnly, http.StatusBadRequest},
+		{structures.ErrUnsupportedGeometry, http.StatusBadRequest},
+		{structures.ErrUnknownVariant, http.StatusBadRequest},
+		{structures.ErrInvalidTransform, http.StatusBadRequest},
+		{structures.ErrVariantTransform, http.StatusBadRequest},
+		{structures.ErrCageDigits, http.StatusBadRequest},
+		{daily.ErrNoPuzzle, http.StatusServiceUnavailable},
+		{errors.New("Disk is full"), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		if status := statusCode(test.err); status != test.status {
+			t.Errorf("statusCode(%v) returns %d, but expected: %d", test.err, status, test.status)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := New(Options{Timeout: time.Nanosecond})
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/count", strings.NewReader(strings.Repeat(".", 81))))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Request should time out with status %d, but status: %d, body: %s", http.StatusServiceUnavailable,
+			w.Code, w.Body.String())
+	}
+}
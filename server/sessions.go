@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/chytilp/sudoku/session"
+)
+
+//Errors for session endpoints.
+var (
+	ErrInvalidJSON error = errors.New("Request body is not valid JSON")
+	ErrNotFound    error = errors.New("Resource was not found")
+)
+
+//StartRequest is JSON body of request starting session.
+type StartRequest struct {
+	Puzzle string `json:"puzzle"`
+}
+
+//MoveRequest is JSON body of move request, value 0 erases cell.
+type MoveRequest struct {
+	Cell  string `json:"cell"`
+	Value uint8  `json:"value"`
+}
+
+//handleSessions starts new session of puzzle, response is session.State.
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) error {
+	var request StartRequest
+	if err := s.readJSON(r, &request); err != nil {
+		return err
+	}
+	state, err := s.options.Sessions.Start(r.Context(), request.Puzzle)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, state)
+}
+
+//handleSession handles requests of one session:
+//
+//	GET    /sessions/{id}        state of session
+//	DELETE /sessions/{id}        end of session
+//	POST   /sessions/{id}/moves  move (MoveRequest), response is session.MoveResult
+//	POST   /sessions/{id}/hint   hint, response is session.HintResult
+//	POST   /sessions/{id}/undo   undo of the last move, response is session.State
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) error {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/sessions/"), "/")
+	if len(parts) > 2 || parts[0] == "" {
+		return ErrNotFound
+	}
+	id := parts[0]
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	sessions := s.options.Sessions
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		state, err := sessions.State(id)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, state)
+	case action == "" && r.Method == http.MethodDelete:
+		if err := sessions.End(id); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	case action == "":
+		w.Header().Set("Allow", "GET, DELETE")
+		return ErrMethod
+	case r.Method != http.MethodPost:
+		w.Header().Set("Allow", http.MethodPost)
+		return ErrMethod
+	case action == "moves":
+		var request MoveRequest
+		if err := s.readJSON(r, &request); err != nil {
+			return err
+		}
+		result, err := sessions.Move(id, request.Cell, request.Value)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, result)
+	case action == "hint":
+		result, err := sessions.Hint(id)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, result)
+	case action == "undo":
+		state, err := sessions.Undo(id)
+		if err != nil {
+			return err
+		}
+		return writeJSON(w, http.StatusOK, state)
+	}
+	return ErrNotFound
+}
+
+//readJSON method decodes JSON request body into value, body size is limited.
+func (s *Server) readJSON(r *http.Request, value interface{}) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, s.options.MaxBodySize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.options.MaxBodySize {
+		return ErrBodyTooLarge
+	}
+	if err = json.Unmarshal(data, value); err != nil {
+		return ErrInvalidJSON
+	}
+	return nil
+}
+
+//sessionStatusCode returns HTTP status code for error of session, it returns 0
+// for other errors.
+func sessionStatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, session.ErrSessionNotFound),
+		errors.Is(err, session.ErrPuzzleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, session.ErrGivenCell), errors.Is(err, session.ErrFinished),
+		errors.Is(err, session.ErrNoMove):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidJSON), errors.Is(err, session.ErrInvalidValue):
+		return http.StatusBadRequest
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/session"
+	"github.com/chytilp/sudoku/structures"
+)
+
+func TestSessionHandlers(t *testing.T) {
+	g, err := structures.ParseLine(easyGame)
+	if err != nil {
+		t.Errorf("Game should be parsed, but err: %v", err)
+		return
+	}
+	puzzles := session.MemoryPuzzles{"easy": {Game: g}}
+	s := New(Options{Sessions: session.NewService(puzzles, session.NewMemoryStore(), time.Second)})
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(`{"puzzle":"easy"}`)))
+	var state session.State
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil || w.Code != http.StatusCreated {
+		t.Errorf("Session should be started, but status: %d, err: %v", w.Code, err)
+		return
+	}
+	path := "/sessions/" + state.ID
+	tests := []struct {
+		method   string
+		target   string
+		body     string
+		status   int
+		expected string
+	}{
+		{http.MethodGet, path, "", http.StatusOK, `"moves":0`},
+		{http.MethodPost, path + "/moves", `{"cell":"a1","value":8}`, http.StatusOK, `"correct":true`},
+		{http.MethodPost, path + "/moves", `{"cell":"b1","value":5}`, http.StatusOK,
+			`"conflicts":["row 1: value 5 in cells [b1 c1]",`},
+		{http.MethodPost, path + "/moves", `{"cell":"c1","value":1}`, http.StatusConflict, "Given cell"},
+		{http.MethodPost, path + "/moves", `{"cell":"a1","value":10}`, http.StatusBadRequest, "Value should be"},
+		{http.MethodPost, path + "/moves", `{"cell":`, http.StatusBadRequest, "not valid JSON"},
+		{http.MethodPost, path + "/hint", "", http.StatusOK, `"mistakes":["b1"]`},
+		{http.MethodPost, path + "/undo", "", http.StatusOK, `"moves":1`},
+		{http.MethodPut, path, "", http.StatusMethodNotAllowed, "Method is not allowed"},
+		{http.MethodGet, path + "/hint", "", http.StatusMethodNotAllowed, "Method is not allowed"},
+		{http.MethodPost, path + "/unknown", "", http.StatusNotFound, "not found"},
+		{http.MethodDelete, path, "", http.StatusNoContent, ""},
+		{http.MethodGet, path, "", http.StatusNotFound, "Session was not found"},
+		{http.MethodPost, "/sessions", `{"puzzle":"hard"}`, http.StatusNotFound, "Puzzle was not found"},
+		{http.MethodGet, "/sessions", "", http.StatusMethodNotAllowed, "Method is not allowed"},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+		if w.Code != test.status {
+			t.Errorf("%s %s returns status %d (%s), but expected: %d", test.method, test.target, w.Code,
+				w.Body.String(), test.status)
+		}
+		if !strings.Contains(w.Body.String(), test.expected) {
+			t.Errorf("%s %s returns %q, it should contain: %q", test.method, test.target, w.Body.String(),
+				test.expected)
+		}
+	}
+}
+
+func TestSessionsDisabled(t *testing.T) {
+	s := New(Options{})
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(`{"puzzle":"easy"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Sessions should not be available without service, but status: %d", w.Code)
+	}
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Limits of delays between events of stream. Server-Sent Events are limited by
+// write timeout of the server, so delays of such stream are skipped after
+// sseMaxPause in total.
+const (
+	maxStreamDelay time.Duration = time.Second
+	sseMaxPause    time.Duration = writeTimeout / 2
+)
+
+//Types of messages which end stream.
+const (
+	messageResult string = "result"
+	messageError  string = "error"
+)
+
+//eventWriter sends messages of stream to client.
+type eventWriter interface {
+	send(messageType string, data []byte) error
+}
+
+//sseWriter sends messages as Server-Sent Events, message type is event name.
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s sseWriter) send(messageType string, data []byte) error {
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", messageType, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+//wsWriter sends messages as WebSocket text messages, type is part of message.
+type wsWriter struct {
+	conn *wsConn
+}
+
+func (s wsWriter) send(messageType string, data []byte) error {
+	return s.conn.WriteText(data)
+}
+
+//StreamEnd is the last message of stream, it contains result of solving or error.
+type StreamEnd struct {
+	Type    string           `json:"type"`
+	Solved  bool             `json:"solved,omitempty"`
+	Steps   int              `json:"steps,omitempty"`
+	Guesses int              `json:"guesses,omitempty"`
+	Game    *structures.Game `json:"game,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+//handleStream solves game and streams every engine event (see engine.Event) to
+// client as Server-Sent Event or as WebSocket message. Stream ends by message
+// of type result or error (see StreamEnd). Game is given by game argument or
+// by request body, delay argument (e.g. 200ms) slows stream down.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) error {
+	selected, guess, err := strategies(r)
+	if err != nil {
+		return err
+	}
+	var delay time.Duration
+	if text := r.URL.Query().Get("delay"); text != "" {
+		if delay, err = time.ParseDuration(text); err != nil || delay < 0 || delay > maxStreamDelay {
+			return ErrInvalidArgument
+		}
+	}
+	var g *structures.Game
+	if text := r.URL.Query().Get("game"); text != "" || r.Method == http.MethodGet {
+		g, err = s.parseGame(r, []byte(text))
+	} else {
+		g, err = s.readGame(r)
+	}
+	if err != nil {
+		return err
+	}
+	budget := newSolveBudget(r.Context(), s.options.Timeout)
+	defer budget.cancel()
+	var writer eventWriter
+	maxPause := time.Duration(-1)
+	if isWebSocket(r) {
+		conn, err := upgradeWebSocket(w, r, s.options.MaxBodySize)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		go func() {
+			conn.ReadLoop()
+			budget.cancel()
+		}()
+		writer = wsWriter{conn}
+	} else {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			return ErrStreaming
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		writer = sseWriter{w, flusher}
+		maxPause = sseMaxPause
+	}
+	streamSolve(budget, g, selected, guess, delay, maxPause, writer)
+	return nil
+}
+
+//streamSolve solves game and sends its events by writer, solving is cancelled
+// when message can not be sent. Delays are not counted into solve timeout,
+// they are skipped when their total reaches maxPause (negative means no limit).
+func streamSolve(budget *solveBudget, g *structures.Game, selected []engine.Strategy, guess bool,
+	delay time.Duration, maxPause time.Duration, writer eventWriter) {
+	var sendErr error
+	var paused time.Duration
+	e := engine.NewEngine(g)
+	e.SetStrategies(selected)
+	e.SetEventHandler(func(event engine.Event) {
+		if sendErr != nil {
+			return
+		}
+		data, err := json.Marshal(event)
+		if err == nil {
+			err = writer.send(string(event.Type), data)
+		}
+		if err != nil {
+			sendErr = err
+			budget.cancel()
+			return
+		}
+		if delay > 0 && (maxPause < 0 || paused+delay <= maxPause) {
+			paused += delay
+			budget.pause()
+			select {
+			case <-time.After(delay):
+			case <-budget.Done():
+			}
+			budget.resume()
+		}
+	})
+	var err error
+	if guess {
+		_, err = e.Solve(budget)
+	} else {
+		_, err = e.Run()
+	}
+	if sendErr != nil {
+		return
+	}
+	end := StreamEnd{Type: messageError}
+	if err != nil {
+		end.Error = err.Error()
+	} else {
+		result := e.Result()
+		end = StreamEnd{Type: messageResult, Solved: result.Solved, Steps: len(result.Steps),
+			Guesses: result.Guesses, Game: result.Game}
+	}
+	if data, err := json.Marshal(end); err == nil {
+		writer.send(end.Type, data)
+	}
+}
+
+//solveBudget is context of streamed solving with timeout which can be paused,
+// so delays between events are not counted into time of solving. Err returns
+// context.DeadlineExceeded when timeout is reached.
+type solveBudget struct {
+	context.Context
+	stop      context.CancelFunc
+	mu        sync.Mutex
+	timer     *time.Timer
+	remaining time.Duration
+	started   time.Time
+	expired   bool
+}
+
+//newSolveBudget creates context with timeout derived from parent.
+func newSolveBudget(parent context.Context, timeout time.Duration) *solveBudget {
+	ctx, stop := context.WithCancel(parent)
+	b := &solveBudget{Context: ctx, stop: stop, remaining: timeout, started: time.Now()}
+	b.timer = time.AfterFunc(timeout, b.expire)
+	return b
+}
+
+//Err method returns context.DeadlineExceeded when timeout was reached, error
+// of parent context otherwise.
+func (b *solveBudget) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.expired {
+		return context.DeadlineExceeded
+	}
+	return b.Context.Err()
+}
+
+func (b *solveBudget) expire() {
+	b.mu.Lock()
+	b.expired = true
+	b.mu.Unlock()
+	b.stop()
+}
+
+//pause method stops measuring of time until resume is called.
+func (b *solveBudget) pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer.Stop() {
+		b.remaining -= time.Since(b.started)
+	}
+}
+
+//resume method continues measuring of time with remaining timeout.
+func (b *solveBudget) resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.expired && b.Context.Err() == nil {
+		b.started = time.Now()
+		b.timer = time.AfterFunc(b.remaining, b.expire)
+	}
+}
+
+//cancel method cancels context and releases its timer.
+func (b *solveBudget) cancel() {
+	b.mu.Lock()
+	b.timer.Stop()
+	b.mu.Unlock()
+	b.stop()
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleStreamSSE(t *testing.T) {
+	s := New(Options{})
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?game="+easyGame+"&delay=1ms", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Stream returns status %d and content type %q, but expected event stream", w.Code,
+			w.Header().Get("Content-Type"))
+	}
+	var events []string
+	var last StreamEnd
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &last); err != nil {
+				t.Errorf("Event data should be JSON, but err: %v", err)
+			}
+		}
+	}
+	if len(events) != 47 || events[0] != "place" || events[45] != "finish" || events[46] != messageResult {
+		t.Errorf("Stream should contain 45 placements, finish and result, but contains: %v", events)
+	}
+	if !last.Solved || last.Steps != 45 || last.Game == nil {
+		t.Errorf("Last message is %+v, but expected solved game", last)
+	}
+}
+
+func TestHandleStreamErrors(t *testing.T) {
+	tests := []struct {
+		method   string
+		target   string
+		body     string
+		status   int
+		expected string
+	}{
+		{http.MethodPost, "/stream?strategies=naked-single", hardGame, http.StatusOK,
+			`event: error` + "\n" + `data: {"type":"error","error":"Game was not finished by logical strategies"}`},
+		{http.MethodGet, "/stream", "", http.StatusBadRequest, "Request body is empty"},
+		{http.MethodGet, "/stream?game=" + easyGame + "&delay=1h", "", http.StatusBadRequest, "Invalid query argument"},
+		{http.MethodGet, "/stream?game=" + easyGame[1:], "", http.StatusBadRequest, "invalid game"},
+	}
+	s := New(Options{})
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+		if w.Code != test.status || !strings.Contains(w.Body.String(), test.expected) {
+			t.Errorf("%s %s returns status %d and %q, but expected: %d and %q", test.method, test.target, w.Code,
+				w.Body.String(), test.status, test.expected)
+		}
+	}
+}
+
+func TestHandleStreamDelayNotInTimeout(t *testing.T) {
+	s := New(Options{Timeout: 100 * time.Millisecond})
+	w := httptest.NewRecorder()
+	// delays of 47 events take longer than timeout of solving.
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?game="+easyGame+"&delay=10ms", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, "event: "+messageResult) {
+		t.Errorf("Stream with delays should end by result, but returns status %d and %s", w.Code, body)
+	}
+}
+
+func TestSolveBudget(t *testing.T) {
+	b := newSolveBudget(context.Background(), 50*time.Millisecond)
+	defer b.cancel()
+	b.pause()
+	time.Sleep(100 * time.Millisecond)
+	if b.Err() != nil {
+		t.Errorf("Paused budget should not expire, but err: %v", b.Err())
+	}
+	b.resume()
+	select {
+	case <-b.Done():
+	case <-time.After(time.Second):
+		t.Error("Resumed budget should expire")
+	}
+	if !errors.Is(b.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expired budget should return DeadlineExceeded, but err: %v", b.Err())
+	}
+}
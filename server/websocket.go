@@ -0,0 +1,221 @@
+package server
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//WebSocket protocol constants (RFC 6455).
+const (
+	wsGUID            string = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsVersion         string = "13"
+	wsOpText          byte   = 0x1
+	wsOpClose         byte   = 0x8
+	wsOpPing          byte   = 0x9
+	wsOpPong          byte   = 0xa
+	wsFinal           byte   = 0x80
+	wsMasked          byte   = 0x80
+	wsCloseNormal     uint16 = 1000
+	wsMaxControlFrame int    = 125
+)
+
+//Errors for WebSocket.
+var (
+	ErrWebSocketHandshake error = errors.New("Invalid WebSocket handshake")
+	ErrWebSocketHijack    error = errors.New("Connection can not be switched to WebSocket")
+	ErrWebSocketFrame     error = errors.New("Invalid WebSocket frame")
+)
+
+//wsConn represents server side of WebSocket connection, it sends text messages
+// and answers control frames of client. Writes are safe for concurrent use.
+type wsConn struct {
+	conn     net.Conn
+	rw       *bufio.ReadWriter
+	maxFrame int64
+	mu       sync.Mutex
+	closed   bool
+}
+
+//isWebSocket returns if request asks for WebSocket connection.
+func isWebSocket(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") && headerContains(r.Header, "Upgrade", "websocket")
+}
+
+//headerContains returns if comma separated header contains token (case is ignored).
+func headerContains(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//upgradeWebSocket switches HTTP connection of request to WebSocket, frames of
+// client are limited to maxFrame bytes.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, maxFrame int64) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || r.Header.Get("Sec-WebSocket-Version") != wsVersion {
+		return nil, ErrWebSocketHandshake
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, ErrWebSocketHijack
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// deadlines of HTTP server would end stream, which can be longer than request.
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAccept(key) + "\r\n\r\n"
+	if _, err = rw.WriteString(response); err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw, maxFrame: maxFrame}, nil
+}
+
+//wsAccept returns value of Sec-WebSocket-Accept header for key of client.
+func wsAccept(key string) string {
+	hash := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+//WriteText method sends text message.
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+//Close method sends close frame and closes connection.
+func (c *wsConn) Close() error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, wsCloseNormal)
+	c.writeFrame(wsOpClose, payload)
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.conn.Close()
+}
+
+//ReadLoop method reads frames of client until close frame or error, pings are
+// answered, other messages are ignored. It returns nil when client closes
+// connection.
+func (c *wsConn) ReadLoop() error {
+	for {
+		opcode, payload, err := readFrame(c.rw.Reader, c.maxFrame)
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case wsOpClose:
+			return nil
+		case wsOpPing:
+			if err = c.writeFrame(wsOpPong, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+//writeFrame method writes unfragmented unmasked frame.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+	if _, err := c.rw.Write(frameHeader(opcode, len(payload), false)); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+//frameHeader returns header of final frame with payload of length.
+func frameHeader(opcode byte, length int, masked bool) []byte {
+	header := []byte{wsFinal | opcode, 0}
+	var mask byte
+	if masked {
+		mask = wsMasked
+	}
+	switch {
+	case length <= wsMaxControlFrame:
+		header[1] = mask | byte(length)
+	case length <= 0xffff:
+		header[1] = mask | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = mask | 127
+		header = append(header, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+	return header
+}
+
+//readFrame reads one frame, masked payload is unmasked. Fragmented messages
+// are returned frame by frame, frame with payload longer than maxSize bytes
+// returns ErrWebSocketFrame.
+func readFrame(r io.Reader, maxSize int64) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0f
+	length := uint64(header[1] &^ wsMasked)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(r, extended); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(r, extended); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+	if length > uint64(maxSize) {
+		return 0, nil, ErrWebSocketFrame
+	}
+	var mask []byte
+	if header[1]&wsMasked != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(r, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		if mask != nil {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
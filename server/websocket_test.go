@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWSAccept(t *testing.T) {
+	// example from RFC 6455.
+	if accept := wsAccept("dGhlIHNhbXBsZSBub25jZQ=="); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("wsAccept returns %s, but expected: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", accept)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	for _, length := range []int{0, 5, 125, 126, 65535} {
+		payload := bytes.Repeat([]byte("x"), length)
+		var buf bytes.Buffer
+		buf.Write(frameHeader(wsOpText, length, true))
+		mask := []byte{1, 2, 3, 4}
+		buf.Write(mask)
+		for i, b := range payload {
+			buf.WriteByte(b ^ mask[i%4])
+		}
+		opcode, data, err := readFrame(&buf, DefaultMaxBodySize)
+		if err != nil || opcode != wsOpText || !bytes.Equal(data, payload) {
+			t.Errorf("Frame of length %d is read as opcode %d and %d bytes, err: %v", length, opcode, len(data), err)
+		}
+	}
+	header := frameHeader(wsOpText, 101, false)
+	if _, _, err := readFrame(bytes.NewReader(header), 100); err != ErrWebSocketFrame {
+		t.Errorf("Too large frame should return ErrWebSocketFrame, but err: %v", err)
+	}
+}
+
+func TestHandleStreamWebSocket(t *testing.T) {
+	ts := httptest.NewServer(New(Options{}))
+	defer ts.Close()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Errorf("Connection should be opened, but err: %v", err)
+		return
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET /stream?game=%s HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n", easyGame)
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil || response.StatusCode != http.StatusSwitchingProtocols ||
+		response.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Handshake should switch protocols, but response: %v, err: %v", response, err)
+		return
+	}
+	var types []string
+	for {
+		opcode, data, err := readFrame(reader, DefaultMaxBodySize)
+		if err != nil {
+			t.Errorf("Frame should be read, but err: %v", err)
+			return
+		}
+		if opcode == wsOpClose {
+			if binary.BigEndian.Uint16(data) != wsCloseNormal {
+				t.Errorf("Close frame has status %v, but expected: %d", data, wsCloseNormal)
+			}
+			break
+		}
+		var message struct {
+			Type string `json:"type"`
+		}
+		if err = json.Unmarshal(data, &message); err != nil {
+			t.Errorf("Message should be JSON, but err: %v", err)
+		}
+		types = append(types, message.Type)
+	}
+	if len(types) != 47 || types[0] != "place" || types[46] != messageResult {
+		t.Errorf("WebSocket should receive 45 placements, finish and result, but received: %v", types)
+	}
+}
+
+func TestHandleStreamWebSocketServerTimeouts(t *testing.T) {
+	ts := httptest.NewUnstartedServer(New(Options{}))
+	ts.Config.ReadTimeout = 100 * time.Millisecond
+	ts.Config.WriteTimeout = 100 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Errorf("Connection should be opened, but err: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	// stream with delays takes longer than timeouts of HTTP connection.
+	fmt.Fprintf(conn, "GET /stream?game=%s&delay=10ms HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\n"+
+		"Upgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n",
+		easyGame)
+	started := time.Now()
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil || response.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("Handshake should switch protocols, but response: %v, err: %v", response, err)
+		return
+	}
+	var last []byte
+	for {
+		opcode, data, err := readFrame(reader, DefaultMaxBodySize)
+		if err != nil {
+			t.Errorf("Frame should be read, but err: %v", err)
+			return
+		}
+		if opcode == wsOpClose {
+			break
+		}
+		last = data
+	}
+	if !strings.Contains(string(last), `"type":"`+messageResult+`"`) {
+		t.Errorf("Stream should end by result, but last message is: %s", last)
+	}
+	// cancelled stream skips delays.
+	if elapsed := time.Since(started); elapsed < 47*10*time.Millisecond {
+		t.Errorf("Stream should not be cancelled, but it took only %v", elapsed)
+	}
+}
+
+func TestHandleStreamWebSocketMaxBodySize(t *testing.T) {
+	ts := httptest.NewServer(New(Options{MaxBodySize: 100}))
+	defer ts.Close()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Errorf("Connection should be opened, but err: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	fmt.Fprintf(conn, "GET /stream?game=%s&delay=1s HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\n"+
+		"Upgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n",
+		easyGame)
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil || response.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("Handshake should switch protocols, but response: %v, err: %v", response, err)
+		return
+	}
+	// frame longer than MaxBodySize ends the stream without waiting for delay.
+	conn.Write(append(frameHeader(wsOpText, 101, true), make([]byte, 4+101)...))
+	for {
+		opcode, _, err := readFrame(reader, DefaultMaxBodySize)
+		if err != nil {
+			t.Errorf("Stream should be closed after too large frame, but err: %v", err)
+			return
+		}
+		if opcode == wsOpClose {
+			return
+		}
+	}
+}
+
+func TestHandleStreamWebSocketHandshake(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/stream?game="+easyGame, nil)
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	w := httptest.NewRecorder()
+	New(Options{}).ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), ErrWebSocketHandshake.Error()) {
+		t.Errorf("Handshake without key should fail with status %d, but status: %d, body: %s",
+			http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,239 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//idLength is count of random bytes of session id.
+const idLength int = 16
+
+//Errors for session service.
+var (
+	ErrPuzzleNotFound error = errors.New("Puzzle was not found")
+)
+
+//PuzzleSource provides puzzles by their id.
+type PuzzleSource interface {
+	//Puzzle returns puzzle with id or ErrPuzzleNotFound.
+	Puzzle(id string) (*structures.Puzzle, error)
+}
+
+//MemoryPuzzles is PuzzleSource holding puzzles in map by their id.
+type MemoryPuzzles map[string]*structures.Puzzle
+
+//Puzzle method returns puzzle with id.
+func (m MemoryPuzzles) Puzzle(id string) (*structures.Puzzle, error) {
+	p, ok := m[id]
+	if !ok {
+		return nil, ErrPuzzleNotFound
+	}
+	return p, nil
+}
+
+//MoveResult represents result of move - state of session after the move, the
+// move and conflicts of moved cell with other cells.
+type MoveResult struct {
+	State     *State   `json:"state"`
+	Move      Move     `json:"move"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+//HintResult represents hint - next logical step (nil when strategies find no
+// step) and cells whose values do not match solution.
+type HintResult struct {
+	State       *State       `json:"state"`
+	Step        *engine.Step `json:"step"`
+	Explanation string       `json:"explanation,omitempty"`
+	Mistakes    []string     `json:"mistakes,omitempty"`
+}
+
+//sessionLock serializes requests against one session, refs counts requests
+// waiting for the lock.
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+//Service manages play sessions - it starts them from puzzles, applies moves,
+// gives hints and keeps sessions in store. It is safe for concurrent use,
+// requests against the same session are serialized.
+type Service struct {
+	puzzles PuzzleSource
+	store   Store
+	timeout time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	locks   map[string]*sessionLock
+}
+
+//NewService creates Service object, timeout limits solving of puzzle when
+// session is started.
+func NewService(puzzles PuzzleSource, store Store, timeout time.Duration) *Service {
+	return &Service{puzzles: puzzles, store: store, timeout: timeout, now: time.Now,
+		locks: make(map[string]*sessionLock)}
+}
+
+//Start method starts new session of puzzle with id, puzzle is solved to
+// validate moves of player.
+func (s *Service) Start(ctx context.Context, puzzleID string) (*State, error) {
+	p, err := s.puzzles.Puzzle(puzzleID)
+	if err != nil {
+		return nil, err
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	result, err := engine.NewEngine(p.Game.Clone()).Solve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	session := &Session{ID: id, PuzzleID: puzzleID, Game: p.Game.Clone(), Solution: result.Game.Line(),
+		Started: s.now()}
+	if err = s.store.Save(session); err != nil {
+		return nil, err
+	}
+	return session.State(s.now()), nil
+}
+
+//State method returns current state of session.
+func (s *Service) State(id string) (*State, error) {
+	var state *State
+	err := s.update(id, false, func(session *Session) error {
+		state = session.State(s.now())
+		return nil
+	})
+	return state, err
+}
+
+//Move method puts value into cell of session (value 0 erases it).
+func (s *Service) Move(id string, cellID string, value uint8) (*MoveResult, error) {
+	var result *MoveResult
+	err := s.update(id, true, func(session *Session) error {
+		move, err := session.Play(cellID, value, s.now())
+		if err != nil {
+			return err
+		}
+		report, err := session.Game.ValidationReport()
+		if err != nil {
+			return err
+		}
+		result = &MoveResult{State: session.State(s.now()), Move: *move}
+		for _, c := range report.Conflicts {
+			for _, conflictID := range c.CellIds {
+				if conflictID == move.CellID {
+					result.Conflicts = append(result.Conflicts, c.String())
+				}
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+//Undo method returns the last move of session back.
+func (s *Service) Undo(id string) (*State, error) {
+	var state *State
+	err := s.update(id, true, func(session *Session) error {
+		if _, err := session.Undo(); err != nil {
+			return err
+		}
+		state = session.State(s.now())
+		return nil
+	})
+	return state, err
+}
+
+//Hint method returns next logical step of session game, values of player which
+// do not match solution are ignored and returned as mistakes.
+func (s *Service) Hint(id string) (*HintResult, error) {
+	var result *HintResult
+	err := s.update(id, true, func(session *Session) error {
+		if session.IsFinished() {
+			return ErrFinished
+		}
+		g := session.Game.Clone()
+		mistakes := session.mistakes()
+		g.RemoveSolutionCells(mistakes)
+		step, err := engine.NewEngine(g).Hint()
+		if err != nil {
+			return err
+		}
+		session.Hints++
+		result = &HintResult{State: session.State(s.now()), Step: step, Mistakes: mistakes}
+		if step != nil {
+			result.Explanation = step.Explanation()
+		}
+		return nil
+	})
+	return result, err
+}
+
+//End method ends session and removes it from store.
+func (s *Service) End(id string) error {
+	unlock := s.lock(id)
+	defer unlock()
+	return s.store.Delete(id)
+}
+
+//update method loads session, calls change and saves the session when save is
+// true and change passes. Session is locked during update.
+func (s *Service) update(id string, save bool, change func(session *Session) error) error {
+	unlock := s.lock(id)
+	defer unlock()
+	session, err := s.store.Load(id)
+	if err != nil {
+		return err
+	}
+	if err = change(session); err != nil {
+		return err
+	}
+	if save {
+		return s.store.Save(session)
+	}
+	return nil
+}
+
+//lock method locks session with id and returns function unlocking it.
+func (s *Service) lock(id string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[id]
+	if !ok {
+		l = &sessionLock{}
+		s.locks[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(s.locks, id)
+		}
+		s.mu.Unlock()
+	}
+}
+
+//newID returns random session id.
+func newID() (string, error) {
+	data := make([]byte, idLength)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+func createService(t *testing.T) (*Service, *MemoryStore) {
+	g, err := structures.ParseLine(easyGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	store := NewMemoryStore()
+	s := NewService(MemoryPuzzles{"easy": {Game: g}}, store, time.Second)
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return s, store
+}
+
+func TestServiceStart(t *testing.T) {
+	s, store := createService(t)
+	state, err := s.Start(context.Background(), "easy")
+	if err != nil {
+		t.Errorf("Start should pass, but err: %v", err)
+		return
+	}
+	if len(state.ID) != 2*idLength || state.PuzzleID != "easy" || state.Game.Line() != easyGame {
+		t.Errorf("Started session has state %+v, but expected new session of easy puzzle", state)
+	}
+	if session, err := store.Load(state.ID); err != nil || session.Solution != easySolved {
+		t.Errorf("Session should be stored with solution, but err: %v", err)
+	}
+	if _, err = s.Start(context.Background(), "hard"); !errors.Is(err, ErrPuzzleNotFound) {
+		t.Errorf("Start of unknown puzzle should return ErrPuzzleNotFound, but err: %v", err)
+	}
+}
+
+func TestServiceMoves(t *testing.T) {
+	s, _ := createService(t)
+	state, _ := s.Start(context.Background(), "easy")
+	result, err := s.Move(state.ID, "b1", 5)
+	if err != nil {
+		t.Errorf("Move should pass, but err: %v", err)
+		return
+	}
+	if result.Move.Correct || len(result.Conflicts) != 3 || result.State.Mistakes != 1 {
+		t.Errorf("Move b1 = 5 should be incorrect with conflicts in row, column and square, but result: %+v", result)
+	}
+	hint, err := s.Hint(state.ID)
+	if err != nil {
+		t.Errorf("Hint should pass, but err: %v", err)
+		return
+	}
+	if hint.Step == nil || hint.Step.String() != "a1 = 8 (naked-single)" || len(hint.Mistakes) != 1 ||
+		hint.Mistakes[0] != "b1" || hint.State.Hints != 1 {
+		t.Errorf("Hint should ignore mistake in b1, but returns: %+v", hint)
+	}
+	undone, err := s.Undo(state.ID)
+	if err != nil || undone.Game.Line() != easyGame || undone.Moves != 0 {
+		t.Errorf("Undo should return game to start, but state: %+v, err: %v", undone, err)
+	}
+	if current, err := s.State(state.ID); err != nil || current.Hints != 1 || current.Elapsed <= 0 {
+		t.Errorf("State should keep hints and timer, but state: %+v, err: %v", current, err)
+	}
+	if err = s.End(state.ID); err != nil {
+		t.Errorf("End should pass, but err: %v", err)
+	}
+	if _, err = s.Move(state.ID, "a1", 8); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Move in ended session should return ErrSessionNotFound, but err: %v", err)
+	}
+}
+
+func TestServiceConcurrentMoves(t *testing.T) {
+	s, store := createService(t)
+	s.now = time.Now
+	state, _ := s.Start(context.Background(), "easy")
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := s.Move(state.ID, fmt.Sprintf("a%d", i%2+1), uint8(i%9+1)); err != nil &&
+				!errors.Is(err, ErrGivenCell) {
+				t.Errorf("Move should pass, but err: %v", err)
+			}
+			s.State(state.ID)
+		}(i)
+	}
+	wg.Wait()
+	session, _ := store.Load(state.ID)
+	// a2 is given cell, only moves into a1 are made.
+	if len(session.Moves) != 20 || len(s.locks) != 0 {
+		t.Errorf("Session should contain 20 moves and no lock, but moves: %d, locks: %d", len(session.Moves),
+			len(s.locks))
+	}
+}
@@ -0,0 +1,180 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Errors for session.
+var (
+	ErrGivenCell    error = errors.New("Given cell can not be changed")
+	ErrFinished     error = errors.New("Session is finished")
+	ErrNoMove       error = errors.New("No move to undo")
+	ErrInvalidValue error = errors.New("Value should be number from 0 to grid size")
+)
+
+//Move represents value entered by player, value 0 erases the cell. Previous is
+// value of the cell before the move, it is used by undo.
+type Move struct {
+	CellID   string    `json:"cell"`
+	Value    uint8     `json:"value"`
+	Previous uint8     `json:"previous"`
+	Correct  bool      `json:"correct"`
+	Time     time.Time `json:"time"`
+}
+
+//Session represents state of one played game held by server - game with values
+// entered by player (solution cells), solution of the game, history of moves
+// and timing. Session is plain data so stores can persist it as JSON.
+type Session struct {
+	ID       string           `json:"id"`
+	PuzzleID string           `json:"puzzleId"`
+	Game     *structures.Game `json:"game"`
+	Solution string           `json:"solution"`
+	Moves    []Move           `json:"moves"`
+	Hints    int              `json:"hints"`
+	Started  time.Time        `json:"started"`
+	Finished time.Time        `json:"finished"`
+}
+
+//State represents session as it is seen by player, solution is not included.
+type State struct {
+	ID       string           `json:"id"`
+	PuzzleID string           `json:"puzzleId"`
+	Game     *structures.Game `json:"game"`
+	Moves    int              `json:"moves"`
+	Mistakes int              `json:"mistakes"`
+	Hints    int              `json:"hints"`
+	Solved   bool             `json:"solved"`
+	Elapsed  time.Duration    `json:"elapsed"`
+}
+
+//IsFinished returns if game of session is solved.
+func (s *Session) IsFinished() bool {
+	return !s.Finished.IsZero()
+}
+
+//State method returns state of session at time now, timer stops when game is solved.
+func (s *Session) State(now time.Time) *State {
+	state := State{
+		ID:       s.ID,
+		PuzzleID: s.PuzzleID,
+		Game:     s.Game.Clone(),
+		Moves:    len(s.Moves),
+		Hints:    s.Hints,
+		Solved:   s.IsFinished(),
+	}
+	for _, m := range s.Moves {
+		if !m.Correct && m.Value != structures.EmptyCellValue {
+			state.Mistakes++
+		}
+	}
+	if s.IsFinished() {
+		now = s.Finished
+	}
+	state.Elapsed = now.Sub(s.Started)
+	return &state
+}
+
+//Clone returns deep copy of session.
+func (s *Session) Clone() *Session {
+	clone := *s
+	clone.Game = s.Game.Clone()
+	clone.Moves = append([]Move{}, s.Moves...)
+	return &clone
+}
+
+//Play method puts value into cell (value 0 erases it) and returns the move.
+// Cell id is case insensitive. Move is correct when value matches solution,
+// game is finished when all cells match solution.
+func (s *Session) Play(cellID string, value uint8, now time.Time) (*Move, error) {
+	if s.IsFinished() {
+		return nil, ErrFinished
+	}
+	geometry := s.Game.Geometry()
+	if value > geometry.Size {
+		return nil, ErrInvalidValue
+	}
+	cell, err := geometry.NewSolutionCell(strings.ToLower(strings.TrimSpace(cellID)), value)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.value(cell.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.setValue(cell.Id, value); err != nil {
+		return nil, err
+	}
+	solution, _ := geometry.ParseValue(rune(s.Solution[lineIndex(cell)]))
+	move := Move{CellID: cell.Id, Value: value, Previous: previous, Time: now, Correct: value == solution}
+	s.Moves = append(s.Moves, move)
+	if s.Game.Line() == s.Solution {
+		s.Finished = now
+	}
+	return &move, nil
+}
+
+//Undo method returns the last move back.
+func (s *Session) Undo() (*Move, error) {
+	if s.IsFinished() {
+		return nil, ErrFinished
+	}
+	if len(s.Moves) == 0 {
+		return nil, ErrNoMove
+	}
+	move := s.Moves[len(s.Moves)-1]
+	if err := s.setValue(move.CellID, move.Previous); err != nil {
+		return nil, err
+	}
+	s.Moves = s.Moves[:len(s.Moves)-1]
+	return &move, nil
+}
+
+//mistakes method returns cells whose values do not match solution.
+func (s *Session) mistakes() []string {
+	var cells []string
+	line := s.Game.Line()
+	geometry := s.Game.Geometry()
+	size := int(geometry.Size)
+	for idx := 0; idx < len(line); idx++ {
+		if line[idx] != structures.EmptyCellTextValue[0] && line[idx] != s.Solution[idx] {
+			cells = append(cells, geometry.CellID(uint8(idx/size+1), uint8(idx%size+1)))
+		}
+	}
+	return cells
+}
+
+//value method returns value of cell entered by player, it returns ErrGivenCell
+// for given cell.
+func (s *Session) value(id string) (uint8, error) {
+	c, err := s.Game.Cell(id)
+	if err != nil {
+		return structures.EmptyCellValue, nil
+	}
+	if !c.SolutionCell() {
+		return structures.EmptyCellValue, ErrGivenCell
+	}
+	return c.Value(), nil
+}
+
+//setValue method replaces value of solution cell, value 0 removes the cell.
+func (s *Session) setValue(id string, value uint8) error {
+	s.Game.RemoveSolutionCells([]string{id})
+	if value == structures.EmptyCellValue {
+		return nil
+	}
+	cell, err := s.Game.Geometry().NewSolutionCell(id, value)
+	if err != nil {
+		return err
+	}
+	return s.Game.AddCell(cell)
+}
+
+//lineIndex returns position of cell in single line format.
+func lineIndex(c *structures.Cell) int {
+	return int(c.Row()-1)*int(c.Geometry().Size) + int(c.Column()-1)
+}
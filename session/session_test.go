@@ -0,0 +1,112 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	easyGame   string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	easySolved string = "825314697396782415417965328971853246543276981682491573269138754154627839738549162"
+)
+
+func createSession(t *testing.T) *Session {
+	g, err := structures.ParseLine(easyGame)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	return &Session{ID: "s1", PuzzleID: "p1", Game: g, Solution: easySolved, Started: time.Unix(1000, 0)}
+}
+
+func TestSessionPlay(t *testing.T) {
+	s := createSession(t)
+	tests := []struct {
+		cellID  string
+		value   uint8
+		correct bool
+		err     error
+	}{
+		{"a1", 8, true, nil},
+		{"B1", 3, false, nil},
+		{"b1", 2, true, nil},
+		{"c1", 5, false, ErrGivenCell},
+		{"a2", 10, false, ErrInvalidValue},
+		{"b1", 0, false, nil},
+	}
+	for _, test := range tests {
+		move, err := s.Play(test.cellID, test.value, time.Unix(1010, 0))
+		if !errors.Is(err, test.err) {
+			t.Errorf("Play(%s, %d) returns error: %v, but expected: %v", test.cellID, test.value, err, test.err)
+		}
+		if err == nil && move.Correct != test.correct {
+			t.Errorf("Play(%s, %d) returns move %+v, correct should be: %v", test.cellID, test.value, move,
+				test.correct)
+		}
+	}
+	if line := s.Game.Line(); line != "8."+easyGame[2:] {
+		t.Errorf("Game is %s after moves, but expected: %s", line, "8."+easyGame[2:])
+	}
+	state := s.State(time.Unix(1030, 0))
+	if state.Moves != 4 || state.Mistakes != 1 || state.Solved || state.Elapsed != 30*time.Second {
+		t.Errorf("Session state is %+v, but expected 4 moves, 1 mistake and 30s", state)
+	}
+}
+
+func TestSessionUndo(t *testing.T) {
+	s := createSession(t)
+	s.Play("a1", 7, time.Unix(1010, 0))
+	s.Play("a1", 8, time.Unix(1020, 0))
+	for _, expected := range []string{"7", "."} {
+		if _, err := s.Undo(); err != nil {
+			t.Errorf("Undo should pass, but err: %v", err)
+		}
+		if line := s.Game.Line(); line[:1] != expected {
+			t.Errorf("Cell a1 has value %s after undo, but expected: %s", line[:1], expected)
+		}
+	}
+	if _, err := s.Undo(); !errors.Is(err, ErrNoMove) {
+		t.Errorf("Undo without moves should return ErrNoMove, but err: %v", err)
+	}
+}
+
+func TestSessionFinish(t *testing.T) {
+	s := createSession(t)
+	for idx := range easyGame {
+		if easyGame[idx] != '.' {
+			continue
+		}
+		id := string(rune('a'+idx%9)) + string(rune('1'+idx/9))
+		if _, err := s.Play(id, easySolved[idx]-'0', time.Unix(1100, 0)); err != nil {
+			t.Errorf("Play(%s) should pass, but err: %v", id, err)
+		}
+	}
+	if !s.IsFinished() || s.State(time.Unix(2000, 0)).Elapsed != 100*time.Second {
+		t.Errorf("Session should be finished after 100s, but state is: %+v", s.State(time.Unix(2000, 0)))
+	}
+	if _, err := s.Play("a1", 1, time.Unix(1200, 0)); !errors.Is(err, ErrFinished) {
+		t.Errorf("Play in finished session should return ErrFinished, but err: %v", err)
+	}
+}
+
+func TestSessionJSON(t *testing.T) {
+	s := createSession(t)
+	s.Play("a1", 7, time.Unix(1010, 0))
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Errorf("Session should be marshaled, but err: %v", err)
+	}
+	var decoded Session
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Session should be unmarshaled, but err: %v", err)
+	}
+	if decoded.Game.Line() != s.Game.Line() || len(decoded.Moves) != 1 || !decoded.Started.Equal(s.Started) {
+		t.Errorf("Session decoded as %+v, but expected: %+v", decoded, s)
+	}
+	if _, err = decoded.Undo(); err != nil || decoded.Game.Line() != easyGame {
+		t.Errorf("Decoded session should undo move, but err: %v, game: %s", err, decoded.Game.Line())
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"errors"
+	"sync"
+)
+
+//Errors for session store.
+var (
+	ErrSessionNotFound error = errors.New("Session was not found")
+)
+
+//Store persists sessions. Implementations must be safe for concurrent use,
+// Service serializes changes of one session itself.
+type Store interface {
+	//Load returns session with id or ErrSessionNotFound.
+	Load(id string) (*Session, error)
+	//Save inserts or replaces session.
+	Save(s *Session) error
+	//Delete removes session, it returns ErrSessionNotFound when session does not exist.
+	Delete(id string) error
+}
+
+//MemoryStore is Store which holds sessions in memory, sessions are copied on
+// load and save so callers do not share them.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+//NewMemoryStore creates empty MemoryStore object.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*Session)}
+}
+
+//Load method returns copy of session with id.
+func (m *MemoryStore) Load(id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return s.Clone(), nil
+}
+
+//Save method stores copy of session.
+func (m *MemoryStore) Save(s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = s.Clone()
+	return nil
+}
+
+//Delete method removes session with id.
+func (m *MemoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; !ok {
+		return ErrSessionNotFound
+	}
+	delete(m.sessions, id)
+	return nil
+}
+
+//Len method returns count of stored sessions.
+func (m *MemoryStore) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sessions)
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	s := createSession(t)
+	if err := store.Save(s); err != nil {
+		t.Errorf("Save should pass, but err: %v", err)
+	}
+	// changes of saved session are not visible in store.
+	s.Play("a1", 8, time.Unix(1010, 0))
+	loaded, err := store.Load("s1")
+	if err != nil || len(loaded.Moves) != 0 || loaded.Game.Line() != easyGame {
+		t.Errorf("Load should return saved session, but returns: %+v, err: %v", loaded, err)
+	}
+	loaded.Play("b1", 2, time.Unix(1010, 0))
+	if again, _ := store.Load("s1"); len(again.Moves) != 0 {
+		t.Errorf("Changes of loaded session should not be visible in store, but moves: %v", again.Moves)
+	}
+	if err = store.Delete("s1"); err != nil || store.Len() != 0 {
+		t.Errorf("Delete should remove session, but err: %v, count: %d", err, store.Len())
+	}
+	if _, err = store.Load("s1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Load of deleted session should return ErrSessionNotFound, but err: %v", err)
+	}
+	if err = store.Delete("s1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Delete of deleted session should return ErrSessionNotFound, but err: %v", err)
+	}
+}
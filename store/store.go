@@ -0,0 +1,433 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/session"
+	"github.com/chytilp/sudoku/structures"
+)
+
+//Operations of database log.
+const (
+	opPut    string = "put"
+	opDelete string = "delete"
+)
+
+//Errors for puzzle store.
+var (
+	ErrNotFound     error = errors.New("Puzzle was not found in store")
+	ErrClosed       error = errors.New("Store is closed")
+	ErrInvalidEntry error = errors.New("Invalid entry of store log")
+	ErrKillerGame   error = errors.New("Game with cages can not be stored")
+	ErrVariantGame  error = errors.New("Game with diagonals or windows can not be stored")
+)
+
+//Record represents puzzle stored in database. Game contains only given cells,
+// Canonical is canonical form of the game (see structures.Canonicalize) used
+// for deduplication, it is recomputed from Game when store is opened. Score and
+// Level are rating of the game and Givens is count of given cells.
+type Record struct {
+	ID        int       `json:"id"`
+	Game      string    `json:"game"`
+	Canonical string    `json:"canonical"`
+	Solution  string    `json:"solution"`
+	Name      string    `json:"name,omitempty"`
+	Level     string    `json:"level"`
+	Score     int       `json:"score"`
+	Source    string    `json:"source,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Givens    int       `json:"givens"`
+	Added     time.Time `json:"added"`
+}
+
+//HasTag returns if record is tagged by tag, tags are case insensitive.
+func (r *Record) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+//Puzzle method returns record as puzzle, tags are joined into the first extra field.
+func (r *Record) Puzzle() (*structures.Puzzle, error) {
+	g, err := structures.ParseLine(r.Game)
+	if err != nil {
+		return nil, err
+	}
+	p := structures.Puzzle{Game: g, Name: r.Name, Rating: r.Level, Source: r.Source}
+	if len(r.Tags) > 0 {
+		p.Extra = []string{strings.Join(r.Tags, ",")}
+	}
+	return &p, nil
+}
+
+//clone returns copy of record, tags are not shared.
+func (r *Record) clone() *Record {
+	clone := *r
+	clone.Tags = append([]string(nil), r.Tags...)
+	return &clone
+}
+
+//DuplicateError is returned when stored puzzle has the same canonical form as
+// added one.
+type DuplicateError struct {
+	Existing *Record
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("puzzle is duplicate of puzzle %d", e.Existing.ID)
+}
+
+//Query represents conditions of search, zero values mean no condition. Score
+// is rating score (1 - 5), results are ordered by id and limited by Limit.
+type Query struct {
+	Level     string
+	MinScore  int
+	MaxScore  int
+	Tag       string
+	MinGivens int
+	MaxGivens int
+	Limit     int
+}
+
+//matches returns if record fulfills conditions of query.
+func (q *Query) matches(r *Record) bool {
+	return (q.Level == "" || r.Level == q.Level) && (q.MinScore == 0 || r.Score >= q.MinScore) &&
+		(q.MaxScore == 0 || r.Score <= q.MaxScore) && (q.MinGivens == 0 || r.Givens >= q.MinGivens) &&
+		(q.MaxGivens == 0 || r.Givens <= q.MaxGivens) && (q.Tag == "" || r.HasTag(q.Tag))
+}
+
+//entry is one line of database log.
+type entry struct {
+	Op     string  `json:"op"`
+	ID     int     `json:"id,omitempty"`
+	Record *Record `json:"record,omitempty"`
+}
+
+//Store is embedded puzzle database kept in one file. File is log of changes
+// (one JSON entry per line) which is replayed when store is opened, all
+// records are held in memory. Store is safe for concurrent use, but the file
+// must not be opened by more processes at once.
+type Store struct {
+	mu          sync.RWMutex
+	path        string
+	file        *os.File
+	records     map[int]*Record
+	byCanonical map[string]int
+	nextID      int
+	now         func() time.Time
+}
+
+//Open opens store in file given by path, file is created when it does not exist.
+// Incomplete last entry (e.g. after crash during write) is discarded.
+func Open(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, file: file, records: make(map[int]*Record), byCanonical: make(map[string]int),
+		nextID: 1, now: time.Now}
+	if err = s.replay(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+//replay method reads log from file and applies its entries.
+func (s *Store) replay() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if err = s.file.Truncate(offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(line))
+		var e entry
+		if err = json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if (e.Op != opPut && e.Op != opDelete) || (e.Op == opPut && e.Record == nil) {
+			return fmt.Errorf("line %d: %w", lineNumber, ErrInvalidEntry)
+		}
+		if e.Op == opPut {
+			// canonical form written by older version can differ from current one.
+			g, err := structures.ParseLine(e.Record.Game)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", lineNumber, ErrInvalidEntry)
+			}
+			e.Record.Canonical, _ = structures.Canonicalize(g)
+		}
+		s.apply(&e)
+	}
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+//apply method applies log entry to records in memory.
+func (s *Store) apply(e *entry) {
+	switch e.Op {
+	case opPut:
+		if old, ok := s.records[e.Record.ID]; ok {
+			delete(s.byCanonical, old.Canonical)
+		}
+		s.records[e.Record.ID] = e.Record
+		s.byCanonical[e.Record.Canonical] = e.Record.ID
+		if e.Record.ID >= s.nextID {
+			s.nextID = e.Record.ID + 1
+		}
+	case opDelete:
+		if old, ok := s.records[e.ID]; ok {
+			delete(s.byCanonical, old.Canonical)
+			delete(s.records, e.ID)
+		}
+	}
+}
+
+//write method appends entry to log, syncs the file and applies the entry.
+func (s *Store) write(e *entry) error {
+	if s.file == nil {
+		return ErrClosed
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err = s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err = s.file.Sync(); err != nil {
+		return err
+	}
+	s.apply(e)
+	return nil
+}
+
+//Add method solves and rates puzzle and stores it with tags. Puzzle must have
+// unique solution, DuplicateError is returned when equivalent puzzle is
+// already stored. Records keep only single line of givens, so killer games
+// and games of other variants than classic are not supported.
+func (s *Store) Add(ctx context.Context, p *structures.Puzzle, tags ...string) (*Record, error) {
+	if len(p.Game.Cages()) > 0 {
+		return nil, ErrKillerGame
+	}
+	if p.Game.Variant() != structures.VariantClassic {
+		return nil, ErrVariantGame
+	}
+	g, err := structures.ParseLine(p.Game.GivensLine())
+	if err != nil {
+		return nil, err
+	}
+	e := engine.NewEngine(g.Clone())
+	if _, err = e.SolveUnique(ctx); err != nil {
+		return nil, err
+	}
+	rating, err := engine.Rate(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+	canonical, _ := structures.Canonicalize(g)
+	record := &Record{Game: g.Line(), Canonical: canonical, Solution: e.Game().Line(), Name: p.Name,
+		Level: rating.Level, Score: rating.Score, Source: p.Source, Tags: normalizeTags(tags),
+		Givens: int(g.FilledCellCount())}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.byCanonical[record.Canonical]; ok {
+		return nil, &DuplicateError{Existing: s.records[id].clone()}
+	}
+	record.ID = s.nextID
+	record.Added = s.now()
+	if err = s.write(&entry{Op: opPut, Record: record}); err != nil {
+		return nil, err
+	}
+	return record.clone(), nil
+}
+
+//Get method returns record with id.
+func (s *Store) Get(id int) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return r.clone(), nil
+}
+
+//Find method returns stored record equivalent to given cells of the game.
+func (s *Store) Find(g *structures.Game) (*Record, error) {
+	givens, err := structures.ParseLine(g.GivensLine())
+	if err != nil {
+		return nil, err
+	}
+	canonical, _ := structures.Canonicalize(givens)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.byCanonical[canonical]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.records[id].clone(), nil
+}
+
+//SetTags method replaces tags of record with id.
+func (s *Store) SetTags(id int, tags ...string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	record := r.clone()
+	record.Tags = normalizeTags(tags)
+	if err := s.write(&entry{Op: opPut, Record: record}); err != nil {
+		return nil, err
+	}
+	return record.clone(), nil
+}
+
+//Delete method removes record with id.
+func (s *Store) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[id]; !ok {
+		return ErrNotFound
+	}
+	return s.write(&entry{Op: opDelete, ID: id})
+}
+
+//Query method returns records matching query ordered by id.
+func (s *Store) Query(q Query) []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]int, 0, len(s.records))
+	for id, r := range s.records {
+		if q.matches(r) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	if q.Limit > 0 && len(ids) > q.Limit {
+		ids = ids[:q.Limit]
+	}
+	records := make([]*Record, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, s.records[id].clone())
+	}
+	return records
+}
+
+//Len method returns count of stored records.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+//Puzzle method returns puzzle with id (decimal id of record), it allows store
+// to be used as puzzle source of play sessions.
+func (s *Store) Puzzle(id string) (*structures.Puzzle, error) {
+	number, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, session.ErrPuzzleNotFound
+	}
+	r, err := s.Get(number)
+	if err != nil {
+		return nil, session.ErrPuzzleNotFound
+	}
+	return r.Puzzle()
+}
+
+//Compact method rewrites log so it contains only current records with their
+// current canonical form, old log is replaced atomically.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	tmp, err := os.OpenFile(s.path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	ids := make([]int, 0, len(s.records))
+	for id := range s.records {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	w := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(w)
+	for _, id := range ids {
+		if err = encoder.Encode(&entry{Op: opPut, Record: s.records[id]}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	s.file.Close()
+	s.file = tmp
+	return nil
+}
+
+//Close method closes file of store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+//normalizeTags returns trimmed non-empty tags without duplicates.
+func normalizeTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		duplicate := tag == ""
+		for _, t := range result {
+			duplicate = duplicate || strings.EqualFold(t, tag)
+		}
+		if !duplicate {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
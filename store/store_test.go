@@ -0,0 +1,245 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chytilp/sudoku/engine"
+	"github.com/chytilp/sudoku/session"
+	"github.com/chytilp/sudoku/structures"
+)
+
+const (
+	easyGame   string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	easySolved string = "825314697396782415417965328971853246543276981682491573269138754154627839738549162"
+	hardGame   string = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."
+)
+
+func puzzle(t *testing.T, line string, name string) *structures.Puzzle {
+	g, err := structures.ParseLine(line)
+	if err != nil {
+		t.Fatalf("Game should be parsed, but err: %v", err)
+	}
+	return &structures.Puzzle{Game: g, Name: name, Source: "test"}
+}
+
+func openStore(t *testing.T) (*Store, string) {
+	path := filepath.Join(t.TempDir(), "puzzles.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Store should be opened, but err: %v", err)
+	}
+	return s, path
+}
+
+func TestStoreAdd(t *testing.T) {
+	s, _ := openStore(t)
+	defer s.Close()
+	r, err := s.Add(context.Background(), puzzle(t, easyGame, "easy"), "daily", " ", "Daily", "classic")
+	if err != nil {
+		t.Errorf("Puzzle should be added, but err: %v", err)
+		return
+	}
+	expected := Record{ID: 1, Game: easyGame, Solution: easySolved, Name: "easy", Level: engine.LevelEasy,
+		Score: 1, Source: "test", Givens: 36}
+	if r.ID != expected.ID || r.Game != expected.Game || r.Solution != expected.Solution || r.Name != expected.Name ||
+		r.Level != expected.Level || r.Score != expected.Score || r.Source != expected.Source ||
+		r.Givens != expected.Givens || strings.Join(r.Tags, ",") != "daily,classic" {
+		t.Errorf("Added record is %+v, but expected: %+v with tags daily,classic", r, expected)
+	}
+	// digits 1 and 2 are swapped.
+	relabeled := strings.NewReplacer("1", "2", "2", "1").Replace(easyGame)
+	transform := structures.IdentityTransform()
+	transform.Transpose = true
+	transformed, _ := transform.Apply(puzzle(t, relabeled, "").Game)
+	for _, g := range []*structures.Game{puzzle(t, relabeled, "").Game, transformed} {
+		_, err = s.Add(context.Background(), &structures.Puzzle{Game: g})
+		var duplicate *DuplicateError
+		if !errors.As(err, &duplicate) || duplicate.Existing.ID != 1 {
+			t.Errorf("Puzzle %s should be duplicate of puzzle 1, but err: %v", g.Line(), err)
+		}
+	}
+	_, err = s.Add(context.Background(), puzzle(t, easyGame[:2]+"."+easyGame[3:], ""))
+	if err != nil {
+		t.Errorf("Puzzle with other givens should be added, but err: %v", err)
+	}
+	_, err = s.Add(context.Background(), puzzle(t, strings.Repeat(".", 81), ""))
+	if !errors.Is(err, engine.ErrMultipleSolutions) {
+		t.Errorf("Empty puzzle should not be added, but err: %v", err)
+	}
+	_, err = s.Add(context.Background(), puzzle(t, "55"+hardGame[2:], ""))
+	if !errors.Is(err, engine.ErrUnsolvable) {
+		t.Errorf("Unsolvable puzzle should not be added, but err: %v", err)
+	}
+	killer := puzzle(t, easyGame, "")
+	killer.Game.AddCage(&structures.Cage{Sum: 10, Cells: []string{"a1", "b1"}})
+	if _, err = s.Add(context.Background(), killer); !errors.Is(err, ErrKillerGame) {
+		t.Errorf("Killer puzzle should not be added, but err: %v", err)
+	}
+	diagonal := puzzle(t, easyGame, "")
+	diagonal.Game.SetVariant(structures.VariantDiagonal)
+	if _, err = s.Add(context.Background(), diagonal); !errors.Is(err, ErrVariantGame) {
+		t.Errorf("Diagonal puzzle should not be added, but err: %v", err)
+	}
+	found, err := s.Find(transformed)
+	if err != nil || found.ID != 1 {
+		t.Errorf("Equivalent puzzle should be found, but found: %v, err: %v", found, err)
+	}
+	if s.Len() != 2 {
+		t.Errorf("Store should contain 2 puzzles, but contains: %d", s.Len())
+	}
+}
+
+func TestStoreQuery(t *testing.T) {
+	s, _ := openStore(t)
+	defer s.Close()
+	for _, p := range []*structures.Puzzle{puzzle(t, easyGame, "easy"), puzzle(t, hardGame, "hard")} {
+		if _, err := s.Add(context.Background(), p, p.Name); err != nil {
+			t.Errorf("Puzzle should be added, but err: %v", err)
+			return
+		}
+	}
+	tests := []struct {
+		query    Query
+		expected string
+	}{
+		{Query{}, "easy,hard"},
+		{Query{MinScore: 2}, "hard"},
+		{Query{Level: engine.LevelEasy}, "easy"},
+		{Query{MaxScore: 1}, "easy"},
+		{Query{Tag: "HARD"}, "hard"},
+		{Query{Tag: "daily"}, ""},
+		{Query{MinGivens: 30}, "easy"},
+		{Query{MaxGivens: 21}, "hard"},
+		{Query{Limit: 1}, "easy"},
+	}
+	for _, test := range tests {
+		var names []string
+		for _, r := range s.Query(test.query) {
+			names = append(names, r.Name)
+		}
+		if strings.Join(names, ",") != test.expected {
+			t.Errorf("Query %+v returns %v, but expected: %s", test.query, names, test.expected)
+		}
+	}
+}
+
+func TestStoreReopen(t *testing.T) {
+	s, path := openStore(t)
+	for _, line := range []string{easyGame, hardGame, easyGame[:2] + "." + easyGame[3:]} {
+		if _, err := s.Add(context.Background(), puzzle(t, line, "")); err != nil {
+			t.Errorf("Puzzle should be added, but err: %v", err)
+			return
+		}
+	}
+	if err := s.Delete(2); err != nil {
+		t.Errorf("Puzzle should be deleted, but err: %v", err)
+	}
+	if err := s.Delete(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Deleted puzzle should not be found, but err: %v", err)
+	}
+	if _, err := s.SetTags(3, "x"); err != nil {
+		t.Errorf("Tags should be set, but err: %v", err)
+	}
+	s.Close()
+	if _, err := s.Get(1); err != nil {
+		t.Errorf("Records should be readable after close, but err: %v", err)
+	}
+	if err := s.Delete(1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Closed store should not be changed, but err: %v", err)
+	}
+	// incomplete entry written during crash.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	file.WriteString(`{"op":"put","record":{"id":`)
+	file.Close()
+	for i := 0; i < 2; i++ {
+		s, err := Open(path)
+		if err != nil {
+			t.Errorf("Store should be reopened, but err: %v", err)
+			return
+		}
+		r, err := s.Get(3)
+		if s.Len() != 2 || err != nil || strings.Join(r.Tags, ",") != "x" {
+			t.Errorf("Reopened store should contain 2 puzzles and tags of puzzle 3, but contains: %d, %v (err: %v)",
+				s.Len(), r, err)
+		}
+		if i == 0 {
+			if err = s.Compact(); err != nil {
+				t.Errorf("Store should be compacted, but err: %v", err)
+			}
+			r, err = s.Add(context.Background(), puzzle(t, hardGame, ""))
+			if err != nil || r.ID != 4 {
+				t.Errorf("Puzzle should be added with id 4 after compaction, but is: %v, err: %v", r, err)
+			}
+			s.Delete(4)
+		}
+		s.Close()
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Count(string(data), "\n") != 4 {
+		t.Errorf("Compacted log should contain 4 entries, but is: %s", data)
+	}
+}
+
+func TestStoreInvalidLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzles.db")
+	os.WriteFile(path, []byte("{\"op\":\"put\"}\n"), 0644)
+	if _, err := Open(path); !errors.Is(err, ErrInvalidEntry) {
+		t.Errorf("Store with invalid entry should not be opened, but err: %v", err)
+	}
+}
+
+func TestStoreStaleCanonical(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzles.db")
+	log := `{"op":"put","record":{"id":1,"game":"` + easyGame + `","canonical":"stale","solution":"` + easySolved +
+		`","level":"easy","score":1,"givens":36,"added":"2026-01-01T00:00:00Z"}}` + "\n"
+	os.WriteFile(path, []byte(log), 0644)
+	s, err := Open(path)
+	if err != nil {
+		t.Errorf("Store should be opened, but err: %v", err)
+		return
+	}
+	defer s.Close()
+	relabeled := strings.NewReplacer("1", "2", "2", "1").Replace(easyGame)
+	if r, err := s.Find(puzzle(t, relabeled, "").Game); err != nil || r.ID != 1 {
+		t.Errorf("Equivalent puzzle should be found, but is: %v, err: %v", r, err)
+	}
+	var duplicate *DuplicateError
+	if _, err = s.Add(context.Background(), puzzle(t, relabeled, "")); !errors.As(err, &duplicate) {
+		t.Errorf("Add should return DuplicateError, but returned: %v", err)
+	}
+	if err = s.Compact(); err != nil {
+		t.Errorf("Store should be compacted, but err: %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "stale") {
+		t.Errorf("Compacted log should contain current canonical form, but is: %s", data)
+	}
+	os.WriteFile(path, []byte(`{"op":"put","record":{"id":1,"game":"x"}}`+"\n"), 0644)
+	if _, err = Open(path); !errors.Is(err, ErrInvalidEntry) {
+		t.Errorf("Store with invalid game should not be opened, but err: %v", err)
+	}
+}
+
+func TestStorePuzzle(t *testing.T) {
+	s, _ := openStore(t)
+	defer s.Close()
+	if _, err := s.Add(context.Background(), puzzle(t, easyGame, "easy"), "a", "b"); err != nil {
+		t.Errorf("Puzzle should be added, but err: %v", err)
+		return
+	}
+	var source session.PuzzleSource = s
+	p, err := source.Puzzle("1")
+	if err != nil || p.Game.Line() != easyGame || p.Name != "easy" || p.Rating != engine.LevelEasy ||
+		strings.Join(p.Extra, "|") != "a,b" {
+		t.Errorf("Puzzle 1 should be easy game with metadata, but is: %+v (err: %v)", p, err)
+	}
+	for _, id := range []string{"2", "x"} {
+		if _, err = source.Puzzle(id); !errors.Is(err, session.ErrPuzzleNotFound) {
+			t.Errorf("Puzzle %s should not be found, but err: %v", id, err)
+		}
+	}
+}
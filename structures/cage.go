@@ -0,0 +1,308 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Messages for cage errors.
+const (
+	ErrCellInCageMsg string = "Cell id=%s is already in cage."
+)
+
+//Characters of killer format.
+const (
+	cageSumSep     string = ":"
+	killerComment  string = "#"
+	cageCellSep    string = ","
+	cageCellsSpace string = " "
+	variantKey     string = "variant"
+)
+
+//Errors for cages of killer sudoku.
+var (
+	ErrCageNoCells     error = errors.New("Cage should contain at least one cell")
+	ErrCageSum         error = errors.New("Cage sum can not be made from distinct values of its cells")
+	ErrCageDigits      error = errors.New("Digits of game with cages can not be relabeled")
+	ErrParseCage       error = errors.New("Invalid cage, expected sum and cells, ex. 15: a1 a2 b1")
+	ErrParseKillerGame error = errors.New("Givens of killer game should be on the first line")
+)
+
+//Cage represents cage of killer sudoku - cells whose values are distinct and
+// give Sum together. Cells of cage added to the game are ordered by rows.
+type Cage struct {
+	Sum   int      `json:"sum"`
+	Cells []string `json:"cells"`
+}
+
+//String returns text representation of cage, ex. 15: a1 a2 b1.
+func (c *Cage) String() string {
+	return fmt.Sprintf("%d%s %s", c.Sum, cageSumSep, strings.Join(c.Cells, cageCellsSpace))
+}
+
+//SumCombinations returns all combinations of count distinct values which give
+// sum, values must be ordered from the lowest one. Values of every combination
+// are ordered too.
+func SumCombinations(sum int, count int, values []uint8) []Values {
+	if count == 0 {
+		if sum == 0 {
+			return []Values{{}}
+		}
+		return nil
+	}
+	var result []Values
+	for idx, value := range values {
+		if int(value) > sum {
+			break
+		}
+		for _, rest := range SumCombinations(sum-int(value), count-1, values[idx+1:]) {
+			result = append(result, append(Values{value}, rest...))
+		}
+	}
+	return result
+}
+
+//AddCage method adds cage of killer sudoku to the game. Cells of the cage
+// should not be in other cage and its sum should be possible for count of
+// its cells.
+func (g *Game) AddCage(c *Cage) error {
+	if len(c.Cells) == 0 {
+		return ErrCageNoCells
+	}
+	geometry := g.Geometry()
+	cells := make([]*Cell, 0, len(c.Cells))
+	added := make(map[string]bool)
+	for _, id := range c.Cells {
+		cell, err := geometry.NewCell(id, EmptyCellValue)
+		if err != nil {
+			return err
+		}
+		if added[cell.Id] || g.CageOf(cell.Id) != nil {
+			return &CageCellError{ID: cell.Id}
+		}
+		added[cell.Id] = true
+		cells = append(cells, cell)
+	}
+	count, size := len(cells), int(geometry.Size)
+	if count > size || c.Sum < count*(count+1)/2 || c.Sum > count*(2*size-count+1)/2 {
+		return ErrCageSum
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Row() != cells[j].Row() {
+			return cells[i].Row() < cells[j].Row()
+		}
+		return cells[i].Column() < cells[j].Column()
+	})
+	cage := Cage{Sum: c.Sum, Cells: make([]string, len(cells))}
+	for idx, cell := range cells {
+		cage.Cells[idx] = cell.Id
+	}
+	g.cages = append(g.cages, &cage)
+	return nil
+}
+
+//Cages returns copy of cages of the game in order they were added, game
+// without cages is classic sudoku.
+func (g *Game) Cages() []*Cage {
+	result := make([]*Cage, len(g.cages))
+	for idx, c := range g.cages {
+		result[idx] = &Cage{Sum: c.Sum, Cells: append([]string{}, c.Cells...)}
+	}
+	return result
+}
+
+//CageOf returns cage which contains cell with id, nil when cell is in no cage.
+func (g *Game) CageOf(id string) *Cage {
+	for _, c := range g.cages {
+		for _, cellID := range c.Cells {
+			if cellID == id {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+//ReadKiller reads killer game. The first line contains givens in single line
+// format, it can be omitted for empty 9x9 grid. Every other line is one cage
+// written as sum and cells, ex. 15: a1 a2 b1, or variant of the game, ex.
+// variant: diagonal. Empty lines and lines starting with # are skipped.
+func ReadKiller(r io.Reader) (*Game, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var g *Game
+	variant := VariantClassic
+	for _, line := range lines {
+		text := strings.TrimSpace(line.text)
+		if text == "" || strings.HasPrefix(text, killerComment) {
+			continue
+		}
+		if isVariantLine(text) {
+			if variant, err = ParseVariant(text[strings.Index(text, cageSumSep)+1:]); err != nil {
+				return nil, newParseError(line.number, 0, err)
+			}
+			continue
+		}
+		if !isCageLine(text) {
+			if g != nil {
+				return nil, newParseError(line.number, 0, ErrParseKillerGame)
+			}
+			if g, err = ParseLine(text); err != nil {
+				var pe *ParseError
+				if errors.As(err, &pe) {
+					pe.Line = line.number
+				}
+				return nil, err
+			}
+			continue
+		}
+		if g == nil {
+			if g, err = NewGame(StandardGeometry, nil); err != nil {
+				return nil, err
+			}
+		}
+		cage, err := parseCage(text)
+		if err != nil {
+			return nil, newParseError(line.number, 0, err)
+		}
+		if err = g.AddCage(cage); err != nil {
+			return nil, newParseError(line.number, 0, err)
+		}
+	}
+	if g == nil {
+		if g, err = NewGame(StandardGeometry, nil); err != nil {
+			return nil, err
+		}
+	}
+	g.SetVariant(variant)
+	return g, nil
+}
+
+//WriteKiller writes killer game, see ReadKiller.
+func WriteKiller(w io.Writer, g *Game) error {
+	_, err := io.WriteString(w, g.killerText())
+	return err
+}
+
+//Package private functions and methods.
+
+//killerText returns game in killer format, single line with all values,
+// variant line for other than classic game and one line for every cage.
+func (g *Game) killerText() string {
+	var text strings.Builder
+	text.WriteString(g.Line() + "\n")
+	if g.variant != VariantClassic {
+		text.WriteString(variantKey + cageSumSep + " " + g.variant.String() + "\n")
+	}
+	for _, c := range g.cages {
+		text.WriteString(c.String() + "\n")
+	}
+	return text.String()
+}
+
+//candidates method returns values which can be in empty cells of the cage -
+// values of combinations of distinct values which are not placed in the cage
+// yet and give rest of its sum.
+func (c *Cage) candidates(g *Game) []uint8 {
+	placed := make(map[uint8]bool)
+	rest, empty := c.Sum, 0
+	for _, id := range c.Cells {
+		if cell, ok := g.cells[id]; ok && cell.Value() != EmptyCellValue {
+			placed[cell.Value()] = true
+			rest -= int(cell.Value())
+			continue
+		}
+		empty++
+	}
+	var free []uint8
+	for value := uint8(1); value <= g.Geometry().Size; value++ {
+		if !placed[value] {
+			free = append(free, value)
+		}
+	}
+	found := make(map[uint8]bool)
+	for _, combination := range SumCombinations(rest, empty, free) {
+		for _, value := range combination {
+			found[value] = true
+		}
+	}
+	var result []uint8
+	for _, value := range free {
+		if found[value] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+//validateCages returns repeated values in cages and cages whose values can
+// not give their sum (sum of full cage differs, sum of other cage is reached).
+func (g *Game) validateCages() ([]Conflict, error) {
+	var conflicts []Conflict
+	for idx, c := range g.cages {
+		index := uint8(idx + 1)
+		valueCells := make(map[uint8][]string)
+		sum, empty := 0, 0
+		for _, id := range c.Cells {
+			cell, ok := g.cells[id]
+			if !ok || cell.Value() == EmptyCellValue {
+				empty++
+				continue
+			}
+			sum += int(cell.Value())
+			valueCells[cell.Value()] = append(valueCells[cell.Value()], id)
+		}
+		for value := uint8(1); value <= g.Geometry().Size; value++ {
+			if ids := valueCells[value]; len(ids) > 1 {
+				sort.Strings(ids)
+				conflicts = append(conflicts, Conflict{Unit: UnitCage, Index: index, Value: value, CellIds: ids})
+			}
+		}
+		if (empty == 0 && sum != c.Sum) || (empty > 0 && sum >= c.Sum) {
+			ids := append([]string{}, c.Cells...)
+			sort.Strings(ids)
+			conflicts = append(conflicts, Conflict{Unit: UnitCage, Index: index, Sum: c.Sum, CellIds: ids})
+		}
+	}
+	return conflicts, nil
+}
+
+//isCageLine returns if line of killer format starts with cage sum.
+func isCageLine(line string) bool {
+	idx := strings.Index(line, cageSumSep)
+	if idx <= 0 {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimSpace(line[:idx]))
+	return err == nil
+}
+
+//isVariantLine returns if line of killer format sets variant of the game.
+func isVariantLine(line string) bool {
+	idx := strings.Index(line, cageSumSep)
+	return idx > 0 && strings.ToLower(strings.TrimSpace(line[:idx])) == variantKey
+}
+
+//parseCage parses one cage of killer format, cells can be separated by spaces
+// or commas.
+func parseCage(line string) (*Cage, error) {
+	idx := strings.Index(line, cageSumSep)
+	if idx <= 0 {
+		return nil, ErrParseCage
+	}
+	sum, err := strconv.Atoi(strings.TrimSpace(line[:idx]))
+	if err != nil {
+		return nil, ErrParseCage
+	}
+	cells := strings.Fields(strings.Replace(line[idx+1:], cageCellSep, cageCellsSpace, -1))
+	if len(cells) == 0 {
+		return nil, ErrParseCage
+	}
+	return &Cage{Sum: sum, Cells: cells}, nil
+}
@@ -0,0 +1,175 @@
+package structures
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const killer1 string = `# two cages of killer game
+.................................................................................
+3: b1 a1
+24: g9, h9, i9
+`
+
+func createKillerGame(t *testing.T) *Game {
+	g, err := ReadKiller(strings.NewReader(killer1))
+	if err != nil {
+		t.Errorf("ReadKiller should pass, but err: %v", err)
+	}
+	return g
+}
+
+func TestGameAddCage(t *testing.T) {
+	g := createKillerGame(t)
+	cages := g.Cages()
+	if len(cages) != 2 || cages[0].String() != "3: a1 b1" || cages[1].String() != "24: g9 h9 i9" {
+		t.Errorf("Game has cages %v, but expected: [3: a1 b1 24: g9 h9 i9]", cages)
+	}
+	if g.CageOf("h9") == nil || g.CageOf("h9").Sum != 24 || g.CageOf("e5") != nil {
+		t.Errorf("Game.CageOf should return cage with cell h9 and nil for cell e5")
+	}
+	tests := []struct {
+		cage     Cage
+		expected error
+	}{
+		{Cage{Sum: 3}, ErrCageNoCells},
+		{Cage{Sum: 3, Cells: []string{"c1", "c1"}}, &CageCellError{ID: "c1"}},
+		{Cage{Sum: 3, Cells: []string{"c1", "b1"}}, &CageCellError{ID: "b1"}},
+		{Cage{Sum: 2, Cells: []string{"c1", "d1"}}, ErrCageSum},
+		{Cage{Sum: 18, Cells: []string{"c1", "d1"}}, ErrCageSum},
+		{Cage{Sum: 5, Cells: []string{"c1", "k1"}}, &CellIDError{ID: "k1"}},
+	}
+	for _, test := range tests {
+		err := g.AddCage(&test.cage)
+		if err == nil || err.Error() != test.expected.Error() {
+			t.Errorf("Game.AddCage(%v) returns err: %v, but expected: %v", test.cage, err, test.expected)
+		}
+	}
+	if len(g.Cages()) != 2 {
+		t.Errorf("Invalid cages should not be added, but game has: %v", g.Cages())
+	}
+}
+
+func TestSumCombinations(t *testing.T) {
+	all := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	tests := []struct {
+		sum, count int
+		values     []uint8
+		expected   []Values
+	}{
+		{10, 2, all, []Values{{1, 9}, {2, 8}, {3, 7}, {4, 6}}},
+		{6, 3, all, []Values{{1, 2, 3}}},
+		{45, 9, all, []Values{all}},
+		{4, 2, []uint8{1, 2, 3}, []Values{{1, 3}}},
+		{5, 3, all, nil},
+	}
+	for _, test := range tests {
+		result := SumCombinations(test.sum, test.count, test.values)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("SumCombinations(%d, %d) returns %v, but expected: %v", test.sum, test.count, result,
+				test.expected)
+		}
+	}
+}
+
+func TestCageFreeValues(t *testing.T) {
+	g := createKillerGame(t)
+	tests := map[string]string{"a1": "12", "h9": "789", "e5": "123456789"}
+	for id, expected := range tests {
+		values, err := g.CellFreeValues(mustCell(t, StandardGeometry, id))
+		if err != nil || valuesText(values) != expected {
+			t.Errorf("Cell %s has free values %v (err: %v), but expected: %s", id, values, err, expected)
+		}
+	}
+	if err := g.AddCell(createSolutionCell("a1", 1)); err != nil {
+		t.Errorf("Game.AddCell should pass, but err: %v", err)
+	}
+	values, err := g.CellFreeValues(mustCell(t, StandardGeometry, "b1"))
+	if err != nil || valuesText(values) != "2" {
+		t.Errorf("Cell b1 has free values %v (err: %v), but expected: 2", values, err)
+	}
+}
+
+func TestCageValidation(t *testing.T) {
+	g := createKillerGame(t)
+	g.AddCell(createSolutionCell("a1", 2))
+	g.AddCell(createSolutionCell("b1", 2))
+	g.AddCell(createSolutionCell("g9", 9))
+	report, err := g.ValidationReport()
+	if err != nil {
+		t.Errorf("Game.ValidationReport should pass, but err: %v", err)
+		return
+	}
+	var conflicts []string
+	for _, c := range report.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+	expected := []string{
+		"row 1: value 2 in cells [a1 b1]",
+		"square 1: value 2 in cells [a1 b1]",
+		"cage 1: value 2 in cells [a1 b1]",
+		"cage 1: cells [a1 b1] can not give sum 3",
+	}
+	if !reflect.DeepEqual(conflicts, expected) || report.UnitOk(UnitCage) {
+		t.Errorf("Game has conflicts %v, but expected: %v", conflicts, expected)
+	}
+}
+
+func TestKillerFormat(t *testing.T) {
+	g := createKillerGame(t)
+	var buffer strings.Builder
+	if err := Save(&buffer, g, FormatKiller); err != nil {
+		t.Errorf("Save in killer format should pass, but err: %v", err)
+	}
+	expected := strings.Repeat(".", LineLength) + "\n3: a1 b1\n24: g9 h9 i9\n"
+	if buffer.String() != expected {
+		t.Errorf("Killer game is written as\n%s, but expected:\n%s", buffer.String(), expected)
+	}
+	if format := DetectFormat([]byte("3: a1 b1\n")); format != FormatKiller {
+		t.Errorf("Cages should be detected as killer format, but format is: %s", format)
+	}
+	tests := []struct {
+		text     string
+		line     int
+		expected error
+	}{
+		{"3: a1 b1\n" + strings.Repeat(".", LineLength) + "\n", 2, ErrParseKillerGame},
+		{"\n3:\n", 2, ErrParseCage},
+		{"3: a1 b1\n4: b1 c1\n", 2, &CageCellError{ID: "b1"}},
+		{"# comment\n..1\n", 2, ErrParseLineLength},
+	}
+	for _, test := range tests {
+		_, err := ReadKiller(strings.NewReader(test.text))
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Line != test.line ||
+			!strings.Contains(err.Error(), test.expected.Error()) {
+			t.Errorf("ReadKiller(%q) returns err: %v, but expected: %v on line %d", test.text, err,
+				test.expected, test.line)
+		}
+	}
+}
+
+func TestCageTransform(t *testing.T) {
+	g := createKillerGame(t)
+	transposed, err := g.Transform(Transpose())
+	if err != nil {
+		t.Errorf("Game.Transform should pass, but err: %v", err)
+		return
+	}
+	if cages := transposed.Cages(); len(cages) != 2 || cages[0].String() != "3: a1 a2" ||
+		cages[1].String() != "24: i7 i8 i9" {
+		t.Errorf("Transposed game has cages %v, but expected: [3: a1 a2 24: i7 i8 i9]", cages)
+	}
+	digits, _ := PermuteDigits([9]uint8{2, 1, 3, 4, 5, 6, 7, 8, 9})
+	if _, err = g.Transform(digits); !errors.Is(err, ErrCageDigits) {
+		t.Errorf("Game.Transform should return ErrCageDigits, but returned: %v", err)
+	}
+	if canonical, transform := Canonicalize(g); canonical != g.killerText() || transform != nil {
+		t.Errorf("Canonicalize returns %s, but expected game in killer format", canonical)
+	}
+	if AreEquivalent(g, transposed) || !AreEquivalent(g, g.Clone()) {
+		t.Errorf("Killer games are equivalent only with the same cages")
+	}
+}
@@ -0,0 +1,189 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Characters of candidate grid layout.
+const (
+	candidateBoxSep    string = "|"
+	candidateCorner    string = "*"
+	candidateCrossing  string = "+"
+	candidateLine      string = "-"
+	candidateCellSpace string = "  "
+	//candidateSingle marks single candidate in grids where c is a value (12x12, 16x16).
+	candidateSingle string = "~"
+)
+
+//Errors for candidate grid layout.
+var (
+	ErrParseCandidatesNoNineCells error = errors.New("Row has not 9 cells")
+	ErrParseCandidatesInvalidCell error = errors.New("Invalid cell, allowed value 5, candidates 237, c5 (~5 in 12x12 and 16x16) or . for no candidate")
+)
+
+//CandidateVisual returns visual representation of the game with candidates:
+// filled cell is written as its value, empty cell as its candidates (. when cell
+// has no candidate, c5 when cell has only one candidate, so it is not confused
+// with filled cell, ~5 in grids where c is a value). Columns are aligned, boxes
+// are separated by | and -.
+func (g *Game) CandidateVisual() string {
+	// ids of empty cells are always valid, Candidates can not fail.
+	candidates, _ := g.Candidates()
+	geometry := g.Geometry()
+	size := int(geometry.Size)
+	tokens := make([][]string, size)
+	widths := make([]int, size)
+	for r := 1; r <= size; r++ {
+		tokens[r-1] = make([]string, size)
+		for c := 1; c <= size; c++ {
+			token := g.findCellValue(uint8(r), uint8(c))
+			if token == EmptyCellTextValue {
+				values := candidates[geometry.CellID(uint8(r), uint8(c))]
+				if len(values) == 1 {
+					token = geometry.singleCandidatePrefix() + geometry.valuesText(values)
+				} else if len(values) > 1 {
+					token = geometry.valuesText(values)
+				}
+			}
+			tokens[r-1][c-1] = token
+			if len(token) > widths[c-1] {
+				widths[c-1] = len(token)
+			}
+		}
+	}
+	boxColumns := int(geometry.BoxColumns)
+	var lines []string
+	var separator, border string
+	for r, rowTokens := range tokens {
+		boxes := make([]string, size/boxColumns)
+		for b := range boxes {
+			padded := make([]string, boxColumns)
+			for i := range padded {
+				c := b*boxColumns + i
+				padded[i] = rowTokens[c] + strings.Repeat(" ", widths[c]-len(rowTokens[c]))
+			}
+			boxes[b] = " " + strings.Join(padded, candidateCellSpace) + " "
+		}
+		if r == 0 {
+			dashes := make([]string, len(boxes))
+			for b, box := range boxes {
+				dashes[b] = strings.Repeat(candidateLine, len(box))
+			}
+			separator = candidateBoxSep + strings.Join(dashes, candidateCrossing) + candidateBoxSep
+			border = candidateCorner + strings.Join(dashes, candidateLine) + candidateCorner
+			lines = append(lines, border)
+		}
+		if r > 0 && r%int(geometry.BoxRows) == 0 {
+			lines = append(lines, separator)
+		}
+		lines = append(lines, candidateBoxSep+strings.Join(boxes, candidateBoxSep)+candidateBoxSep)
+	}
+	lines = append(lines, border)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+//ParseCandidateVisual creates Game object from candidate grid (see CandidateVisual),
+// candidates of every empty cell are set as its pencil marks. Grid size is given
+// by count of cells in the first row (standard 9x9 grid when it is not supported size).
+func ParseCandidateVisual(text string) (*Game, error) {
+	lines := strings.Split(text, "\n")
+	var cells []*Cell
+	var geometry *Geometry
+	marks := make(map[string][]uint8)
+	rowIndex := uint8(0)
+	for index, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if isSkippedLine(line) {
+			continue
+		}
+		tokens, columns := splitTokens(strings.Replace(line, candidateBoxSep, " ", -1))
+		if geometry == nil {
+			geometry = StandardGeometry
+			if g, err := GeometryForSize(len(tokens)); err == nil {
+				geometry = g
+			}
+		}
+		rowIndex++
+		if rowIndex > geometry.Size {
+			return nil, rowsError(index+1, geometry)
+		}
+		if len(tokens) != int(geometry.Size) {
+			return nil, candidateCellsError(index+1, geometry)
+		}
+		for colIdx, token := range tokens {
+			id := geometry.CellID(rowIndex, uint8(colIdx+1))
+			values, ok := parseCandidateToken(token, geometry)
+			if !ok {
+				return nil, newParseError(index+1, columns[colIdx], ErrParseCandidatesInvalidCell)
+			}
+			if len(values) == 1 && !strings.HasPrefix(token, geometry.singleCandidatePrefix()) {
+				c, err := geometry.NewCell(id, values[0])
+				if err != nil {
+					return nil, err
+				}
+				cells = append(cells, c)
+			} else {
+				marks[id] = values
+			}
+		}
+	}
+	if geometry == nil {
+		geometry = StandardGeometry
+	}
+	if rowIndex != geometry.Size {
+		return nil, rowsError(len(lines), geometry)
+	}
+	g, err := NewGame(geometry, cells)
+	if err != nil {
+		return nil, err
+	}
+	for id, values := range marks {
+		if err = g.SetPencilMarks(id, values); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Package private functions.
+
+//candidateCellsError returns error of candidate grid row with wrong count of cells.
+func candidateCellsError(line int, geometry *Geometry) *ParseError {
+	err := newParseError(line, 0, ErrParseCandidatesNoNineCells)
+	if geometry != StandardGeometry {
+		err.Reason = fmt.Sprintf("Row has not %d cells", geometry.Size)
+	}
+	return err
+}
+
+//singleCandidatePrefix returns prefix of single candidate of empty cell in grid of
+// geometry, c when it is not a value of the grid, ~ otherwise.
+func (g *Geometry) singleCandidatePrefix() string {
+	if _, ok := g.ParseValue(rune(sdxCandidatePrefix[0])); ok {
+		return candidateSingle
+	}
+	return sdxCandidatePrefix
+}
+
+//parseCandidateToken returns values of token in grid of geometry, . means no
+// candidate and prefix (see singleCandidatePrefix) means single candidate of empty cell.
+func parseCandidateToken(token string, geometry *Geometry) ([]uint8, bool) {
+	values := []uint8{}
+	if token == EmptyCellTextValue {
+		return values, true
+	}
+	prefix := geometry.singleCandidatePrefix()
+	if strings.HasPrefix(token, prefix) && len(token) != 2 {
+		return nil, false
+	}
+	for _, char := range strings.TrimPrefix(token, prefix) {
+		value, ok := geometry.ParseValue(char)
+		if !ok || value == EmptyCellValue {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
@@ -0,0 +1,175 @@
+package structures
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCandidatesVisualRoundTrip(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.SetPencilMarks("b1", []uint8{3, 7})
+	g.SetPencilMarks("c1", []uint8{})
+	visual := g.CandidateVisual()
+	lines := strings.Split(visual, "\n")
+	if len(lines) != 14 {
+		t.Errorf("CandidateVisual has %d lines, but expected: %d\n%s", len(lines), 14, visual)
+	}
+	if fields := strings.Fields(lines[1]); len(fields) != 13 || fields[2] != "37" || fields[3] != "." {
+		t.Errorf("CandidateVisual first row is %q", lines[1])
+	}
+	if !strings.Contains(lines[3], " c8 ") {
+		t.Errorf("CandidateVisual third row should contain single candidate c8 of i3: %q", lines[3])
+	}
+	if !strings.HasPrefix(lines[4], "|---") || !strings.HasPrefix(lines[0], "*---") {
+		t.Errorf("CandidateVisual separators are %q and %q", lines[0], lines[4])
+	}
+	parsed, err := ParseCandidateVisual(visual)
+	if err != nil {
+		t.Errorf("ParseCandidateVisual should pass, but err: %v", err)
+		return
+	}
+	if parsed.CandidateVisual() != visual {
+		t.Errorf("ParseCandidateVisual restores\n%s, but expected:\n%s", parsed.CandidateVisual(), visual)
+	}
+	expected, _ := g.Candidates()
+	candidates, _ := parsed.Candidates()
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Errorf("ParseCandidateVisual candidates are %v, but expected: %v", candidates, expected)
+	}
+	if DetectFormat([]byte(visual)) != FormatCandidates {
+		t.Errorf("CandidateVisual was detected as %s", DetectFormat([]byte(visual)))
+	}
+}
+
+func TestCandidatesVisualGeometries(t *testing.T) {
+	tests := []struct {
+		line     string
+		geometry *Geometry
+		marked   string
+	}{
+		{mini6Line, Geometry6x6, "a1"},
+		{hex16Line, Geometry16x16, "a1"},
+	}
+	for _, test := range tests {
+		g, err := ParseLine(test.line)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+			continue
+		}
+		g.SetPencilMarks(test.marked, []uint8{2, test.geometry.Size})
+		visual := g.CandidateVisual()
+		if DetectFormat([]byte(visual)) != FormatCandidates {
+			t.Errorf("%s CandidateVisual was detected as %s", test.geometry, DetectFormat([]byte(visual)))
+		}
+		parsed, err := ParseCandidateVisual(visual)
+		if err != nil {
+			t.Errorf("ParseCandidateVisual of %s grid should pass, but err: %v", test.geometry, err)
+			continue
+		}
+		if parsed.Geometry() != test.geometry || parsed.Line() != test.line {
+			t.Errorf("ParseCandidateVisual returns %s game %s, but expected: %s %s", parsed.Geometry(),
+				parsed.Line(), test.geometry, test.line)
+		}
+		expected, _ := g.Candidates()
+		candidates, _ := parsed.Candidates()
+		if !reflect.DeepEqual(candidates, expected) {
+			t.Errorf("ParseCandidateVisual candidates of %s grid are %v, but expected: %v", test.geometry,
+				candidates, expected)
+		}
+	}
+	// boxes of filled 16x16 grid look like cells with candidates.
+	filled := strings.Repeat("1234|5678|9ABC|DEFG\n", 16)
+	if DetectFormat([]byte(filled)) != FormatGrid {
+		t.Errorf("Filled 16x16 grid was detected as %s", DetectFormat([]byte(filled)))
+	}
+	_, err := ParseCandidateVisual("| 1 2 3 | 4 5 6 |\n| 1 2 | 3 |\n")
+	if !errors.Is(err, ErrParseCandidatesNoNineCells) || !strings.Contains(err.Error(), "Row has not 6 cells") {
+		t.Errorf("ParseCandidateVisual should return error of 6 cells, but returned: %v", err)
+	}
+}
+
+func TestCandidatesVisualLetterValues(t *testing.T) {
+	g, _ := ParseLine(hex16Line)
+	g.SetPencilMarks("a1", []uint8{12})
+	visual := g.CandidateVisual()
+	if !strings.HasPrefix(strings.Split(visual, "\n")[1], "| ~C ") {
+		t.Errorf("CandidateVisual should write single candidate C of a1 as ~C: %q", strings.Split(visual, "\n")[1])
+	}
+	// c is value 12 in 16x16 grid, not prefix of single candidate.
+	lines := strings.Split(visual, "\n")
+	lines[4] = strings.Replace(lines[4], " C ", " c ", 1)
+	lines[1] = strings.Replace(lines[1], "~C", "cA", 1)
+	parsed, err := ParseCandidateVisual(strings.Join(lines, "\n"))
+	if err != nil {
+		t.Errorf("ParseCandidateVisual with letter c should pass, but err: %v", err)
+		return
+	}
+	if parsed.Line() != hex16Line {
+		t.Errorf("ParseCandidateVisual returns game %s, but expected: %s", parsed.Line(), hex16Line)
+	}
+	if candidates, _ := parsed.Candidates(); !reflect.DeepEqual(candidates["a1"], []uint8{10, 12}) {
+		t.Errorf("ParseCandidateVisual candidates of a1 are %v, but expected: [10 12]", candidates["a1"])
+	}
+	if parsed, _ = ParseCandidateVisual(visual); parsed == nil || parsed.CandidateVisual() != visual {
+		t.Errorf("ParseCandidateVisual should restore single candidate ~C of a1")
+	}
+}
+
+func TestCandidatesVisualErrors(t *testing.T) {
+	g, _ := NewGameFromString(game1)
+	lines := strings.Split(g.CandidateVisual(), "\n")
+	withRow := func(row string) string {
+		return strings.Join([]string{lines[0], lines[1], row}, "\n")
+	}
+	tests := []struct {
+		input          string
+		expectedErr    error
+		expectedLine   int
+		expectedColumn int
+	}{
+		{strings.Join(lines[:5], "\n"), ErrParseCellsNoNineRows, 5, 0},
+		{withRow(strings.Replace(lines[2], "5", "x", 1)), ErrParseCandidatesInvalidCell, 3,
+			strings.Index(lines[2], "5") + 1},
+		{withRow(strings.Replace(lines[3], "c8", "c89", 1)), ErrParseCandidatesInvalidCell, 3,
+			strings.Index(lines[3], "c8") + 1},
+		{withRow("| 1 2 3 |"), ErrParseCandidatesNoNineCells, 3, 0},
+	}
+	for _, test := range tests {
+		_, err := ParseCandidateVisual(test.input)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, test.expectedErr) {
+			t.Errorf("ParseCandidateVisual should return %v, but returned: %v", test.expectedErr, err)
+			continue
+		}
+		if parseErr.Line != test.expectedLine || parseErr.Column != test.expectedColumn {
+			t.Errorf("ParseError position is %d:%d, but expected: %d:%d", parseErr.Line,
+				parseErr.Column, test.expectedLine, test.expectedColumn)
+		}
+	}
+}
+
+func TestCandidatesJSONPencilMarks(t *testing.T) {
+	g, _ := NewGameFromString(game1)
+	g.SetPencilMarks("b1", []uint8{3, 7})
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Game should be marshaled, but err: %v", err)
+	}
+	var decoded Game
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+	}
+	marks, ok := decoded.PencilMarks("b1")
+	if !ok || !reflect.DeepEqual(marks, []uint8{3, 7}) {
+		t.Errorf("Decoded pencil marks of b1 are %v, but expected: %v", marks, []uint8{3, 7})
+	}
+	if _, ok = decoded.PencilMarks("c1"); ok {
+		t.Error("Decoded game should not have pencil marks for c1.")
+	}
+}
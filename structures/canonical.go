@@ -0,0 +1,350 @@
+package structures
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+)
+
+//Errors for grid transformations.
+var (
+	ErrInvalidTransform error = errors.New("Transform does not keep rows in bands, columns in stacks or digits distinct")
+)
+
+//Transform represents validity-preserving transformation of the grid. Grid is
+// transposed first (when Transpose is true), then row r of the result is row
+// Rows[r] and column c is column Columns[c] of the (transposed) grid, finally
+// every digit d is replaced by Digits[d]. Rows and columns are indexed from 0,
+// Digits[0] is always 0 (empty cell).
+type Transform struct {
+	Transpose bool
+	Rows      [9]uint8
+	Columns   [9]uint8
+	Digits    [10]uint8
+}
+
+//IdentityTransform returns transform which does not change the grid.
+func IdentityTransform() *Transform {
+	t := Transform{}
+	for idx := uint8(0); idx < 9; idx++ {
+		t.Rows[idx] = idx
+		t.Columns[idx] = idx
+		t.Digits[idx+1] = idx + 1
+	}
+	return &t
+}
+
+//Valid returns if transform keeps rows in bands, columns in stacks and maps
+// digits one to one.
+func (t *Transform) Valid() bool {
+	if t.Digits[0] != 0 || !isBandPermutation(t.Rows) || !isBandPermutation(t.Columns) {
+		return false
+	}
+	used := [10]bool{true}
+	for _, d := range t.Digits[1:] {
+		if d > 9 || used[d] {
+			return false
+		}
+		used[d] = true
+	}
+	return true
+}
+
+//source returns 0-based row and column of the original grid which is moved to
+// row r and column c.
+func (t *Transform) source(r uint8, c uint8) (uint8, uint8) {
+	if t.Transpose {
+		return t.Columns[c], t.Rows[r]
+	}
+	return t.Rows[r], t.Columns[c]
+}
+
+//Apply method returns transformed copy of the game, cells keep their origin
+// (given or solution), pencil marks, solution steps and cages are transformed
+// too. Only standard 9x9 game can be transformed, digits of game with cages
+// can not be relabeled and units of game variant must be moved onto units of
+// the game (ex. by rotation).
+func (t *Transform) Apply(g *Game) (*Game, error) {
+	if !t.Valid() {
+		return nil, ErrInvalidTransform
+	}
+	if !g.Geometry().same(StandardGeometry) {
+		return nil, ErrStandardOnly
+	}
+	if len(g.cages) > 0 && t.Digits != IdentityTransform().Digits {
+		return nil, ErrCageDigits
+	}
+	ids := make(map[string]string, LineLength)
+	for r := uint8(0); r < 9; r++ {
+		for c := uint8(0); c < 9; c++ {
+			sr, sc := t.source(r, c)
+			ids[cellID(sr, sc)] = cellID(r, c)
+		}
+	}
+	if g.variant != VariantClassic && !keepsUnits(g.Units(), ids) {
+		return nil, ErrVariantTransform
+	}
+	cells := make([]*Cell, 0, len(g.cells))
+	for id, c := range g.cells {
+		cell, err := createCell(ids[id], t.Digits[c.Value()], c.SolutionCell())
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	result, err := NewGameFromCells(cells)
+	if err != nil {
+		return nil, err
+	}
+	result.variant = g.variant
+	result.solutionSteps = nil
+	for _, id := range g.solutionSteps {
+		result.solutionSteps = append(result.solutionSteps, ids[id])
+	}
+	for _, c := range g.cages {
+		cage := Cage{Sum: c.Sum, Cells: make([]string, len(c.Cells))}
+		for idx, id := range c.Cells {
+			cage.Cells[idx] = ids[id]
+		}
+		if err = result.AddCage(&cage); err != nil {
+			return nil, err
+		}
+	}
+	for id, marks := range g.pencilMarks {
+		values := make([]uint8, 0, len(marks))
+		for _, value := range marks {
+			values = append(values, t.Digits[value])
+		}
+		if err = result.SetPencilMarks(ids[id], values); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+//Canonicalize returns canonical form of the game and transform which turns the
+// game into it. Canonical form is the lexicographically minimal single line
+// (values of all cells, . is less than digits) of all games made by digit
+// relabeling, permutations of rows within bands and columns within stacks,
+// permutations of bands and stacks and transposition. Equivalent games have
+// the same canonical form. Game of other than standard 9x9 grid is returned in
+// single line format and game with cages or variant in killer format, both
+// with nil transform.
+func Canonicalize(g *Game) (string, *Transform) {
+	if !g.Geometry().same(StandardGeometry) {
+		return g.Line(), nil
+	}
+	if len(g.cages) > 0 || g.variant != VariantClassic {
+		return g.killerText(), nil
+	}
+	var values [LineLength]uint8
+	for _, c := range g.cells {
+		values[int(c.Row()-1)*9+int(c.Column()-1)] = c.Value()
+	}
+	cz := canonicalizer{}
+	for idx := range cz.best {
+		cz.best[idx] = 0xff
+	}
+	permutations := bandPermutations()
+	// the first row is chosen over all transpositions, column permutations and
+	// rows, only choices giving minimal first row are searched further.
+	type start struct {
+		transpose bool
+		columns   [9]uint8
+		row       uint8
+	}
+	var starts []start
+	first := []uint8{0xff}
+	for _, transpose := range []bool{false, true} {
+		for _, columns := range permutations {
+			t := Transform{Transpose: transpose, Columns: columns}
+			for r := uint8(0); r < 9; r++ {
+				t.Rows[0] = r
+				var row [9]uint8
+				var digits [10]uint8
+				next := uint8(1)
+				for c := uint8(0); c < 9; c++ {
+					sr, sc := t.source(0, c)
+					row[c], next = relabel(values[sr*9+sc], &digits, next)
+				}
+				switch bytes.Compare(row[:], first) {
+				case -1:
+					first = append([]uint8{}, row[:]...)
+					starts = starts[:0]
+					fallthrough
+				case 0:
+					starts = append(starts, start{transpose, columns, r})
+				}
+			}
+		}
+	}
+	for _, s := range starts {
+		t := Transform{Transpose: s.transpose, Columns: s.columns}
+		for r := uint8(0); r < 9; r++ {
+			t.Rows[0] = r
+			for c := uint8(0); c < 9; c++ {
+				sr, sc := t.source(0, c)
+				cz.grid[r*9+c] = values[sr*9+sc]
+			}
+		}
+		var digits [10]uint8
+		next := uint8(1)
+		for c := uint8(0); c < 9; c++ {
+			cz.current[c], next = relabel(cz.grid[s.row*9+c], &digits, next)
+		}
+		t.Rows[0] = s.row
+		cz.search(1, &t, digits, next)
+	}
+	for d := uint8(1); d < 10; d++ {
+		if cz.transform.Digits[d] == 0 {
+			cz.transform.Digits[d] = cz.next
+			cz.next++
+		}
+	}
+	line := make([]byte, LineLength)
+	for idx, value := range cz.best {
+		line[idx] = EmptyCellTextValue[0]
+		if value != EmptyCellValue {
+			line[idx] = '0' + value
+		}
+	}
+	return string(line), &cz.transform
+}
+
+//AreEquivalent returns if games differ only by transformations used by
+// Canonicalize.
+func AreEquivalent(a *Game, b *Game) bool {
+	canonicalA, _ := Canonicalize(a)
+	canonicalB, _ := Canonicalize(b)
+	return canonicalA == canonicalB
+}
+
+//canonicalizer searches row permutations of grid with fixed transposition and
+// column permutation, best is minimal grid found so far.
+type canonicalizer struct {
+	grid      [LineLength]uint8
+	current   [LineLength]uint8
+	best      [LineLength]uint8
+	transform Transform
+	next      uint8
+}
+
+//search method chooses row of the slot, rows giving greater prefix than the
+// best grid are skipped.
+func (cz *canonicalizer) search(slot uint8, t *Transform, digits [10]uint8, next uint8) {
+	if slot == 9 {
+		if bytes.Compare(cz.current[:], cz.best[:]) < 0 {
+			cz.best = cz.current
+			cz.transform = *t
+			cz.transform.Digits = digits
+			cz.next = next
+		}
+		return
+	}
+	for r := uint8(0); r < 9; r++ {
+		if !cz.allowed(slot, t, r) {
+			continue
+		}
+		rowDigits, rowNext := digits, next
+		for c := uint8(0); c < 9; c++ {
+			cz.current[slot*9+c], rowNext = relabel(cz.grid[r*9+c], &rowDigits, rowNext)
+		}
+		end := (slot + 1) * 9
+		if bytes.Compare(cz.current[:end], cz.best[:end]) > 0 {
+			continue
+		}
+		t.Rows[slot] = r
+		cz.search(slot+1, t, rowDigits, rowNext)
+	}
+}
+
+//allowed method returns if row r can be placed into slot - it is not used yet,
+// the first slot of band takes row of unused band, other slots rows of band of
+// the previous slot.
+func (cz *canonicalizer) allowed(slot uint8, t *Transform, r uint8) bool {
+	for _, used := range t.Rows[:slot] {
+		if used == r || (slot%3 == 0 && used/3 == r/3) {
+			return false
+		}
+	}
+	return slot%3 == 0 || t.Rows[slot-1]/3 == r/3
+}
+
+//relabel returns new label of value, unlabeled digit gets label next.
+func relabel(value uint8, digits *[10]uint8, next uint8) (uint8, uint8) {
+	if value == EmptyCellValue {
+		return EmptyCellValue, next
+	}
+	if digits[value] == 0 {
+		digits[value] = next
+		next++
+	}
+	return digits[value], next
+}
+
+//bandPermutations returns all 1296 permutations of 9 rows (or columns) which
+// keep rows in bands.
+func bandPermutations() [][9]uint8 {
+	orders := [][3]uint8{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	var result [][9]uint8
+	for _, bands := range orders {
+		for _, first := range orders {
+			for _, second := range orders {
+				for _, third := range orders {
+					var p [9]uint8
+					for idx, inner := range [][3]uint8{first, second, third} {
+						for j := 0; j < 3; j++ {
+							p[idx*3+j] = bands[idx]*3 + inner[j]
+						}
+					}
+					result = append(result, p)
+				}
+			}
+		}
+	}
+	return result
+}
+
+//isBandPermutation returns if p is permutation of 0 - 8 which keeps rows in bands.
+func isBandPermutation(p [9]uint8) bool {
+	used := [9]bool{}
+	for idx, value := range p {
+		if value > 8 || used[value] || value/3 != p[idx/3*3]/3 {
+			return false
+		}
+		used[value] = true
+	}
+	return true
+}
+
+//cellID returns id of cell in 0-based row and column.
+func cellID(r uint8, c uint8) string {
+	return string([]byte{'a' + c, '1' + r})
+}
+
+//keepsUnits returns if cells of every unit are moved by ids onto cells of some
+// unit (rows can be moved onto columns).
+func keepsUnits(units []Unit, ids map[string]string) bool {
+	keys := make(map[string]bool, len(units))
+	for _, u := range units {
+		keys[unitKey(u.Cells)] = true
+	}
+	for _, u := range units {
+		moved := make([]string, len(u.Cells))
+		for idx, id := range u.Cells {
+			moved[idx] = ids[id]
+		}
+		if !keys[unitKey(moved)] {
+			return false
+		}
+	}
+	return true
+}
+
+//unitKey returns key of unit cells which does not depend on their order.
+func unitKey(cells []string) string {
+	sorted := append([]string{}, cells...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
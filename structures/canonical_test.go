@@ -0,0 +1,118 @@
+package structures
+
+import (
+	"testing"
+)
+
+const (
+	easyLine      string = "..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1.."
+	easyCanonical string = ".....1..2.13...45.4.657.........35.1.81.4.27.25.16..8.....59..454.7..39.7.982.6.."
+)
+
+//testTransform transposes the grid, swaps the first two rows, bands 2 and 3
+// and digits 1 and 2.
+func testTransform() *Transform {
+	t := IdentityTransform()
+	t.Transpose = true
+	t.Rows = [9]uint8{1, 0, 2, 6, 7, 8, 3, 4, 5}
+	t.Digits[1], t.Digits[2] = 2, 1
+	return t
+}
+
+func TestTransformApply(t *testing.T) {
+	g, err := ParseLine(easyLine)
+	if err != nil {
+		t.Errorf("Game should be parsed, but err: %v", err)
+		return
+	}
+	g.AddCell(createSolutionCell("a1", 8))
+	g.SetPencilMarks("b1", []uint8{1, 2})
+	transformed, err := testTransform().Apply(g)
+	if err != nil {
+		t.Errorf("Game should be transformed, but err: %v", err)
+		return
+	}
+	// a1 is moved to a2 by transposition and swap of rows, c1 to a3, e1 to a8.
+	tests := []struct {
+		id       string
+		value    uint8
+		solution bool
+	}{
+		{"a2", 8, true},
+		{"a3", 5, false},
+		{"a8", 2, false},
+		{"d1", 7, false},
+	}
+	for _, test := range tests {
+		c, err := transformed.Cell(test.id)
+		if err != nil || c.Value() != test.value || c.SolutionCell() != test.solution {
+			t.Errorf("Cell %s should have value %d (solution %v), but is: %v (err: %v)", test.id, test.value,
+				test.solution, c, err)
+		}
+	}
+	if marks, ok := transformed.PencilMarks("a1"); !ok || len(marks) != 2 {
+		t.Errorf("Pencil marks of b1 should be moved to a1, but are: %v", marks)
+	}
+	if len(transformed.solutionSteps) != 1 || transformed.solutionSteps[0] != "a2" {
+		t.Errorf("Solution steps should be transformed, but are: %v", transformed.solutionSteps)
+	}
+	if g.Line() == transformed.Line() {
+		t.Errorf("Game should not be changed by transformation, but is: %s", g.Line())
+	}
+}
+
+func TestTransformValid(t *testing.T) {
+	tests := []struct {
+		change func(t *Transform)
+		valid  bool
+	}{
+		{func(t *Transform) {}, true},
+		{func(t *Transform) { t.Columns = [9]uint8{8, 7, 6, 5, 4, 3, 2, 1, 0} }, true},
+		{func(t *Transform) { t.Rows[2], t.Rows[3] = 3, 2 }, false},
+		{func(t *Transform) { t.Columns[0] = 1 }, false},
+		{func(t *Transform) { t.Digits[1] = 2 }, false},
+		{func(t *Transform) { t.Digits[0] = 1 }, false},
+	}
+	g, _ := ParseLine(easyLine)
+	for idx, test := range tests {
+		transform := IdentityTransform()
+		test.change(transform)
+		if transform.Valid() != test.valid {
+			t.Errorf("Transform %d should be valid: %v, but is: %v", idx, test.valid, transform.Valid())
+		}
+		if _, err := transform.Apply(g); (err == nil) != test.valid {
+			t.Errorf("Transform %d should be applied: %v, but err: %v", idx, test.valid, err)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	g, _ := ParseLine(easyLine)
+	transformed, _ := testTransform().Apply(g)
+	for _, game := range []*Game{g, transformed} {
+		canonical, transform := Canonicalize(game)
+		if canonical != easyCanonical {
+			t.Errorf("Canonical form of %s is %s, but expected: %s", game.Line(), canonical, easyCanonical)
+		}
+		result, err := transform.Apply(game)
+		if err != nil || result.Line() != canonical {
+			t.Errorf("Transform should turn game into canonical form, but result is: %v (err: %v)", result, err)
+		}
+	}
+	canonicalGame, _ := ParseLine(easyCanonical)
+	if canonical, _ := Canonicalize(canonicalGame); canonical != easyCanonical {
+		t.Errorf("Canonical form of canonical game should not change, but is: %s", canonical)
+	}
+}
+
+func TestAreEquivalent(t *testing.T) {
+	g, _ := ParseLine(easyLine)
+	transformed, _ := testTransform().Apply(g)
+	other, _ := ParseLine(easyLine[:2] + "." + easyLine[3:])
+	if !AreEquivalent(g, transformed) {
+		t.Errorf("Transformed game should be equivalent to %s, but is not: %s", g.Line(), transformed.Line())
+	}
+	if AreEquivalent(g, other) {
+		t.Errorf("Game with less givens should not be equivalent to %s, but is: %s", g.Line(), other.Line())
+	}
+}
@@ -6,21 +6,10 @@ import (
 	"strings"
 )
 
-var convertTable = map[string]uint8{
-	"a": 1,
-	"b": 2,
-	"c": 3,
-	"d": 4,
-	"e": 5,
-	"f": 6,
-	"g": 7,
-	"h": 8,
-	"i": 9}
-
 //Error messages for cell object.
 var (
-	ErrInvalidCellIDFormatMsg = "Invalid format of cell id: %s. Allowed a1-i9."
-	ErrInvalidValueMsg        = "Value should be number 0-9. Given number: %d"
+	ErrInvalidCellIDFormatMsg = "Invalid format of cell id: %s. Allowed a1-i9 (a1-p16 in 16x16 grid)."
+	ErrInvalidValueMsg        = "Value should be number 0-9 (0-16 in 16x16 grid). Given number: %d"
 )
 
 //Empty cell representations.
@@ -32,50 +21,51 @@ const (
 // Package private functions.
 
 func createCell(id string, value uint8, solution bool) (*Cell, error) {
-	id, err := validateIDFormat(id)
-	if err != nil {
-		return nil, err
-	}
-	_, err = validateColumn(id)
+	return StandardGeometry.createCell(id, value, solution)
+}
+
+func (g *Geometry) createCell(id string, value uint8, solution bool) (*Cell, error) {
+	id, err := g.validateID(id)
 	if err != nil {
 		return nil, err
 	}
-	_, err = validateRow(id)
-	if err != nil {
+	c := Cell{Id: id, solutionCell: solution, geometry: g}
+	if err = c.SetValue(value); err != nil {
 		return nil, err
 	}
-	c := Cell{Id: id, solutionCell: solution}
-	c.SetValue(value)
 	return &c, nil
 }
 
-func validateIDFormat(id string) (string, error) {
+//validateID returns trimmed id when it is id of cell of the grid - column
+// letter and row number without leading zero.
+func (g *Geometry) validateID(id string) (string, error) {
 	id = strings.TrimSpace(id)
-	if len(id) != 2 {
-		return "", fmt.Errorf(ErrInvalidCellIDFormatMsg, id)
+	if len(id) < 2 || len(id) > 3 || id[1] == '0' {
+		return "", &CellIDError{ID: id}
+	}
+	column, row := idColumn(id), idRow(id)
+	if column < 1 || column > g.Size || row < 1 || row > g.Size {
+		return "", &CellIDError{ID: id}
 	}
 	return id, nil
 }
 
-func validateColumn(id string) (uint8, error) {
-	idPart := strings.ToLower(string(id[0]))
-	column, ok := convertTable[idPart]
-	if !ok {
-		return 0, fmt.Errorf(ErrInvalidCellIDFormatMsg, id)
+//idColumn returns column index of cell id, 0 when column is not letter.
+func idColumn(id string) uint8 {
+	letter := strings.ToLower(string(id[0]))[0]
+	if letter < 'a' || letter > 'z' {
+		return 0
 	}
-	return column, nil
+	return letter - 'a' + 1
 }
 
-func validateRow(id string) (uint8, error) {
-	idPart := string(id[1])
-	row, err := strconv.ParseUint(idPart, 10, 8)
+//idRow returns row index of cell id, 0 when row is not number.
+func idRow(id string) uint8 {
+	row, err := strconv.ParseUint(id[1:], 10, 8)
 	if err != nil {
-		return 0, fmt.Errorf(ErrInvalidCellIDFormatMsg, id)
-	}
-	if row < 1 || row > 9 {
-		return 0, fmt.Errorf(ErrInvalidCellIDFormatMsg, id)
+		return 0
 	}
-	return uint8(row), nil
+	return uint8(row)
 }
 
 // Cell struct represents one cell in sudoku game.
@@ -83,6 +73,7 @@ type Cell struct {
 	Id           string
 	value        *uint8
 	solutionCell bool
+	geometry     *Geometry
 }
 
 // Cell struct constructors.
@@ -94,17 +85,27 @@ func NewCell(id string, value uint8) (*Cell, error) {
 
 // NewCellFromString creates Cell object from string representation.
 func NewCellFromString(text string) (*Cell, error) {
-	// format a1=5 o resp. a1=5 x
+	// format a1=5 o resp. a1=5 x, mark is optional and o is default.
+	text = strings.TrimSpace(text)
+	if len(text) < 4 || text[2] != '=' {
+		return nil, newParseError(1, 1, ErrParseInvalidCell)
+	}
 	id := string(text[:2])
-	tmp, err := strconv.ParseUint(string(text[3]), 10, 8)
-	if err != nil {
-		return nil, err
+	value, ok := parseCellValue(rune(text[3]))
+	if !ok {
+		return nil, newParseError(1, 4, ErrParseInvalidCell)
+	}
+	mark := strings.TrimSpace(text[4:])
+	if len(text) > 4 && mark == string(text[4:]) {
+		return nil, newParseError(1, 5, ErrParseInvalidCell)
 	}
-	value := uint8(tmp)
-	if string(text[5]) == "x" {
+	switch mark {
+	case "x":
 		return NewSolutionCell(id, value)
+	case "o", "":
+		return NewCell(id, value)
 	}
-	return NewCell(id, value)
+	return nil, newParseError(1, len(text)-len(mark)+1, ErrParseInvalidCell)
 }
 
 // NewSolutionCell creates Cell object with solutionCell=true.
@@ -122,19 +123,27 @@ func (c *Cell) Value() uint8 {
 	return *c.value
 }
 
-//TextValue returns cell value in text format.
+//TextValue returns cell value in text format, values greater than 9 are letters.
 func (c *Cell) TextValue() string {
 	value := c.Value()
 	if value == EmptyCellValue {
 		return ""
 	}
-	return string(value)
+	return c.Geometry().ValueText(value)
+}
+
+//Geometry returns geometry of the grid in which cell lies.
+func (c *Cell) Geometry() *Geometry {
+	if c.geometry == nil {
+		return StandardGeometry
+	}
+	return c.geometry
 }
 
 //SetValue can validate and set cell value.
 func (c *Cell) SetValue(value uint8) error {
-	if value < 0 || value > 9 {
-		return fmt.Errorf(ErrInvalidValueMsg, value)
+	if value > c.Geometry().Size {
+		return &ValueError{Value: value}
 	}
 	if c.value == nil {
 		var v uint8
@@ -148,8 +157,7 @@ func (c *Cell) SetValue(value uint8) error {
 
 //Row returns cell row index.
 func (c *Cell) Row() uint8 {
-	row, _ := validateRow(c.Id)
-	return row
+	return idRow(c.Id)
 }
 
 //TextColumn returns cell column index in text format ex.a,b,c...
@@ -160,8 +168,7 @@ func (c *Cell) TextColumn() string {
 
 //Column returns cell column index.
 func (c *Cell) Column() uint8 {
-	column, _ := validateColumn(c.Id)
-	return column
+	return idColumn(c.Id)
 }
 
 //SolutionCell returns if cell is solution cell or not.
@@ -171,40 +178,7 @@ func (c *Cell) SolutionCell() bool {
 
 //Square returns cell square index.
 func (c *Cell) Square() uint8 {
-	r := c.Row()
-	s := c.Column()
-	if r >= 1 && r <= 3 {
-		if s >= 1 && s <= 3 {
-			return 1
-		}
-		if s >= 4 && s <= 6 {
-			return 2
-		}
-		if s >= 7 && s <= 9 {
-			return 3
-		}
-	} else if r >= 4 && r <= 6 {
-		if s >= 1 && s <= 3 {
-			return 4
-		}
-		if s >= 4 && s <= 6 {
-			return 5
-		}
-		if s >= 7 && s <= 9 {
-			return 6
-		}
-	} else {
-		if s >= 1 && s <= 3 {
-			return 7
-		}
-		if s >= 4 && s <= 6 {
-			return 8
-		}
-		if s >= 7 && s <= 9 {
-			return 9
-		}
-	}
-	return 0
+	return c.Geometry().Box(c.Row(), c.Column())
 }
 
 //String returns string representation of cell.
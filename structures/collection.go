@@ -0,0 +1,170 @@
+package structures
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+//Errors for collection files.
+var (
+	ErrCollectionInvalidField error = errors.New("Puzzle field must not contain tab, new line or #")
+)
+
+//Collection file format constants.
+const (
+	CollectionCommentMark string = "#"
+	CollectionFieldSep    string = "\t"
+	maxCollectionLineSize int    = 1024 * 1024
+)
+
+//Puzzle represents one game from collection file together with its metadata.
+type Puzzle struct {
+	Game   *Game
+	Name   string
+	Rating string
+	Source string
+	Extra  []string
+	Line   int
+}
+
+//CollectionReader reads puzzles from collection file one by one, it never keeps
+// more than one line of the file in memory.
+//
+// Every puzzle is on its own line: 81 characters of game followed by optional
+// fields name, rating, source and any extra fields. Fields are separated by tab,
+// when line contains no tab, by whitespace. Text after # is comment, blank lines
+// are skipped.
+type CollectionReader struct {
+	scanner *bufio.Scanner
+	puzzle  *Puzzle
+	err     error
+	line    int
+}
+
+//NewCollectionReader creates CollectionReader object reading from r.
+func NewCollectionReader(r io.Reader) *CollectionReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxCollectionLineSize)
+	return &CollectionReader{scanner: scanner}
+}
+
+//Next reads next puzzle, it returns false at the end of file or when error occurs.
+func (r *CollectionReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.scanner.Scan() {
+		r.line++
+		puzzle, err := parsePuzzleLine(r.scanner.Text(), r.line)
+		if err != nil {
+			r.err = err
+			r.puzzle = nil
+			return false
+		}
+		if puzzle != nil {
+			r.puzzle = puzzle
+			return true
+		}
+	}
+	r.err = r.scanner.Err()
+	r.puzzle = nil
+	return false
+}
+
+//Puzzle returns puzzle read by last call of Next.
+func (r *CollectionReader) Puzzle() *Puzzle {
+	return r.puzzle
+}
+
+//Err returns first error which occurred during reading.
+func (r *CollectionReader) Err() error {
+	return r.err
+}
+
+//CollectionWriter writes puzzles in collection file format.
+type CollectionWriter struct {
+	w *bufio.Writer
+}
+
+//NewCollectionWriter creates CollectionWriter object writing to w.
+func NewCollectionWriter(w io.Writer) *CollectionWriter {
+	return &CollectionWriter{w: bufio.NewWriter(w)}
+}
+
+//WriteComment writes comment line.
+func (w *CollectionWriter) WriteComment(comment string) error {
+	for _, line := range strings.Split(comment, "\n") {
+		_, err := w.w.WriteString(CollectionCommentMark + " " + line + "\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Write writes one puzzle, trailing empty fields are omitted.
+func (w *CollectionWriter) Write(p *Puzzle) error {
+	fields := append([]string{p.Game.Line(), p.Name, p.Rating, p.Source}, p.Extra...)
+	last := len(fields)
+	for last > 1 && fields[last-1] == "" {
+		last--
+	}
+	for idx := 1; idx < last; idx++ {
+		if strings.ContainsAny(fields[idx], CollectionFieldSep+"\n"+CollectionCommentMark) {
+			return ErrCollectionInvalidField
+		}
+	}
+	_, err := w.w.WriteString(strings.Join(fields[:last], CollectionFieldSep) + "\n")
+	return err
+}
+
+//Flush writes buffered data to underlying writer.
+func (w *CollectionWriter) Flush() error {
+	return w.w.Flush()
+}
+
+//parsePuzzleLine parses one line of collection file, it returns nil puzzle for
+// blank and comment lines.
+func parsePuzzleLine(line string, lineNumber int) (*Puzzle, error) {
+	if idx := strings.Index(line, CollectionCommentMark); idx >= 0 {
+		line = line[:idx]
+	}
+	line = strings.TrimRight(line, " \t\r")
+	content := strings.TrimLeft(line, " \t")
+	if content == "" {
+		return nil, nil
+	}
+	var fields []string
+	if strings.Contains(content, CollectionFieldSep) {
+		fields = strings.Split(content, CollectionFieldSep)
+		for idx := range fields {
+			fields[idx] = strings.TrimSpace(fields[idx])
+		}
+	} else {
+		fields = strings.Fields(content)
+	}
+	cells, err := parseLineCells(fields[0], lineNumber)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Column += len(line) - len(content)
+		}
+		return nil, err
+	}
+	game, err := NewGame(lineGeometry(fields[0]), cells)
+	if err != nil {
+		return nil, err
+	}
+	p := Puzzle{Game: game, Line: lineNumber}
+	meta := []*string{&p.Name, &p.Rating, &p.Source}
+	for idx, field := range fields[1:] {
+		if idx < len(meta) {
+			*meta[idx] = field
+		} else {
+			p.Extra = append(p.Extra, field)
+		}
+	}
+	return &p, nil
+}
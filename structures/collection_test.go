@@ -0,0 +1,113 @@
+package structures
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const collection1 string = `# test collection
+# line, name, rating, source
+
+` + game1Line + `	first puzzle	2.3	newspaper	weekly
+..5.1.6..39.7...1.4.79...2897.8...4.5..2.6..1.8...1.7326...87.4.5...7.39..8.4.1..   second  1.2 # comment
+` + game1Line + "\r\n"
+
+func TestCollectionReader(t *testing.T) {
+	reader := NewCollectionReader(strings.NewReader(collection1))
+	var puzzles []*Puzzle
+	for reader.Next() {
+		puzzles = append(puzzles, reader.Puzzle())
+	}
+	if reader.Err() != nil {
+		t.Errorf("CollectionReader should read all puzzles, but err: %v", reader.Err())
+	}
+	if len(puzzles) != 3 {
+		t.Errorf("CollectionReader read %d puzzles, but expected: %d", len(puzzles), 3)
+		return
+	}
+	tests := []struct {
+		name   string
+		rating string
+		source string
+		extra  []string
+		line   int
+	}{
+		{"first puzzle", "2.3", "newspaper", []string{"weekly"}, 4},
+		{"second", "1.2", "", nil, 5},
+		{"", "", "", nil, 6},
+	}
+	for idx, test := range tests {
+		p := puzzles[idx]
+		if p.Name != test.name || p.Rating != test.rating || p.Source != test.source ||
+			!reflect.DeepEqual(p.Extra, test.extra) || p.Line != test.line {
+			t.Errorf("Puzzle %d is %+v, but expected: %+v", idx, p, test)
+		}
+	}
+	if puzzles[0].Game.Line() != game1Line {
+		t.Errorf("Puzzle game is %s, but expected: %s", puzzles[0].Game.Line(), game1Line)
+	}
+}
+
+func TestCollectionReaderError(t *testing.T) {
+	input := "# header\n" + game1Line + "\n  " + game1Line[:10] + "x" + game1Line[11:] + "\n" + game1Line
+	reader := NewCollectionReader(strings.NewReader(input))
+	count := 0
+	for reader.Next() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("CollectionReader read %d puzzles before error, but expected: %d", count, 1)
+	}
+	var parseErr *ParseError
+	if !errors.As(reader.Err(), &parseErr) {
+		t.Errorf("CollectionReader should return ParseError, but returned: %v", reader.Err())
+		return
+	}
+	if parseErr.Line != 3 || parseErr.Column != 13 {
+		t.Errorf("ParseError position is %d:%d, but expected: %d:%d", parseErr.Line, parseErr.Column, 3, 13)
+	}
+}
+
+func TestCollectionWriterRoundTrip(t *testing.T) {
+	g, err := ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	var buffer bytes.Buffer
+	writer := NewCollectionWriter(&buffer)
+	writer.WriteComment("weekly pack")
+	written := []*Puzzle{
+		{Game: g, Name: "first puzzle", Rating: "2.3", Source: "newspaper", Extra: []string{"x"}},
+		{Game: g, Name: "second"},
+		{Game: g},
+	}
+	for _, p := range written {
+		if err = writer.Write(p); err != nil {
+			t.Errorf("CollectionWriter should write puzzle, but err: %v", err)
+		}
+	}
+	if err = writer.Write(&Puzzle{Game: g, Name: "bad#name"}); !errors.Is(err, ErrCollectionInvalidField) {
+		t.Errorf("CollectionWriter should return ErrCollectionInvalidField, but returned: %v", err)
+	}
+	writer.Flush()
+	expected := "# weekly pack\n" + game1Line + "\tfirst puzzle\t2.3\tnewspaper\tx\n" +
+		game1Line + "\tsecond\n" + game1Line + "\n"
+	if buffer.String() != expected {
+		t.Errorf("CollectionWriter writes %q, but expected: %q", buffer.String(), expected)
+	}
+	reader := NewCollectionReader(&buffer)
+	idx := 0
+	for reader.Next() {
+		p := reader.Puzzle()
+		if p.Name != written[idx].Name || p.Rating != written[idx].Rating || p.Game.Line() != game1Line {
+			t.Errorf("Puzzle %d is %+v, but expected: %+v", idx, p, written[idx])
+		}
+		idx++
+	}
+	if idx != len(written) {
+		t.Errorf("CollectionReader read %d puzzles, but expected: %d", idx, len(written))
+	}
+}
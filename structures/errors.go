@@ -0,0 +1,79 @@
+package structures
+
+import (
+	"fmt"
+)
+
+//CellIDError is returned when cell id has invalid format.
+type CellIDError struct {
+	ID string
+}
+
+func (e *CellIDError) Error() string {
+	return fmt.Sprintf(ErrInvalidCellIDFormatMsg, e.ID)
+}
+
+//ValueError is returned when cell value is out of allowed range.
+type ValueError struct {
+	Value uint8
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf(ErrInvalidValueMsg, e.Value)
+}
+
+//DuplicateCellError is returned when cell with the same id is already in game.
+type DuplicateCellError struct {
+	ID string
+}
+
+func (e *DuplicateCellError) Error() string {
+	return fmt.Sprintf(ErrDuplicatedCellInGameMsg, e.ID)
+}
+
+//CellNotFoundError is returned when cell with given id is not in game.
+type CellNotFoundError struct {
+	ID string
+}
+
+func (e *CellNotFoundError) Error() string {
+	return fmt.Sprintf(ErrCellWasNotFoundMsg, e.ID)
+}
+
+//CageCellError is returned when cell is added to cage, but it is already in
+// some cage of the game.
+type CageCellError struct {
+	ID string
+}
+
+func (e *CageCellError) Error() string {
+	return fmt.Sprintf(ErrCellInCageMsg, e.ID)
+}
+
+//ParseError is returned when text representation of game or cell can not be parsed.
+// Line and Column are 1-based, zero means position is not known.
+type ParseError struct {
+	Line   int
+	Column int
+	Reason string
+	Err    error
+}
+
+func newParseError(line int, column int, err error) *ParseError {
+	return &ParseError{Line: line, Column: column, Reason: err.Error(), Err: err}
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 && e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Reason)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+	}
+	return e.Reason
+}
+
+//Unwrap returns underlying error of parse error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
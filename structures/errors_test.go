@@ -0,0 +1,78 @@
+package structures
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorsCellID(t *testing.T) {
+	_, err := NewCell("j7", 0)
+	var idErr *CellIDError
+	if !errors.As(err, &idErr) {
+		t.Errorf("NewCell should return CellIDError, but returned: %v", err)
+		return
+	}
+	if idErr.ID != "j7" {
+		t.Errorf("CellIDError has ID=%s, but expected: %s", idErr.ID, "j7")
+	}
+}
+
+func TestErrorsValue(t *testing.T) {
+	c, _ := NewCell("a1", 0)
+	err := c.SetValue(12)
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) {
+		t.Errorf("SetValue should return ValueError, but returned: %v", err)
+		return
+	}
+	if valueErr.Value != 12 {
+		t.Errorf("ValueError has Value=%d, but expected: %d", valueErr.Value, 12)
+	}
+}
+
+func TestErrorsGameCells(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	err = g.AddStringCell("a1=8 o")
+	var duplicateErr *DuplicateCellError
+	if !errors.As(err, &duplicateErr) || duplicateErr.ID != "a1" {
+		t.Errorf("AddStringCell should return DuplicateCellError for a1, but returned: %v", err)
+	}
+	_, err = g.Cell("b1")
+	var notFoundErr *CellNotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.ID != "b1" {
+		t.Errorf("Cell should return CellNotFoundError for b1, but returned: %v", err)
+	}
+}
+
+func TestErrorsParse(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"8..|94.|..5\n...|.5.|2..", 2, 0},
+		{"8..|94.|..5\n...|.5.|2..\n1.9|6.2|...\n5.1|...|..4\n46.|...|.53\n2..|...|8.1\n...|4.9|1.7\n..4|.6.|...\n9..|.x7|..6",
+			9, 6},
+		{"8..|94.|..5\n...|.5.|2..\n1.9|6.2|...\n5.1|...|..4\n46.|...|.53\n2..|...|8.1\n...|4.9|1.7\n..4|.6.|...\n9..|.17|..66",
+			9, 12},
+	}
+	for _, test := range tests {
+		_, err := NewGameFromString(test.input)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("NewGameFromString should return ParseError, but returned: %v", err)
+			continue
+		}
+		if parseErr.Line != test.expectedLine || parseErr.Column != test.expectedColumn {
+			t.Errorf("ParseError position is %d:%d, but expected: %d:%d", parseErr.Line,
+				parseErr.Column, test.expectedLine, test.expectedColumn)
+		}
+	}
+	_, err := NewGameFromString("8..|94.|..5")
+	if !errors.Is(err, ErrParseCellsNoNineRows) {
+		t.Errorf("NewGameFromString should return ErrParseCellsNoNineRows, but returned: %v", err)
+	}
+}
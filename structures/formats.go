@@ -0,0 +1,561 @@
+package structures
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//Format represents text format of the game.
+type Format string
+
+//Supported game formats.
+const (
+	//FormatLine is single line format, 81 characters.
+	FormatLine Format = "line"
+	//FormatGrid is 9 lines format abc|def|ghi, same as GameVisual.
+	FormatGrid Format = "grid"
+	//FormatSS is Simple Sudoku format, grid with borders *---* and |---+---+---|.
+	FormatSS Format = "ss"
+	//FormatSDK is SadMan Sudoku format, #X metadata lines, [Puzzle] and optional [State] grids.
+	FormatSDK Format = "sdk"
+	//FormatSDX is pencil-mark format, 9 lines of 9 cells separated by space.
+	FormatSDX Format = "sdx"
+	//FormatJSON is JSON representation of the game.
+	FormatJSON Format = "json"
+	//FormatCandidates is candidate grid, see Game.CandidateVisual.
+	FormatCandidates Format = "candidates"
+	//FormatKiller is killer sudoku format, givens line, variant line (variant: diagonal)
+	// and one cage per line (15: a1 a2 b1).
+	FormatKiller Format = "killer"
+)
+
+//Sections and metadata of SadMan format.
+const (
+	sdkPuzzleSection   string = "[Puzzle]"
+	sdkStateSection    string = "[State]"
+	sdkMetaMark        string = "#"
+	sdkMetaName        string = "D"
+	sdkMetaRating      string = "L"
+	sdkMetaSource      string = "S"
+	sdxSolutionPrefix  string = "u"
+	sdxCandidatePrefix string = "c"
+)
+
+//Errors for game formats.
+var (
+	ErrUnknownFormat       error = errors.New("Unknown game format")
+	ErrParseSDXNoNineCells error = errors.New("Row has not 9 cells separated by space")
+	ErrParseSDXInvalidCell error = errors.New("Invalid cell, allowed given 5, solved u5 or candidates 237")
+)
+
+//textLine represents one line of input together with its 1-based number.
+type textLine struct {
+	number int
+	text   string
+}
+
+//Formats returns all supported formats.
+func Formats() []Format {
+	return []Format{FormatLine, FormatGrid, FormatSS, FormatSDK, FormatSDX, FormatJSON, FormatCandidates,
+		FormatKiller}
+}
+
+//ParseFormat returns format by its name or file extension (ex. "ss", ".sdk").
+func ParseFormat(name string) (Format, error) {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), ".")
+	for _, format := range Formats() {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	switch name {
+	case "txt", "sudoku":
+		return FormatGrid, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+//DetectFormat guesses format of the game from its text representation.
+func DetectFormat(data []byte) Format {
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "{") {
+		return FormatJSON
+	}
+	hasMeta := false
+	var content []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case line == sdkPuzzleSection || line == sdkStateSection:
+			return FormatSDK
+		case strings.HasPrefix(line, sdkMetaMark):
+			hasMeta = true
+			continue
+		}
+		content = append(content, line)
+	}
+	if len(content) == 0 {
+		return FormatGrid
+	}
+	for _, line := range content {
+		if isCageLine(line) || isVariantLine(line) {
+			return FormatKiller
+		}
+	}
+	if isCollection(content) {
+		return FormatLine
+	}
+	for _, line := range content {
+		if isCandidateRow(line) {
+			return FormatCandidates
+		}
+	}
+	for _, line := range content {
+		fields := strings.Fields(line)
+		if len(fields) != 9 || strings.Contains(line, "|") {
+			continue
+		}
+		for _, field := range fields {
+			if len(field) > 1 {
+				return FormatSDX
+			}
+		}
+	}
+	if strings.HasPrefix(content[0], "*") || strings.HasPrefix(content[0], "|") {
+		return FormatSS
+	}
+	if hasMeta {
+		return FormatSDK
+	}
+	return FormatGrid
+}
+
+//Load reads game from r, format is detected from the content.
+func Load(r io.Reader) (*Game, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadData(data, DetectFormat(data))
+}
+
+//LoadFormat reads game in given format from r.
+func LoadFormat(r io.Reader, format Format) (*Game, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return loadData(data, format)
+}
+
+//Save writes game to w in given format. Simple Sudoku, SadMan and pencil-mark
+// formats support only standard 9x9 game, cages and variant are written only
+// in killer and JSON formats.
+func Save(w io.Writer, g *Game, format Format) error {
+	var err error
+	switch format {
+	case FormatLine:
+		_, err = io.WriteString(w, g.Line()+"\n")
+	case FormatGrid:
+		_, err = io.WriteString(w, g.GameVisual())
+	case FormatSS:
+		err = WriteSS(w, g)
+	case FormatSDK:
+		err = WriteSDK(w, &Puzzle{Game: g})
+	case FormatSDX:
+		err = WriteSDX(w, g)
+	case FormatCandidates:
+		_, err = io.WriteString(w, g.CandidateVisual())
+	case FormatKiller:
+		err = WriteKiller(w, g)
+	case FormatJSON:
+		var data []byte
+		data, err = json.Marshal(g)
+		if err == nil {
+			_, err = w.Write(append(data, '\n'))
+		}
+	default:
+		err = ErrUnknownFormat
+	}
+	return err
+}
+
+//ReadSDK reads game in SadMan format together with its metadata (#D name,
+// #L rating, #S source, other metadata lines are kept in Extra without #).
+// Cells presented only in [State] section are solution cells.
+func ReadSDK(r io.Reader) (*Puzzle, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	p := Puzzle{}
+	var puzzleRows, stateRows []textLine
+	rows := &puzzleRows
+	last := 0
+	for _, line := range lines {
+		text := strings.TrimSpace(line.text)
+		last = line.number
+		switch {
+		case text == "" || isSkippedLine(text):
+			continue
+		case text == sdkPuzzleSection:
+			rows = &puzzleRows
+		case text == sdkStateSection:
+			rows = &stateRows
+		case strings.HasPrefix(text, sdkMetaMark):
+			p.setSDKMeta(strings.TrimPrefix(text, sdkMetaMark))
+		default:
+			*rows = append(*rows, line)
+		}
+	}
+	givens, err := parseGridRows(puzzleRows, last)
+	if err != nil {
+		return nil, err
+	}
+	cells := givens
+	if len(stateRows) > 0 {
+		state, err := parseGridRows(stateRows, last)
+		if err != nil {
+			return nil, err
+		}
+		given := make(map[string]bool)
+		for _, c := range givens {
+			given[c.Id] = true
+		}
+		for _, c := range state {
+			if !given[c.Id] {
+				c.solutionCell = true
+				cells = append(cells, c)
+			}
+		}
+	}
+	p.Game, err = NewGameFromCells(cells)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+//WriteSDK writes game in SadMan format, [State] section is written only when game
+// has solution cells. It returns ErrStandardOnly for game which is not 9x9.
+func WriteSDK(w io.Writer, p *Puzzle) error {
+	if !p.Game.Geometry().same(StandardGeometry) {
+		return ErrStandardOnly
+	}
+	bw := bufio.NewWriter(w)
+	meta := []struct{ mark, value string }{
+		{sdkMetaName, p.Name}, {sdkMetaRating, p.Rating}, {sdkMetaSource, p.Source}}
+	for _, m := range meta {
+		if m.value != "" {
+			bw.WriteString(sdkMetaMark + m.mark + m.value + "\n")
+		}
+	}
+	for _, extra := range p.Extra {
+		bw.WriteString(sdkMetaMark + extra + "\n")
+	}
+	bw.WriteString(sdkPuzzleSection + "\n")
+	writeLineAsRows(bw, p.Game.GivensLine())
+	if p.Game.SolutionCellCount() > 0 {
+		bw.WriteString(sdkStateSection + "\n")
+		writeLineAsRows(bw, p.Game.Line())
+	}
+	return bw.Flush()
+}
+
+//WriteSS writes game in Simple Sudoku format. It returns ErrStandardOnly for game
+// which is not 9x9.
+func WriteSS(w io.Writer, g *Game) error {
+	if !g.Geometry().same(StandardGeometry) {
+		return ErrStandardOnly
+	}
+	bw := bufio.NewWriter(w)
+	border := "*-----------*\n"
+	bw.WriteString(border)
+	line := g.Line()
+	for r := 0; r < 9; r++ {
+		if r == 3 || r == 6 {
+			bw.WriteString("|---+---+---|\n")
+		}
+		row := line[r*9 : r*9+9]
+		bw.WriteString("|" + row[:3] + "|" + row[3:6] + "|" + row[6:] + "|\n")
+	}
+	bw.WriteString(border)
+	return bw.Flush()
+}
+
+//ReadSDX reads game in pencil-mark format. Every row is on its own line, cells are
+// separated by space: given cell is single digit (5), solved cell has prefix u (u5)
+// and empty cell is written as its candidates (237). Empty cell with less than two
+// candidates has prefix c (c5), it is extension of this package.
+func ReadSDX(r io.Reader) (*Game, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var rows []textLine
+	last := 0
+	for _, line := range lines {
+		last = line.number
+		if strings.TrimSpace(line.text) != "" {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) != 9 {
+		if len(rows) > 9 {
+			last = rows[9].number
+		}
+		return nil, newParseError(last, 0, ErrParseCellsNoNineRows)
+	}
+	var cells []*Cell
+	marks := make(map[string][]uint8)
+	for rowIdx, row := range rows {
+		tokens, columns := splitTokens(row.text)
+		if len(tokens) != 9 {
+			return nil, newParseError(row.number, 0, ErrParseSDXNoNineCells)
+		}
+		for colIdx, token := range tokens {
+			id := columnID(uint8(colIdx+1)) + fmt.Sprintf("%d", rowIdx+1)
+			c, candidates, ok := parseSDXToken(id, token)
+			if !ok {
+				return nil, newParseError(row.number, columns[colIdx], ErrParseSDXInvalidCell)
+			}
+			if c != nil {
+				cells = append(cells, c)
+			} else {
+				marks[id] = candidates
+			}
+		}
+	}
+	g, err := NewGameFromCells(cells)
+	if err != nil {
+		return nil, err
+	}
+	for id, candidates := range marks {
+		if err = g.SetPencilMarks(id, candidates); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+//WriteSDX writes game in pencil-mark format, see ReadSDX. It returns ErrStandardOnly
+// for game which is not 9x9.
+func WriteSDX(w io.Writer, g *Game) error {
+	if !g.Geometry().same(StandardGeometry) {
+		return ErrStandardOnly
+	}
+	candidates, err := g.Candidates()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for r := 1; r < 10; r++ {
+		tokens := make([]string, 0, 9)
+		for c := 1; c < 10; c++ {
+			id := columnID(uint8(c)) + fmt.Sprintf("%d", r)
+			cell, ok := g.cells[id]
+			switch {
+			case ok && cell.Value() != EmptyCellValue && cell.SolutionCell():
+				tokens = append(tokens, sdxSolutionPrefix+cell.TextValue())
+			case ok && cell.Value() != EmptyCellValue:
+				tokens = append(tokens, cell.TextValue())
+			default:
+				text := valuesText(candidates[id])
+				if len(candidates[id]) < 2 {
+					text = sdxCandidatePrefix + text
+				}
+				tokens = append(tokens, text)
+			}
+		}
+		bw.WriteString(strings.Join(tokens, " ") + "\n")
+	}
+	return bw.Flush()
+}
+
+//Package private functions.
+
+func loadData(data []byte, format Format) (*Game, error) {
+	switch format {
+	case FormatLine:
+		reader := NewCollectionReader(strings.NewReader(string(data)))
+		if reader.Next() {
+			return reader.Puzzle().Game, nil
+		}
+		if reader.Err() != nil {
+			return nil, reader.Err()
+		}
+		return nil, newParseError(reader.line, 0, ErrParseLineLength)
+	case FormatGrid, FormatSS:
+		return NewGameFromString(string(data))
+	case FormatSDK:
+		p, err := ReadSDK(strings.NewReader(string(data)))
+		if err != nil {
+			return nil, err
+		}
+		return p.Game, nil
+	case FormatSDX:
+		return ReadSDX(strings.NewReader(string(data)))
+	case FormatCandidates:
+		return ParseCandidateVisual(string(data))
+	case FormatKiller:
+		return ReadKiller(strings.NewReader(string(data)))
+	case FormatJSON:
+		var g Game
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, err
+		}
+		return &g, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+//isCollection returns if every line starts with game in single line format,
+// 16 rows of 16x16 grid written without separators are not taken as 4x4 games.
+func isCollection(lines []string) bool {
+	for _, line := range lines {
+		length := len(strings.Fields(line)[0])
+		if length < LineLength && (geometryForLineLength(length) == nil || length == len(lines)) {
+			return false
+		}
+	}
+	return true
+}
+
+func readLines(r io.Reader) ([]textLine, error) {
+	var lines []textLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxCollectionLineSize)
+	number := 0
+	for scanner.Scan() {
+		number++
+		lines = append(lines, textLine{number: number, text: strings.TrimRight(scanner.Text(), "\r")})
+	}
+	return lines, scanner.Err()
+}
+
+//parseGridRows parses exactly 9 rows of grid, last is number of last line of input.
+func parseGridRows(rows []textLine, last int) ([]*Cell, error) {
+	if len(rows) != 9 {
+		if len(rows) > 9 {
+			last = rows[9].number
+		}
+		return nil, newParseError(last, 0, ErrParseCellsNoNineRows)
+	}
+	var cells []*Cell
+	for idx, row := range rows {
+		tmp, err := parseRow(row.text, uint8(idx+1), row.number, StandardGeometry)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, tmp...)
+	}
+	return cells, nil
+}
+
+func (p *Puzzle) setSDKMeta(meta string) {
+	if meta == "" {
+		return
+	}
+	value := strings.TrimSpace(meta[1:])
+	switch meta[:1] {
+	case sdkMetaName:
+		p.Name = value
+	case sdkMetaRating:
+		p.Rating = value
+	case sdkMetaSource:
+		p.Source = value
+	default:
+		p.Extra = append(p.Extra, meta)
+	}
+}
+
+//isCandidateRow returns if line is row of candidate grid - it has cells of some
+// grid size separated by space and |, more cells in every box (grid format has
+// box written without spaces) and some cell has more candidates.
+func isCandidateRow(line string) bool {
+	fields := strings.Fields(strings.Replace(line, candidateBoxSep, " ", -1))
+	if _, err := GeometryForSize(len(fields)); err != nil {
+		return false
+	}
+	boxes := 0
+	for _, box := range strings.Split(line, candidateBoxSep) {
+		if strings.TrimSpace(box) != "" {
+			boxes++
+		}
+	}
+	if boxes < 2 || len(fields) <= boxes {
+		return false
+	}
+	for _, field := range fields {
+		if len(field) > 1 && strings.Trim(field, "123456789ABCDEFG") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func writeLineAsRows(w *bufio.Writer, line string) {
+	for r := 0; r < 9; r++ {
+		w.WriteString(line[r*9:r*9+9] + "\n")
+	}
+}
+
+//splitTokens splits line by whitespace and returns tokens with their 1-based columns.
+func splitTokens(line string) ([]string, []int) {
+	var tokens []string
+	var columns []int
+	start := -1
+	for idx, char := range line + " " {
+		if char == ' ' || char == '\t' {
+			if start >= 0 {
+				tokens = append(tokens, line[start:idx])
+				columns = append(columns, start+1)
+				start = -1
+			}
+		} else if start < 0 {
+			start = idx
+		}
+	}
+	return tokens, columns
+}
+
+//parseSDXToken returns cell for given and solved token, candidates for empty cell token.
+func parseSDXToken(id string, token string) (*Cell, []uint8, bool) {
+	if strings.HasPrefix(token, sdxSolutionPrefix) || (len(token) == 1 && token != EmptyCellTextValue) {
+		value, ok := parseCellValue(rune(token[len(token)-1]))
+		if !ok || value == EmptyCellValue || len(strings.TrimPrefix(token, sdxSolutionPrefix)) != 1 {
+			return nil, nil, false
+		}
+		c, err := createCell(id, value, strings.HasPrefix(token, sdxSolutionPrefix))
+		return c, nil, err == nil
+	}
+	candidates := []uint8{}
+	for _, char := range strings.TrimPrefix(token, sdxCandidatePrefix) {
+		value, ok := parseCellValue(char)
+		if !ok || value == EmptyCellValue {
+			return nil, nil, false
+		}
+		candidates = append(candidates, value)
+	}
+	return nil, candidates, true
+}
+
+//valuesText returns values as text without separators, ex. 237.
+func valuesText(values []uint8) string {
+	return StandardGeometry.valuesText(values)
+}
+
+//valuesText method returns values as text without separators, ex. 237 or 1AG.
+func (g *Geometry) valuesText(values []uint8) string {
+	var text strings.Builder
+	for _, value := range values {
+		text.WriteString(g.ValueText(value))
+	}
+	return text.String()
+}
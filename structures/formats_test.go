@@ -0,0 +1,203 @@
+package structures
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const game1SS string = `*-----------*
+|8..|94.|..5|
+|...|.5.|2..|
+|1.9|6.2|...|
+|---+---+---|
+|5.1|...|..4|
+|46.|...|.53|
+|2..|...|8.1|
+|---+---+---|
+|...|4.9|1.7|
+|..4|.6.|...|
+|9..|.17|..6|
+*-----------*
+`
+
+const game1SDK string = `#Dfirst puzzle
+#L2.3
+#Anobody
+[Puzzle]
+8..94...5
+....5.2..
+1.96.2...
+5.1.....4
+46.....53
+2.....8.1
+...4.91.7
+..4.6....
+9...17..6
+[State]
+82.94...5
+....5.2..
+1.96.2...
+5.1.....4
+46.....53
+2.....8.1
+...4.91.7
+..4.6....
+9...17..6
+`
+
+func TestFormatsDetect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+	}{
+		{game1Line, FormatLine},
+		{"# comment\n" + game1Line + " name\n", FormatLine},
+		{game1Line + "\tfirst\n" + game1Line + "\tsecond\n", FormatLine},
+		{game1, FormatGrid},
+		{game1SS, FormatSS},
+		{game1SDK, FormatSDK},
+		{"#Dname\n" + strings.Join(strings.Split(game1SDK, "\n")[4:13], "\n"), FormatSDK},
+		{`{"givens":"` + game1Line + `"}`, FormatJSON},
+		{"8 c 237 9 4 u1 c 3 5\n", FormatSDX},
+	}
+	for _, test := range tests {
+		if got := DetectFormat([]byte(test.input)); got != test.expected {
+			t.Errorf("DetectFormat(%q) = %s, but expected: %s", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestFormatsLoadAndSave(t *testing.T) {
+	g, err := ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.AddCell(createSolutionCell("b1", 2))
+	if err = g.AddCage(&Cage{Sum: 10, Cells: []string{"a1", "b1"}}); err != nil {
+		t.Errorf("Game.AddCage should pass, but err: %v", err)
+	}
+	for _, format := range Formats() {
+		var buffer bytes.Buffer
+		if err = Save(&buffer, g, format); err != nil {
+			t.Errorf("Save in format %s should pass, but err: %v", format, err)
+			continue
+		}
+		text := buffer.String()
+		if detected := DetectFormat(buffer.Bytes()); detected != format {
+			t.Errorf("Format %s was detected as %s:\n%s", format, detected, text)
+		}
+		loaded, err := Load(&buffer)
+		if err != nil {
+			t.Errorf("Load of format %s should pass, but err: %v\n%s", format, err, text)
+			continue
+		}
+		if loaded.Line() != g.Line() {
+			t.Errorf("Format %s loaded game %s, but expected: %s", format, loaded.Line(), g.Line())
+		}
+		if (format == FormatKiller || format == FormatJSON) && len(loaded.Cages()) != 1 {
+			t.Errorf("Format %s should keep cage, but loaded cages: %v", format, loaded.Cages())
+		}
+	}
+	if err = Save(&bytes.Buffer{}, g, Format("xml")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Save should return ErrUnknownFormat, but returned: %v", err)
+	}
+}
+
+func TestFormatsSDKMetadata(t *testing.T) {
+	p, err := ReadSDK(strings.NewReader(game1SDK))
+	if err != nil {
+		t.Errorf("ReadSDK should pass, but err: %v", err)
+		return
+	}
+	if p.Name != "first puzzle" || p.Rating != "2.3" || !reflect.DeepEqual(p.Extra, []string{"Anobody"}) {
+		t.Errorf("ReadSDK returns puzzle %+v with wrong metadata", p)
+	}
+	if p.Game.SolutionCellCount() != 1 || p.Game.FilledCellCount() != 31 {
+		t.Errorf("ReadSDK game has %d solution cells and %d filled cells, but expected 1 and 31",
+			p.Game.SolutionCellCount(), p.Game.FilledCellCount())
+	}
+	var buffer bytes.Buffer
+	if err = WriteSDK(&buffer, p); err != nil {
+		t.Errorf("WriteSDK should pass, but err: %v", err)
+	}
+	if buffer.String() != game1SDK {
+		t.Errorf("WriteSDK writes\n%s, but expected:\n%s", buffer.String(), game1SDK)
+	}
+	_, err = ReadSDK(strings.NewReader("[Puzzle]\n8..94...5\n"))
+	if !errors.Is(err, ErrParseCellsNoNineRows) {
+		t.Errorf("ReadSDK should return ErrParseCellsNoNineRows, but returned: %v", err)
+	}
+}
+
+func TestFormatsSDXPencilMarks(t *testing.T) {
+	g, err := ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.AddCell(createSolutionCell("i2", 9))
+	g.SetPencilMarks("b1", []uint8{7, 3})
+	g.SetPencilMarks("c1", []uint8{6})
+	var buffer bytes.Buffer
+	if err = WriteSDX(&buffer, g); err != nil {
+		t.Errorf("WriteSDX should pass, but err: %v", err)
+	}
+	firstRow := strings.Split(buffer.String(), "\n")[0]
+	if firstRow != "8 37 c6 9 4 13 367 1367 5" {
+		t.Errorf("WriteSDX writes first row %q", firstRow)
+	}
+	loaded, err := ReadSDX(&buffer)
+	if err != nil {
+		t.Errorf("ReadSDX should pass, but err: %v", err)
+		return
+	}
+	expected, _ := g.Candidates()
+	candidates, _ := loaded.Candidates()
+	if !reflect.DeepEqual(candidates, expected) {
+		t.Errorf("ReadSDX candidates are %v, but expected: %v", candidates, expected)
+	}
+	if cell, _ := loaded.Cell("i2"); cell == nil || !cell.SolutionCell() || cell.Value() != 9 {
+		t.Errorf("ReadSDX should read solution cell i2=9, but read: %v", cell)
+	}
+	_, err = ReadSDX(strings.NewReader(strings.Repeat("8 37 x6 9 4 13 367 1367 5\n", 9)))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrParseSDXInvalidCell) || parseErr.Column != 6 {
+		t.Errorf("ReadSDX should return ErrParseSDXInvalidCell at column 6, but returned: %v", err)
+	}
+}
+
+func TestFormatsParseFormat(t *testing.T) {
+	tests := map[string]Format{"ss": FormatSS, ".SDK": FormatSDK, "sdx": FormatSDX, "txt": FormatGrid}
+	for name, expected := range tests {
+		if got, err := ParseFormat(name); err != nil || got != expected {
+			t.Errorf("ParseFormat(%s) = %s, %v, but expected: %s", name, got, err, expected)
+		}
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat should return ErrUnknownFormat, but returned: %v", err)
+	}
+}
+
+func TestGamePencilMarks(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	if err = g.SetPencilMarks("b1", []uint8{7, 2, 7}); err != nil {
+		t.Errorf("SetPencilMarks should pass, but err: %v", err)
+	}
+	marks, ok := g.PencilMarks("b1")
+	if !ok || !reflect.DeepEqual(marks, []uint8{2, 7}) || !g.HasPencilMarks() {
+		t.Errorf("PencilMarks of b1 are %v, but expected: %v", marks, []uint8{2, 7})
+	}
+	var valueErr *ValueError
+	if err = g.SetPencilMarks("b1", []uint8{0}); !errors.As(err, &valueErr) {
+		t.Errorf("SetPencilMarks should return ValueError, but returned: %v", err)
+	}
+	g.ClearPencilMarks("b1")
+	if _, ok = g.PencilMarks("b1"); ok || g.HasPencilMarks() {
+		t.Error("PencilMarks of b1 should be cleared.")
+	}
+}
@@ -1,26 +1,15 @@
 package structures
 
 import (
-	"errors"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 )
 
 //Messages for game errors.
 const (
-	ErrParseCellsNoThreePartsMsg string = "No 3 parts devided by | in row: %s."
-	ErrParseCellsNoThreeCharsMsg string = "Row part has not 3 characters: %s."
-	ErrDuplicatedCellInGameMsg   string = "Cell id=%s is already presented in game."
-	ErrCellWasNotFoundMsg        string = "Cell id=%s was not found in game."
-)
-
-//Errors for game object.
-var (
-	ErrParseCellsNoNineRows                      error = errors.New("No 9 rows in game input")
-	ErrOnlyOneFromRowColumnSquareMustBeSpecified error = errors.New("Only one argument from (row, column, square) should be > 0")
-	ErrOnlyOneArgumentShouldBeTrue               error = errors.New("Only one argument from (row, column, square) should be true")
+	ErrDuplicatedCellInGameMsg string = "Cell id=%s is already presented in game."
+	ErrCellWasNotFoundMsg      string = "Cell id=%s was not found in game."
 )
 
 //Package private functions.
@@ -31,96 +20,46 @@ func valueFoundInSlice(slice []uint8, value uint8) bool {
 	return found
 }
 
-func parseCells(textCells string) ([]*Cell, error) {
-	rows := strings.Split(textCells, "\n")
-	if len(rows) != 9 {
-		return nil, ErrParseCellsNoNineRows
-	}
-	var err error
-	var cells, tmp []*Cell
-	for index, row := range rows {
-		tmp, err = parseRow(row, uint8(index+1))
-		if err != nil {
-			return nil, err
-		}
-		cells = append(cells, tmp...)
-	}
-	return cells, nil
-}
-
-func parseRow(textRow string, rowIndex uint8) ([]*Cell, error) {
-	parts := strings.Split(strings.TrimSpace(textRow), "|")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf(ErrParseCellsNoThreePartsMsg, textRow)
-	}
-	var cells, tmp []*Cell
-	var err error
-	cells, err = processRowPart(parts[0], rowIndex, "abc")
-	if err != nil {
-		return nil, err
-	}
-	tmp, err = processRowPart(parts[1], rowIndex, "def")
-	if err != nil {
-		return nil, err
-	}
-	cells = append(cells, tmp...)
-	tmp, err = processRowPart(parts[2], rowIndex, "ghi")
-	if err != nil {
-		return nil, err
-	}
-	cells = append(cells, tmp...)
-	return cells, nil
-}
-
-func processRowPart(part string, rowIndex uint8, columns string) ([]*Cell, error) {
-	if len(part) != 3 {
-		return nil, fmt.Errorf(ErrParseCellsNoThreeCharsMsg, part)
-	}
-	var cells []*Cell
-	var c *Cell
-	var value uint64
-	var err error
-	for index, char := range part {
-		if string(char) != EmptyCellTextValue {
-			value, err = strconv.ParseUint(string(char), 10, 8)
-			if err != nil {
-				return nil, err
-			}
-			c, err = NewCell(string(columns[index])+fmt.Sprintf("%d", rowIndex), uint8(value))
-			if err != nil {
-				return nil, err
-			}
-			cells = append(cells, c)
-		}
-	}
-	return cells, nil
-}
-
 //Game struct represents one sudoku game.
 type Game struct {
 	cells         map[string]*Cell
 	solutionSteps []string
+	pencilMarks   map[string][]uint8
+	geometry      *Geometry
+	cages         []*Cage
+	variant       Variant
 }
 
 //Game constructors.
 
 //NewGameFromString creates Game object from string representation.
 func NewGameFromString(textCells string) (*Game, error) {
-	cells, err := parseCells(textCells)
+	cells, geometry, err := parseCells(textCells)
 	if err != nil {
 		return nil, err
 	}
-	game, err := NewGameFromCells(cells)
+	game, err := NewGame(geometry, cells)
 	if err != nil {
 		return nil, err
 	}
 	return game, nil
 }
 
-//NewGameFromCells creates Game object from slice of cells.
+//NewGameFromCells creates Game object from slice of cells, geometry of the
+// game is geometry of the first cell (standard 9x9 grid when there is no cell).
 func NewGameFromCells(cells []*Cell) (*Game, error) {
-	g := Game{}
+	geometry := StandardGeometry
+	if len(cells) > 0 {
+		geometry = cells[0].Geometry()
+	}
+	return NewGame(geometry, cells)
+}
+
+//NewGame creates Game object with grid of geometry from slice of cells.
+func NewGame(geometry *Geometry, cells []*Cell) (*Game, error) {
+	g := Game{geometry: geometry}
 	g.cells = make(map[string]*Cell)
+	g.pencilMarks = make(map[string][]uint8)
 	var err error
 	for _, c := range cells {
 		err = g.AddCell(c)
@@ -133,11 +72,23 @@ func NewGameFromCells(cells []*Cell) (*Game, error) {
 
 //Game public methods.
 
-//AddCell method add new cell to the game.
+//Geometry returns geometry of the game grid.
+func (g *Game) Geometry() *Geometry {
+	if g.geometry == nil {
+		return StandardGeometry
+	}
+	return g.geometry
+}
+
+//AddCell method add new cell to the game, cell should have the same geometry
+// as the game.
 func (g *Game) AddCell(c *Cell) error {
+	if !c.Geometry().same(g.Geometry()) {
+		return ErrGeometryMismatch
+	}
 	_, ok := g.cells[c.Id]
 	if ok {
-		return fmt.Errorf(ErrDuplicatedCellInGameMsg, c.Id)
+		return &DuplicateCellError{ID: c.Id}
 	}
 	g.cells[c.Id] = c
 	if c.SolutionCell() {
@@ -163,24 +114,24 @@ func (g *Game) AddStringCell(textCell string) error {
 func (g *Game) Cell(id string) (*Cell, error) {
 	c, ok := g.cells[id]
 	if !ok {
-		return nil, fmt.Errorf(ErrCellWasNotFoundMsg, id)
+		return nil, &CellNotFoundError{ID: id}
 	}
 	return c, nil
 }
 
 //EmptyCellCount returns count of empty cells in the game.
-func (g *Game) EmptyCellCount() uint8 {
-	return uint8(81 - g.FilledCellCount())
+func (g *Game) EmptyCellCount() int {
+	return g.Geometry().Cells() - g.FilledCellCount()
 }
 
 //FilledCellCount returns count of filled cells in the game.
-func (g *Game) FilledCellCount() uint8 {
-	return uint8(len(g.cells))
+func (g *Game) FilledCellCount() int {
+	return len(g.cells)
 }
 
 //SolutionCellCount returns count of solution cells in game.
-func (g *Game) SolutionCellCount() uint8 {
-	count := uint8(0)
+func (g *Game) SolutionCellCount() int {
+	count := 0
 	for _, c := range g.cells {
 		if c.SolutionCell() {
 			count++
@@ -189,77 +140,214 @@ func (g *Game) SolutionCellCount() uint8 {
 	return count
 }
 
-//Validate method makes validation of the game and returns
-// if problem is in rows, columns or squares.
+//Validate method makes validation of the game and returns if there is no
+// problem in rows, in columns and in other units of the grid (squares and
+// diagonals or windows of game variant).
 func (g *Game) Validate() (*bool, *bool, *bool, error) {
-	var err error
-	invalidRows, err := g.validateRows()
+	conflicts, err := g.unitConflicts()
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	invalidColumns, err := g.validateColumns()
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	invalidSquares, err := g.validateSquares()
-	if err != nil {
-		return nil, nil, nil, err
+	rowsOk, columnsOk, squaresOk := true, true, true
+	for _, c := range conflicts {
+		switch c.Unit {
+		case UnitRow:
+			rowsOk = false
+		case UnitColumn:
+			columnsOk = false
+		default:
+			squaresOk = false
+		}
 	}
-	rowsOk := len(invalidRows) == 0
-	columnsOk := len(invalidColumns) == 0
-	squaresOk := len(invalidSquares) == 0
 	return &rowsOk, &columnsOk, &squaresOk, nil
 }
 
-//CellFreeValues returns for the cell which values can have yet.
+//ValidationReport method makes validation of the game and returns detailed
+// report with all conflicts and empty cells without any free value.
+func (g *Game) ValidationReport() (*ValidationReport, error) {
+	report := ValidationReport{}
+	validators := []func() ([]Conflict, error){g.unitConflicts, g.validateCages}
+	for _, validator := range validators {
+		conflicts, err := validator()
+		if err != nil {
+			return nil, err
+		}
+		report.Conflicts = append(report.Conflicts, conflicts...)
+	}
+	for _, cellID := range g.EmptyCells() {
+		cell, err := g.Geometry().NewSolutionCell(cellID, EmptyCellValue)
+		if err != nil {
+			return nil, err
+		}
+		values, err := g.CellFreeValues(cell)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			report.EmptyCandidates = append(report.EmptyCandidates, cellID)
+		}
+	}
+	return &report, nil
+}
+
+//CellFreeValues returns for the cell which values can have yet - values which
+// are not in any unit of the cell, values of cell in cage must be part of some
+// combination which gives rest of cage sum.
 func (g *Game) CellFreeValues(cell *Cell) ([]uint8, error) {
-	rowValues, err := g.specifiedCellsValues(cell.Row(), 0, 0)
-	if err != nil {
-		return nil, err
+	unitValues := g.unitValues(cell)
+	var result []uint8
+	for v := 1; v <= int(g.Geometry().Size); v++ {
+		if !valueFoundInSlice(unitValues, uint8(v)) {
+			result = append(result, uint8(v))
+		}
 	}
-	colValues, err := g.specifiedCellsValues(0, cell.Column(), 0)
-	if err != nil {
-		return nil, err
+	if cage := g.CageOf(cell.Id); cage != nil {
+		cageValues := cage.candidates(g)
+		filtered := result[:0]
+		for _, v := range result {
+			if valueFoundInSlice(cageValues, v) {
+				filtered = append(filtered, v)
+			}
+		}
+		result = filtered
 	}
-	squareValues, err := g.specifiedCellsValues(0, 0, cell.Square())
+	return result, nil
+}
+
+//SetPencilMarks method sets candidates of the cell explicitly, they are used instead
+// of computed free values until the cell is filled or pencil marks are cleared.
+func (g *Game) SetPencilMarks(id string, values []uint8) error {
+	c, err := g.Geometry().NewCell(id, EmptyCellValue)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var result []uint8
-	var rFound, cFound, sFound bool
-	for v := 1; v < 10; v++ {
-		rFound = valueFoundInSlice(rowValues, uint8(v))
-		cFound = valueFoundInSlice(colValues, uint8(v))
-		sFound = valueFoundInSlice(squareValues, uint8(v))
-		if !rFound && !cFound && !sFound {
-			result = append(result, uint8(v))
+	marks := make([]uint8, 0, len(values))
+	for _, value := range values {
+		if value < 1 || value > g.Geometry().Size {
+			return &ValueError{Value: value}
+		}
+		if !valueFoundInSlice(marks, value) {
+			marks = append(marks, value)
+			sort.Slice(marks, func(i, j int) bool { return marks[i] < marks[j] })
+		}
+	}
+	if g.pencilMarks == nil {
+		g.pencilMarks = make(map[string][]uint8)
+	}
+	g.pencilMarks[c.Id] = marks
+	return nil
+}
+
+//PencilMarks returns explicitly set candidates of the cell and if they are set.
+func (g *Game) PencilMarks(id string) ([]uint8, bool) {
+	marks, ok := g.pencilMarks[id]
+	return marks, ok
+}
+
+//ClearPencilMarks method removes explicitly set candidates of the cell.
+func (g *Game) ClearPencilMarks(id string) {
+	delete(g.pencilMarks, id)
+}
+
+//HasPencilMarks returns if some empty cell has explicitly set candidates.
+func (g *Game) HasPencilMarks() bool {
+	for id := range g.pencilMarks {
+		if _, ok := g.cells[id]; !ok {
+			return true
 		}
 	}
+	return false
+}
+
+//Candidates returns candidates for every empty cell of the game - pencil marks
+// when they are set, computed free values otherwise.
+func (g *Game) Candidates() (map[string][]uint8, error) {
+	result := make(map[string][]uint8)
+	for _, id := range g.EmptyCells() {
+		if marks, ok := g.PencilMarks(id); ok {
+			result[id] = append([]uint8{}, marks...)
+			continue
+		}
+		cell, err := g.Geometry().NewSolutionCell(id, EmptyCellValue)
+		if err != nil {
+			return nil, err
+		}
+		values, err := g.CellFreeValues(cell)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			values = []uint8{}
+		}
+		result[id] = values
+	}
 	return result, nil
 }
 
-//GameVisual returns visual representation of the game.
+//GameVisual returns visual representation of the game, squares are separated by |.
 func (g *Game) GameVisual() string {
+	geometry := g.Geometry()
 	var visual string
-	for r := 1; r < 10; r++ {
+	for r := uint8(1); r <= geometry.Size; r++ {
 		line := ""
-		for c := 1; c < 10; c++ {
-			line += g.findCellValue(uint8(r), uint8(c))
+		for c := uint8(1); c <= geometry.Size; c++ {
+			if c > 1 && (c-1)%geometry.BoxColumns == 0 {
+				line += "|"
+			}
+			line += g.findCellValue(r, c)
 		}
-		visual += line[:3] + "|" + line[3:6] + "|" + line[6:] + "\n"
+		visual += line + "\n"
 	}
 	return visual
 }
 
+//Line returns game in single line format (81 characters for 9x9 grid, . for
+// empty cell, letters for values greater than 9).
+func (g *Game) Line() string {
+	geometry := g.Geometry()
+	line := []byte(strings.Repeat(EmptyCellTextValue, geometry.Cells()))
+	for _, c := range g.cells {
+		if c.Value() != EmptyCellValue {
+			line[int(c.Row()-1)*int(geometry.Size)+int(c.Column()-1)] = geometry.ValueText(c.Value())[0]
+		}
+	}
+	return string(line)
+}
+
+//Clone returns deep copy of the game, cells and pencil marks are not shared
+// (cages can not be changed, so they are shared).
+func (g *Game) Clone() *Game {
+	clone := Game{
+		cells:         make(map[string]*Cell, len(g.cells)),
+		solutionSteps: append([]string{}, g.solutionSteps...),
+		pencilMarks:   make(map[string][]uint8, len(g.pencilMarks)),
+		geometry:      g.geometry,
+		cages:         append([]*Cage{}, g.cages...),
+		variant:       g.variant,
+	}
+	for id, c := range g.cells {
+		cell := *c
+		if c.value != nil {
+			value := *c.value
+			cell.value = &value
+		}
+		clone.cells[id] = &cell
+	}
+	for id, marks := range g.pencilMarks {
+		clone.pencilMarks[id] = append([]uint8{}, marks...)
+	}
+	return &clone
+}
+
 //EmptyCells returns slice of empty cells in the game.
 func (g *Game) EmptyCells() []string {
-	columns := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}
+	geometry := g.Geometry()
 	var ok bool
 	var result []string
 	var id string
-	for r := 1; r < 10; r++ {
-		for _, c := range columns {
-			id = string(c) + fmt.Sprintf("%d", r)
+	for r := uint8(1); r <= geometry.Size; r++ {
+		for c := uint8(1); c <= geometry.Size; c++ {
+			id = geometry.CellID(r, c)
 			_, ok = g.cells[id]
 			if !ok {
 				result = append(result, id)
@@ -287,92 +375,20 @@ func (g *Game) RemoveSolutionCells(cellIds []string) {
 			delete(g.cells, cellID)
 		}
 	}
-}
-
-//Game private methods.
-
-func (g *Game) validateEntities(rows bool, columns bool, squares bool) ([]uint8, error) {
-	if (rows && columns) || (rows && squares) || (columns && squares) {
-		return nil, ErrOnlyOneArgumentShouldBeTrue
-	}
-	var invalid []uint8
-	entities := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9}
-	var partResult *bool
-	var err error
-	var rowIdx, colIdx, squareIdx uint8
-	for _, entity := range entities {
-		rowIdx, colIdx, squareIdx = 0, 0, 0
-		if rows {
-			rowIdx = entity
-		} else if columns {
-			colIdx = entity
-		} else if squares {
-			squareIdx = entity
-		}
-		partResult, err = g.specifiedCellsHaveDuplicities(rowIdx, colIdx, squareIdx)
-		if err != nil {
-			return nil, err
-		}
-		if *partResult {
-			invalid = append(invalid, entity)
+	steps := g.solutionSteps[:0]
+	for _, cellID := range g.solutionSteps {
+		if _, ok := g.cells[cellID]; ok {
+			steps = append(steps, cellID)
 		}
 	}
-	return invalid, nil
-}
-
-func (g *Game) validateRows() ([]uint8, error) {
-	invalid, err := g.validateEntities(true, false, false)
-	return invalid, err
-}
-
-func (g *Game) validateColumns() ([]uint8, error) {
-	invalid, err := g.validateEntities(false, true, false)
-	return invalid, err
-}
-
-func (g *Game) validateSquares() ([]uint8, error) {
-	invalid, err := g.validateEntities(false, false, true)
-	return invalid, err
+	g.solutionSteps = steps
 }
 
-func (g *Game) specifiedCellsHaveDuplicities(row uint8, column uint8, square uint8) (*bool, error) {
-	if (row > 0 && column > 0) || (row > 0 && square > 0) || (column > 0 && square > 0) {
-		return nil, ErrOnlyOneFromRowColumnSquareMustBeSpecified
-	}
-	valueOccurences := make(map[uint8]uint8)
-	result := false
-	values, err := g.specifiedCellsValues(row, column, square)
-	if err != nil {
-		return nil, err
-	}
-	for _, val := range values {
-		if valueOccurences[val] > 0 {
-			result = true
-			return &result, nil
-		}
-		valueOccurences[val]++
-	}
-	return &result, nil
-}
-
-func (g *Game) specifiedCellsValues(row uint8, column uint8, square uint8) ([]uint8, error) {
-	if (row > 0 && column > 0) || (row > 0 && square > 0) || (column > 0 && square > 0) {
-		return nil, ErrOnlyOneFromRowColumnSquareMustBeSpecified
-	}
-	var result []uint8
-	for _, c := range g.cells {
-		if (row > 0 && c.Row() == row) || (column > 0 && c.Column() == column) ||
-			(square > 0 && c.Square() == square) {
-			result = append(result, c.Value())
-		}
-	}
-	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
-	return result, nil
-}
+//Game private methods.
 
 func (g *Game) findCellValue(rowIdx uint8, colIdx uint8) string {
 	for _, c := range g.cells {
-		if c.Row() == rowIdx && c.Column() == colIdx {
+		if c.Row() == rowIdx && c.Column() == colIdx && c.Value() != EmptyCellValue {
 			return c.TextValue()
 		}
 	}
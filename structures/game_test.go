@@ -20,7 +20,7 @@ func TestGameObjectCreatedFromString(t *testing.T) {
 	if err != nil {
 		t.Errorf("Game should be succesfully created, but err: %v", err)
 	}
-	var expected uint8 = 30
+	var expected = 30
 	if g.FilledCellCount() != expected {
 		t.Errorf("Game have filled cells: %d, but expected number: %d", g.FilledCellCount(), expected)
 	}
@@ -33,13 +33,45 @@ func TestGameObjectCreatedFromString(t *testing.T) {
 	}
 }
 
+func TestGameLine(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.AddCell(createSolutionCell("b1", 2))
+	expected := "82.94...5....5.2..1.96.2...5.1.....446.....532.....8.1...4.91.7..4.6....9...17..6"
+	if g.Line() != expected {
+		t.Errorf("Game line is %s, but expected: %s", g.Line(), expected)
+	}
+}
+
+func TestGameClone(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.SetPencilMarks("c1", []uint8{3, 6})
+	clone := g.Clone()
+	clone.AddCell(createSolutionCell("b1", 2))
+	clone.SetPencilMarks("c1", []uint8{3})
+	if g.Line() == clone.Line() || g.SolutionCellCount() != 0 {
+		t.Errorf("Game should not be changed by its clone, but is: %s", g.Line())
+	}
+	if marks, _ := g.PencilMarks("c1"); len(marks) != 2 {
+		t.Errorf("Game pencil marks should not be changed by its clone, but are: %v", marks)
+	}
+	if clone.SolutionCellCount() != 1 || clone.FilledCellCount() != g.FilledCellCount()+1 {
+		t.Errorf("Clone should have one more cell, but has: %d", clone.FilledCellCount())
+	}
+}
+
 func TestGameCreateEmptyObject(t *testing.T) {
 	var cells []*Cell
 	g, err := NewGameFromCells(cells)
 	if err != nil {
 		t.Errorf("Game should be succesfully created, but err: %v", err)
 	}
-	var expected uint8 = 0
+	var expected = 0
 	if g.FilledCellCount() != expected {
 		t.Errorf("Game have filled cells: %d, but expected number: %d", g.FilledCellCount(), expected)
 	}
@@ -170,6 +202,9 @@ func TestGameRemoveSolutionCells(t *testing.T) {
 	if cellCount != expectedCount {
 		t.Errorf("Game solution cells should be: %d, but is %d", expectedCount, cellCount)
 	}
+	if !reflect.DeepEqual(g.solutionSteps, []string{"a7"}) {
+		t.Errorf("Game solution steps are %v, but expected: %v", g.solutionSteps, []string{"a7"})
+	}
 }
 
 func createSolutionCell(id string, value byte) *Cell {
@@ -0,0 +1,141 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//Errors for grid geometry.
+var (
+	ErrUnsupportedGeometry error = errors.New("Grid size should be 4, 6, 9, 12 or 16")
+	ErrGeometryMismatch    error = errors.New("Cell and game have different grid size")
+	ErrStandardOnly        error = errors.New("Only standard 9x9 grid is supported")
+)
+
+//Geometry represents size of the grid and shape of its squares (boxes). Square
+// has BoxRows rows and BoxColumns columns, grid has Size rows, Size columns and
+// Size squares. Columns are named by letters from a, rows are numbered from 1,
+// values greater than 9 are written as letters from A (A = 10, G = 16).
+type Geometry struct {
+	Size       uint8
+	BoxRows    uint8
+	BoxColumns uint8
+}
+
+//Supported geometries.
+var (
+	Geometry4x4      *Geometry = &Geometry{Size: 4, BoxRows: 2, BoxColumns: 2}
+	Geometry6x6      *Geometry = &Geometry{Size: 6, BoxRows: 2, BoxColumns: 3}
+	StandardGeometry *Geometry = &Geometry{Size: 9, BoxRows: 3, BoxColumns: 3}
+	Geometry12x12    *Geometry = &Geometry{Size: 12, BoxRows: 3, BoxColumns: 4}
+	Geometry16x16    *Geometry = &Geometry{Size: 16, BoxRows: 4, BoxColumns: 4}
+)
+
+//Geometries returns all supported geometries ordered by size.
+func Geometries() []*Geometry {
+	return []*Geometry{Geometry4x4, Geometry6x6, StandardGeometry, Geometry12x12, Geometry16x16}
+}
+
+//GeometryForSize returns supported geometry with size (count of rows).
+func GeometryForSize(size int) (*Geometry, error) {
+	for _, g := range Geometries() {
+		if int(g.Size) == size {
+			return g, nil
+		}
+	}
+	return nil, ErrUnsupportedGeometry
+}
+
+//ParseGeometry returns geometry from its name - size (ex. 6) or size of rows
+// and columns (ex. 6x6).
+func ParseGeometry(name string) (*Geometry, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if parts := strings.Split(name, "x"); len(parts) == 2 && parts[0] == parts[1] {
+		name = parts[0]
+	}
+	size, err := strconv.Atoi(name)
+	if err != nil {
+		return nil, ErrUnsupportedGeometry
+	}
+	return GeometryForSize(size)
+}
+
+//geometryForLineLength returns geometry of game in single line format with
+// length characters, nil when no geometry has such line.
+func geometryForLineLength(length int) *Geometry {
+	for _, g := range Geometries() {
+		if g.Cells() == length {
+			return g
+		}
+	}
+	return nil
+}
+
+//String returns name of geometry, ex. 9x9.
+func (g *Geometry) String() string {
+	return fmt.Sprintf("%dx%d", g.Size, g.Size)
+}
+
+//Cells returns count of cells of the grid, it is also length of single line format.
+func (g *Geometry) Cells() int {
+	return int(g.Size) * int(g.Size)
+}
+
+//Box returns square index (1 - Size) of cell in row and column (1 - Size).
+// Squares are numbered by rows from the top left one.
+func (g *Geometry) Box(row uint8, column uint8) uint8 {
+	return (row-1)/g.BoxRows*(g.Size/g.BoxColumns) + (column-1)/g.BoxColumns + 1
+}
+
+//CellID returns id of cell in row and column (1 - Size), ex. a1 or p16.
+func (g *Geometry) CellID(row uint8, column uint8) string {
+	return columnID(column) + strconv.Itoa(int(row))
+}
+
+//ValueText returns text of value - digit, letter for values greater than 9
+// or . for empty cell.
+func (g *Geometry) ValueText(value uint8) string {
+	switch {
+	case value == EmptyCellValue:
+		return EmptyCellTextValue
+	case value < 10:
+		return string(rune('0' + value))
+	}
+	return string(rune('A' + value - 10))
+}
+
+//ParseValue converts one character to value of the grid, . and 0 mean empty
+// cell, letters (case is ignored) are values greater than 9.
+func (g *Geometry) ParseValue(char rune) (uint8, bool) {
+	var value uint8
+	switch {
+	case string(char) == EmptyCellTextValue || char == alternateEmptyValue:
+		return EmptyCellValue, true
+	case char >= '1' && char <= '9':
+		value = uint8(char - '0')
+	case char >= 'A' && char <= 'Z':
+		value = uint8(char-'A') + 10
+	case char >= 'a' && char <= 'z':
+		value = uint8(char-'a') + 10
+	default:
+		return 0, false
+	}
+	return value, value <= g.Size
+}
+
+//NewCell creates cell object of the grid with solutionCell=false.
+func (g *Geometry) NewCell(id string, value uint8) (*Cell, error) {
+	return g.createCell(id, value, false)
+}
+
+//NewSolutionCell creates cell object of the grid with solutionCell=true.
+func (g *Geometry) NewSolutionCell(id string, value uint8) (*Cell, error) {
+	return g.createCell(id, value, true)
+}
+
+//same returns if geometries have the same shape.
+func (g *Geometry) same(other *Geometry) bool {
+	return *g == *other
+}
@@ -0,0 +1,223 @@
+package structures
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	mini4Line   string = "1....2...3.2...."
+	mini6Line   string = ".......4..61.5....3...2.......2.6.3."
+	hex16Line   string = ".B.....D9....4..E...9.1.....C3AF...4..GE.382.....8.C67.35...1G....2A..6...3....D.F8.C..B24..A..5C.9.3...7.5...F..6.D.G...........DAG5F91C..B......76..4......F..894...2C6...7...1.........E4G..9.....3.6....F.8.F1.8E.A4.D...9.G....2.....9...E6.G.E.8D.....5..B"
+	mini6Visual string = "...|...\n.4.|.61\n.5.|...\n3..|.2.\n...|...\n2.6|.3.\n"
+)
+
+func TestGeometryBox(t *testing.T) {
+	tests := []struct {
+		geometry *Geometry
+		id       string
+		expected uint8
+	}{
+		{Geometry4x4, "b2", 1},
+		{Geometry4x4, "c2", 2},
+		{Geometry4x4, "a3", 3},
+		{Geometry6x6, "c2", 1},
+		{Geometry6x6, "d1", 2},
+		{Geometry6x6, "a3", 3},
+		{Geometry6x6, "f6", 6},
+		{StandardGeometry, "e5", 5},
+		{Geometry12x12, "e1", 2},
+		{Geometry12x12, "l4", 6},
+		{Geometry16x16, "p16", 16},
+		{Geometry16x16, "e5", 6},
+	}
+	for _, test := range tests {
+		c, err := test.geometry.NewCell(test.id, EmptyCellValue)
+		if err != nil {
+			t.Errorf("Cell %s of %s grid should be created, but err: %v", test.id, test.geometry, err)
+			continue
+		}
+		if c.Square() != test.expected {
+			t.Errorf("Cell %s of %s grid is in square %d, but expected: %d", test.id, test.geometry,
+				c.Square(), test.expected)
+		}
+	}
+}
+
+func TestGeometryCellLimits(t *testing.T) {
+	tests := []struct {
+		geometry *Geometry
+		id       string
+		value    uint8
+		ok       bool
+	}{
+		{Geometry4x4, "d4", 4, true},
+		{Geometry4x4, "e1", 1, false},
+		{Geometry4x4, "a5", 1, false},
+		{Geometry4x4, "a1", 5, false},
+		{Geometry16x16, "p16", 16, true},
+		{Geometry16x16, "a10", 10, true},
+		{Geometry16x16, "q1", 1, false},
+		{Geometry16x16, "a17", 1, false},
+		{Geometry16x16, "a01", 1, false},
+		{Geometry16x16, "a1", 17, false},
+	}
+	for _, test := range tests {
+		_, err := test.geometry.NewCell(test.id, test.value)
+		if (err == nil) != test.ok {
+			t.Errorf("%s.NewCell(%s, %d) returns err: %v, but expected success: %t", test.geometry,
+				test.id, test.value, err, test.ok)
+		}
+	}
+}
+
+func TestGeometryValues(t *testing.T) {
+	for value := uint8(0); value <= Geometry16x16.Size; value++ {
+		text := Geometry16x16.ValueText(value)
+		parsed, ok := Geometry16x16.ParseValue(rune(text[0]))
+		if !ok || parsed != value {
+			t.Errorf("Value %d written as %s is parsed as %d", value, text, parsed)
+		}
+	}
+	if _, ok := StandardGeometry.ParseValue('A'); ok {
+		t.Errorf("Value A should not be parsed in 9x9 grid")
+	}
+	if value, ok := Geometry12x12.ParseValue('c'); !ok || value != 12 {
+		t.Errorf("Value c should be parsed as 12 in 12x12 grid, but is: %d", value)
+	}
+}
+
+func TestParseGeometry(t *testing.T) {
+	tests := map[string]*Geometry{"4": Geometry4x4, "6x6": Geometry6x6, " 16X16 ": Geometry16x16}
+	for name, expected := range tests {
+		g, err := ParseGeometry(name)
+		if err != nil || g != expected {
+			t.Errorf("ParseGeometry(%q) returns %v (err: %v), but expected: %v", name, g, err, expected)
+		}
+	}
+	for _, name := range []string{"5", "6x9", "big"} {
+		if _, err := ParseGeometry(name); !errors.Is(err, ErrUnsupportedGeometry) {
+			t.Errorf("ParseGeometry(%q) should return ErrUnsupportedGeometry, but returned: %v", name, err)
+		}
+	}
+}
+
+func TestGeometryGames(t *testing.T) {
+	tests := []struct {
+		line     string
+		geometry *Geometry
+		filled   int
+	}{
+		{mini4Line, Geometry4x4, 4},
+		{mini6Line, Geometry6x6, 9},
+		{hex16Line, Geometry16x16, 95},
+	}
+	for _, test := range tests {
+		g, err := ParseLine(test.line)
+		if err != nil {
+			t.Errorf("ParseLine should create %s game, but err: %v", test.geometry, err)
+			continue
+		}
+		if g.Geometry() != test.geometry || g.FilledCellCount() != test.filled ||
+			g.EmptyCellCount() != test.geometry.Cells()-test.filled {
+			t.Errorf("Game is %s with %d filled cells, but expected: %s with %d", g.Geometry(),
+				g.FilledCellCount(), test.geometry, test.filled)
+		}
+		if g.Line() != test.line {
+			t.Errorf("Game line is %s, but expected: %s", g.Line(), test.line)
+		}
+		visual, err := NewGameFromString(g.GameVisual())
+		if err != nil || visual.Line() != test.line {
+			t.Errorf("Game should be parsed from its visual representation, but err: %v", err)
+		}
+	}
+}
+
+func TestGeometryGameVisual(t *testing.T) {
+	g, err := NewGameFromString(mini6Visual)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	if g.Geometry() != Geometry6x6 || g.Line() != mini6Line {
+		t.Errorf("Game line is %s, but expected: %s", g.Line(), mini6Line)
+	}
+	if g.GameVisual() != mini6Visual {
+		t.Errorf("Game looks like\n%s, but expected is\n%s", g.GameVisual(), mini6Visual)
+	}
+	_, err = NewGameFromString(mini6Visual + "...|...\n")
+	if !errors.Is(err, ErrParseCellsNoNineRows) || !strings.Contains(err.Error(), "No 6 rows") {
+		t.Errorf("Game with 7 rows of 6 cells should not be created, but err: %v", err)
+	}
+}
+
+func TestGeometryValidation(t *testing.T) {
+	g, err := ParseLine(mini4Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	values, err := g.CellFreeValues(mustCell(t, Geometry4x4, "b1"))
+	if err != nil || valuesText(values) != "4" {
+		t.Errorf("Cell b1 has free values %v, but expected: 4", values)
+	}
+	cell, _ := Geometry4x4.NewSolutionCell("d1", 1)
+	if err = g.AddCell(cell); err != nil {
+		t.Errorf("Game.AddCell should add cell, but err: %v", err)
+	}
+	report, err := g.ValidationReport()
+	if err != nil || len(report.Conflicts) != 1 || report.Conflicts[0].String() != "row 1: value 1 in cells [a1 d1]" {
+		t.Errorf("Game should have conflict in row 1, but report is: %v", report)
+	}
+	standard, _ := NewCell("a2", 1)
+	if err = g.AddCell(standard); !errors.Is(err, ErrGeometryMismatch) {
+		t.Errorf("Game.AddCell should return ErrGeometryMismatch, but returned: %v", err)
+	}
+}
+
+func TestGeometryCandidates(t *testing.T) {
+	g, err := ParseLine(mini4Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	expected := "*-------------------*\n" +
+		"| 1   c4 | 234  34  |\n" +
+		"| 34  2  | 134  134 |\n" +
+		"|--------+----------|\n" +
+		"| c4  3  | 14   2   |\n" +
+		"| 24  14 | 134  134 |\n" +
+		"*-------------------*\n"
+	if visual := g.CandidateVisual(); visual != expected {
+		t.Errorf("Candidates look like\n%s, but expected is\n%s", visual, expected)
+	}
+}
+
+func mustCell(t *testing.T, g *Geometry, id string) *Cell {
+	c, err := g.NewCell(id, EmptyCellValue)
+	if err != nil {
+		t.Errorf("Cell %s should be created, but err: %v", id, err)
+	}
+	return c
+}
+
+func TestGeometryStandardOnly(t *testing.T) {
+	g, err := ParseLine(mini4Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	var buffer strings.Builder
+	for _, format := range []Format{FormatSS, FormatSDK, FormatSDX} {
+		if err = Save(&buffer, g, format); !errors.Is(err, ErrStandardOnly) {
+			t.Errorf("Save in %s format should return ErrStandardOnly, but returned: %v", format, err)
+		}
+	}
+	if _, err = g.Transform(Transpose()); !errors.Is(err, ErrStandardOnly) {
+		t.Errorf("Game.Transform should return ErrStandardOnly, but returned: %v", err)
+	}
+	if canonical, transform := Canonicalize(g); canonical != mini4Line || transform != nil {
+		t.Errorf("Canonicalize returns %s, but expected line of the game", canonical)
+	}
+}
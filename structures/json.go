@@ -0,0 +1,284 @@
+package structures
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+//Cell origins used in JSON representation.
+const (
+	OriginGiven    string = "given"
+	OriginSolution string = "solution"
+)
+
+//Errors for JSON representation.
+var (
+	ErrJSONInvalidOrigin error = errors.New("Cell origin should be given or solution")
+)
+
+//Values represents list of cell values, in JSON it is encoded as array of numbers
+// (plain []uint8 would be encoded as base64 string).
+type Values []uint8
+
+//MarshalJSON returns JSON array of values.
+func (v Values) MarshalJSON() ([]byte, error) {
+	numbers := make([]int, len(v))
+	for idx, value := range v {
+		numbers[idx] = int(value)
+	}
+	return json.Marshal(numbers)
+}
+
+type cellJSON struct {
+	ID     string `json:"id"`
+	Value  uint8  `json:"value"`
+	Origin string `json:"origin"`
+}
+
+//gameJSON is JSON schema of the game:
+//
+//	{
+//	  "size": 6,                                // grid size, omitted for standard 9x9 grid
+//	  "variant": "diagonal",                    // variant, omitted for classic game
+//	  "givens": "8..94...5....5.2..1.96.2...",  // 81 characters, only given cells
+//	  "values": "82.94...5....5.2..1.96.2...",  // 81 characters, given and solution cells
+//	  "cells": [{"id": "a1", "value": 8, "origin": "given"},
+//	            {"id": "b1", "value": 2, "origin": "solution"}],
+//	  "candidates": {"c1": [3, 7]},             // free values of empty cells
+//	  "solutionSteps": ["b1"],                  // order in which solution cells were added
+//	  "cages": [{"sum": 15, "cells": ["a1", "a2", "b1"]}]  // cages of killer sudoku, omitted when empty
+//	}
+//
+// When decoding, cells take precedence, givens and values are used only when cells
+// are missing. Cells with value 0 are empty, they are skipped. Candidates which differ from free values computed from cells are
+// restored as pencil marks.
+type gameJSON struct {
+	Size          uint8             `json:"size,omitempty"`
+	Variant       string            `json:"variant,omitempty"`
+	Givens        string            `json:"givens"`
+	Values        string            `json:"values"`
+	Cells         []cellJSON        `json:"cells"`
+	Candidates    map[string]Values `json:"candidates"`
+	SolutionSteps []string          `json:"solutionSteps"`
+	Cages         []*Cage           `json:"cages,omitempty"`
+}
+
+//MarshalJSON returns JSON representation of cell: {"id": "a1", "value": 5, "origin": "given"}.
+func (c *Cell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.toJSON())
+}
+
+//UnmarshalJSON sets cell from its JSON representation.
+func (c *Cell) UnmarshalJSON(data []byte) error {
+	var cj cellJSON
+	if err := json.Unmarshal(data, &cj); err != nil {
+		return err
+	}
+	cell, err := cj.toCell(StandardGeometry)
+	if err != nil {
+		return err
+	}
+	*c = *cell
+	return nil
+}
+
+//MarshalJSON returns JSON representation of game, see gameJSON for schema.
+func (g *Game) MarshalJSON() ([]byte, error) {
+	candidates, err := g.Candidates()
+	if err != nil {
+		return nil, err
+	}
+	gj := gameJSON{
+		Givens:        g.GivensLine(),
+		Values:        g.Line(),
+		Cells:         make([]cellJSON, 0, len(g.cells)),
+		Candidates:    make(map[string]Values, len(candidates)),
+		SolutionSteps: make([]string, 0, len(g.solutionSteps)),
+		Cages:         g.Cages(),
+	}
+	if geometry := g.Geometry(); !geometry.same(StandardGeometry) {
+		gj.Size = geometry.Size
+	}
+	if g.variant != VariantClassic {
+		gj.Variant = g.variant.String()
+	}
+	for id, values := range candidates {
+		gj.Candidates[id] = values
+	}
+	for _, c := range g.sortedCells() {
+		gj.Cells = append(gj.Cells, c.toJSON())
+	}
+	for _, id := range g.solutionSteps {
+		if _, ok := g.cells[id]; ok {
+			gj.SolutionSteps = append(gj.SolutionSteps, id)
+		}
+	}
+	return json.Marshal(gj)
+}
+
+//UnmarshalJSON sets game from its JSON representation.
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var gj gameJSON
+	if err := json.Unmarshal(data, &gj); err != nil {
+		return err
+	}
+	geometry, err := gj.geometry()
+	if err != nil {
+		return err
+	}
+	variant, err := ParseVariant(gj.Variant)
+	if err != nil {
+		return err
+	}
+	cells, err := gj.cells(geometry)
+	if err != nil {
+		return err
+	}
+	// solution cells are added in solution steps order, the rest after them.
+	position := make(map[string]int)
+	for idx, id := range gj.SolutionSteps {
+		position[id] = idx + 1
+	}
+	sort.SliceStable(cells, func(i, j int) bool {
+		pi, pj := position[cells[i].Id], position[cells[j].Id]
+		if pi == 0 || pj == 0 {
+			return pi != 0 && pj == 0
+		}
+		return pi < pj
+	})
+	game, err := NewGame(geometry, cells)
+	if err != nil {
+		return err
+	}
+	game.SetVariant(variant)
+	for _, c := range gj.Cages {
+		if err = game.AddCage(c); err != nil {
+			return err
+		}
+	}
+	if err = game.restorePencilMarks(gj.Candidates); err != nil {
+		return err
+	}
+	*g = *game
+	return nil
+}
+
+//Package private functions and methods.
+
+func (c *Cell) toJSON() cellJSON {
+	origin := OriginGiven
+	if c.SolutionCell() {
+		origin = OriginSolution
+	}
+	return cellJSON{ID: c.Id, Value: c.Value(), Origin: origin}
+}
+
+func (cj cellJSON) toCell(geometry *Geometry) (*Cell, error) {
+	switch cj.Origin {
+	case OriginGiven, "":
+		return geometry.NewCell(cj.ID, cj.Value)
+	case OriginSolution:
+		return geometry.NewSolutionCell(cj.ID, cj.Value)
+	}
+	return nil, ErrJSONInvalidOrigin
+}
+
+func (g *Game) restorePencilMarks(candidates map[string]Values) error {
+	computed, err := g.Candidates()
+	if err != nil {
+		return err
+	}
+	for id, values := range candidates {
+		free, ok := computed[id]
+		if !ok || valuesText(free) == valuesText(values) {
+			continue
+		}
+		if err = g.SetPencilMarks(id, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//geometry returns geometry of the game - by size when it is set, by length of
+// givens or values line otherwise.
+func (gj gameJSON) geometry() (*Geometry, error) {
+	switch {
+	case gj.Size != 0:
+		return GeometryForSize(int(gj.Size))
+	case gj.Givens != "":
+		return lineGeometry(gj.Givens), nil
+	case gj.Values != "":
+		return lineGeometry(gj.Values), nil
+	}
+	return StandardGeometry, nil
+}
+
+func (gj gameJSON) cells(geometry *Geometry) ([]*Cell, error) {
+	var cells []*Cell
+	if len(gj.Cells) > 0 || (gj.Givens == "" && gj.Values == "") {
+		for _, cj := range gj.Cells {
+			c, err := cj.toCell(geometry)
+			if err != nil {
+				return nil, err
+			}
+			if c.Value() != EmptyCellValue {
+				cells = append(cells, c)
+			}
+		}
+		return cells, nil
+	}
+	given := make(map[string]bool)
+	if gj.Givens != "" {
+		givens, err := parseLineCells(gj.Givens, 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range givens {
+			given[c.Id] = true
+		}
+		cells = givens
+	}
+	if gj.Values == "" {
+		return cells, nil
+	}
+	values, err := parseLineCells(gj.Values, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range values {
+		if !given[c.Id] {
+			c.solutionCell = true
+			cells = append(cells, c)
+		}
+	}
+	return cells, nil
+}
+
+//GivensLine returns given cells of the game in single line format, values of
+// solution cells are omitted.
+func (g *Game) GivensLine() string {
+	line := []byte(g.Line())
+	size := int(g.Geometry().Size)
+	for _, c := range g.cells {
+		if c.SolutionCell() {
+			line[(int(c.Row())-1)*size+int(c.Column())-1] = EmptyCellTextValue[0]
+		}
+	}
+	return string(line)
+}
+
+func (g *Game) sortedCells() []*Cell {
+	cells := make([]*Cell, 0, len(g.cells))
+	for _, c := range g.cells {
+		cells = append(cells, c)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Row() != cells[j].Row() {
+			return cells[i].Row() < cells[j].Row()
+		}
+		return cells[i].Column() < cells[j].Column()
+	})
+	return cells
+}
@@ -0,0 +1,148 @@
+package structures
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONCellRoundTrip(t *testing.T) {
+	c := createSolutionCell("b7", 5)
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Errorf("Cell should be marshaled, but err: %v", err)
+	}
+	expected := `{"id":"b7","value":5,"origin":"solution"}`
+	if string(data) != expected {
+		t.Errorf("Cell JSON is %s, but expected: %s", data, expected)
+	}
+	var decoded Cell
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Cell should be unmarshaled, but err: %v", err)
+	}
+	if decoded.String() != c.String() {
+		t.Errorf("Cell decoded as %s, but expected: %s", &decoded, c)
+	}
+	err = json.Unmarshal([]byte(`{"id":"b7","value":5,"origin":"guess"}`), &decoded)
+	if !errors.Is(err, ErrJSONInvalidOrigin) {
+		t.Errorf("Cell unmarshal should return ErrJSONInvalidOrigin, but returned: %v", err)
+	}
+	err = json.Unmarshal([]byte(`{"id":"k7","value":5}`), &decoded)
+	var idErr *CellIDError
+	if !errors.As(err, &idErr) {
+		t.Errorf("Cell unmarshal should return CellIDError, but returned: %v", err)
+	}
+}
+
+func TestJSONGameRoundTrip(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	g.AddCell(createSolutionCell("i2", 9))
+	g.AddCell(createSolutionCell("b1", 2))
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Game should be marshaled, but err: %v", err)
+	}
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	if raw["givens"] != game1Line {
+		t.Errorf("Game JSON givens are %v, but expected: %s", raw["givens"], game1Line)
+	}
+	if raw["values"] != g.Line() {
+		t.Errorf("Game JSON values are %v, but expected: %s", raw["values"], g.Line())
+	}
+	candidates := raw["candidates"].(map[string]interface{})
+	if !reflect.DeepEqual(candidates["c1"], []interface{}{3.0, 6.0, 7.0}) {
+		t.Errorf("Game JSON candidates of c1 are %v, but expected: %v", candidates["c1"], []uint8{3, 6, 7})
+	}
+	var decoded Game
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+	}
+	if decoded.Line() != g.Line() {
+		t.Errorf("Game decoded as %s, but expected: %s", decoded.Line(), g.Line())
+	}
+	if !reflect.DeepEqual(decoded.solutionSteps, []string{"i2", "b1"}) {
+		t.Errorf("Game decoded solution steps are %v, but expected: %v", decoded.solutionSteps,
+			[]string{"i2", "b1"})
+	}
+}
+
+func TestJSONGameFromLines(t *testing.T) {
+	values := "82.94...5....5.2..1.96.2...5.1.....446.....532.....8.1...4.91.7..4.6....9...17..6"
+	data := `{"givens":"` + game1Line + `","values":"` + values + `","solutionSteps":["b1"]}`
+	var g Game
+	if err := json.Unmarshal([]byte(data), &g); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+	}
+	if g.Line() != values {
+		t.Errorf("Game decoded as %s, but expected: %s", g.Line(), values)
+	}
+	if g.SolutionCellCount() != 1 {
+		t.Errorf("Game solution cells should be: %d, but is %d", 1, g.SolutionCellCount())
+	}
+}
+
+func TestJSONGameEmptyCellVisual(t *testing.T) {
+	var g Game
+	if err := json.Unmarshal([]byte(`{"cells":[{"id":"a1","value":0},{"id":"b1","value":2}]}`), &g); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+		return
+	}
+	expected := ".2.|...|..."
+	if line := g.GameVisual()[:len(expected)]; line != expected {
+		t.Errorf("Game visual starts with %s, but expected: %s", line, expected)
+	}
+	if _, err := g.Cell("a1"); err == nil || g.EmptyCellCount() != 80 {
+		t.Errorf("Cell a1 with value 0 should be empty, but game has %d empty cells", g.EmptyCellCount())
+	}
+}
+
+func TestJSONGameCages(t *testing.T) {
+	g := createKillerGame(t)
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Game should be marshaled, but err: %v", err)
+	}
+	expected := `"cages":[{"sum":3,"cells":["a1","b1"]},{"sum":24,"cells":["g9","h9","i9"]}]`
+	if !strings.Contains(string(data), expected) || !strings.Contains(string(data), `"a1":[1,2]`) {
+		t.Errorf("Game JSON should contain cages and their candidates, but is: %s", data)
+	}
+	var decoded Game
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Cages(), g.Cages()) || decoded.HasPencilMarks() {
+		t.Errorf("Game decoded with cages %v and pencil marks, but expected cages: %v", decoded.Cages(), g.Cages())
+	}
+	err = json.Unmarshal([]byte(`{"cages":[{"sum":30,"cells":["a1","b1"]}]}`), &decoded)
+	if !errors.Is(err, ErrCageSum) {
+		t.Errorf("Game unmarshal should return ErrCageSum, but returned: %v", err)
+	}
+}
+
+func TestJSONGameGeometry(t *testing.T) {
+	g, err := ParseLine(mini6Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Errorf("Game should be marshaled, but err: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"size":6,`) {
+		t.Errorf("Game JSON should start with size, but is: %s", data)
+	}
+	var decoded Game
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("Game should be unmarshaled, but err: %v", err)
+	}
+	if decoded.Geometry() != Geometry6x6 || decoded.Line() != mini6Line {
+		t.Errorf("Game decoded as %s %s, but expected: 6x6 %s", decoded.Geometry(), decoded.Line(), mini6Line)
+	}
+}
@@ -0,0 +1,206 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+//Errors for game parser.
+var (
+	ErrParseCellsNoNineRows  error = errors.New("No 9 rows in game input")
+	ErrParseRowNoNineCells   error = errors.New("Row has not 9 cells")
+	ErrParseInvalidCharacter error = errors.New("Invalid character in game input")
+	ErrParseInvalidCell      error = errors.New("Invalid format of cell, allowed a1=5 o or a1=5 x")
+	ErrParseLineLength       error = errors.New("Game line has not 81 characters")
+)
+
+//LineLength is length of standard 9x9 game in single line format.
+const LineLength int = 81
+
+//Characters allowed in game input beside cell values.
+const (
+	columnSeparators    string = "| \t"
+	separatorLineChars  string = "-+=*| \t"
+	alternateEmptyValue rune   = '0'
+)
+
+//ParseLine creates Game object from single line format - row by row, digits
+// 1-9 (and letters A-G for values 10-16) for filled cells and . or 0 for empty
+// cells. Grid size is given by line length: 16, 36, 81, 144 or 256 characters.
+func ParseLine(text string) (*Game, error) {
+	text = strings.TrimSpace(text)
+	cells, err := parseLineCells(text, 1)
+	if err != nil {
+		return nil, err
+	}
+	return NewGame(lineGeometry(text), cells)
+}
+
+// Package private functions.
+
+//lineGeometry returns geometry of game in single line format, standard one
+// when line length does not match any geometry.
+func lineGeometry(text string) *Geometry {
+	if g := geometryForLineLength(utf8.RuneCountInString(text)); g != nil {
+		return g
+	}
+	return StandardGeometry
+}
+
+//parseLineCells parses cells of game in single line format, lineNumber is used for error positions.
+func parseLineCells(text string, lineNumber int) ([]*Cell, error) {
+	geometry := lineGeometry(text)
+	size := int(geometry.Size)
+	var cells []*Cell
+	position := 0
+	for _, char := range text {
+		if position == geometry.Cells() {
+			return nil, newParseError(lineNumber, position+1, ErrParseLineLength)
+		}
+		value, ok := geometry.ParseValue(char)
+		if !ok {
+			return nil, &ParseError{Line: lineNumber, Column: position + 1,
+				Reason: fmt.Sprintf("%s: %q", ErrParseInvalidCharacter, char), Err: ErrParseInvalidCharacter}
+		}
+		if value != EmptyCellValue {
+			c, err := geometry.NewCell(geometry.CellID(uint8(position/size+1), uint8(position%size+1)), value)
+			if err != nil {
+				return nil, err
+			}
+			cells = append(cells, c)
+		}
+		position++
+	}
+	if position != geometry.Cells() {
+		return nil, newParseError(lineNumber, position+1, ErrParseLineLength)
+	}
+	return cells, nil
+}
+
+//parseCells parses game in grid format, one row per line. Rows may contain |
+// separators and whitespace, horizontal separator lines (---+---+---) and blank
+// lines are skipped. Empty cells are written as . or 0. Grid size is given by
+// count of cells in the first row (standard 9x9 grid when it is not supported size).
+func parseCells(textCells string) ([]*Cell, *Geometry, error) {
+	lines := strings.Split(textCells, "\n")
+	var cells, tmp []*Cell
+	var err error
+	var geometry *Geometry
+	rowIndex := uint8(0)
+	for index, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if isSkippedLine(line) {
+			continue
+		}
+		if geometry == nil {
+			geometry = rowGeometry(line)
+		}
+		rowIndex++
+		if rowIndex > geometry.Size {
+			return nil, nil, rowsError(index+1, geometry)
+		}
+		tmp, err = parseRow(line, rowIndex, index+1, geometry)
+		if err != nil {
+			return nil, nil, err
+		}
+		cells = append(cells, tmp...)
+	}
+	if geometry == nil {
+		geometry = StandardGeometry
+	}
+	if rowIndex != geometry.Size {
+		return nil, nil, rowsError(len(lines), geometry)
+	}
+	return cells, geometry, nil
+}
+
+//rowGeometry returns geometry of grid with the row, standard one when count of
+// cells in row does not match any geometry.
+func rowGeometry(line string) *Geometry {
+	count := 0
+	for _, char := range line {
+		if !strings.ContainsRune(columnSeparators, char) {
+			count++
+		}
+	}
+	if g, err := GeometryForSize(count); err == nil {
+		return g
+	}
+	return StandardGeometry
+}
+
+//rowsError returns error of grid with wrong count of rows.
+func rowsError(line int, geometry *Geometry) *ParseError {
+	err := newParseError(line, 0, ErrParseCellsNoNineRows)
+	if geometry != StandardGeometry {
+		err.Reason = fmt.Sprintf("No %d rows in game input", geometry.Size)
+	}
+	return err
+}
+
+//isSkippedLine returns if line is blank or horizontal separator line.
+func isSkippedLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return true
+	}
+	if !strings.ContainsAny(trimmed, "-=") {
+		return false
+	}
+	return strings.Trim(trimmed, separatorLineChars) == ""
+}
+
+//parseRow parses one row of game, lineNumber is used for error positions.
+func parseRow(line string, rowIndex uint8, lineNumber int, geometry *Geometry) ([]*Cell, error) {
+	var cells []*Cell
+	columnIndex := uint8(0)
+	column := 0
+	for _, char := range line {
+		column++
+		if strings.ContainsRune(columnSeparators, char) {
+			continue
+		}
+		columnIndex++
+		if columnIndex > geometry.Size {
+			return nil, cellsError(lineNumber, column, geometry)
+		}
+		value, ok := geometry.ParseValue(char)
+		if !ok {
+			return nil, &ParseError{Line: lineNumber, Column: column,
+				Reason: fmt.Sprintf("%s: %q", ErrParseInvalidCharacter, char), Err: ErrParseInvalidCharacter}
+		}
+		if value == EmptyCellValue {
+			continue
+		}
+		c, err := geometry.NewCell(geometry.CellID(rowIndex, columnIndex), value)
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, c)
+	}
+	if columnIndex != geometry.Size {
+		return nil, cellsError(lineNumber, column+1, geometry)
+	}
+	return cells, nil
+}
+
+//cellsError returns error of row with wrong count of cells.
+func cellsError(line int, column int, geometry *Geometry) *ParseError {
+	err := newParseError(line, column, ErrParseRowNoNineCells)
+	if geometry != StandardGeometry {
+		err.Reason = fmt.Sprintf("Row has not %d cells", geometry.Size)
+	}
+	return err
+}
+
+//parseCellValue converts one character to value of standard grid, . and 0 mean empty cell.
+func parseCellValue(char rune) (uint8, bool) {
+	return StandardGeometry.ParseValue(char)
+}
+
+//columnID returns text column index (a-p) for column index 1-16.
+func columnID(column uint8) string {
+	return string(rune('a' + column - 1))
+}
@@ -0,0 +1,122 @@
+package structures
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParserTolerantInput(t *testing.T) {
+	inputs := []string{
+		"8..|94.|..5\n...|.5.|2..\n1.9|6.2|...\n5.1|...|..4\n46.|...|.53\n2..|...|8.1\n...|4.9|1.7\n..4|.6.|...\n9..|.17|..6\n\n",
+		"\n8 0 0 | 9 4 0 | 0 0 5\n0 0 0 | 0 5 0 | 2 0 0\n1 0 9 | 6 0 2 | 0 0 0\n------+-------+------\n" +
+			"5 0 1 | 0 0 0 | 0 0 4\n4 6 0 | 0 0 0 | 0 5 3\n2 0 0 | 0 0 0 | 8 0 1\n------+-------+------\n" +
+			"0 0 0 | 4 0 9 | 1 0 7\n0 0 4 | 0 6 0 | 0 0 0\n9 0 0 | 0 1 7 | 0 0 6\n",
+		"8..94...5\r\n....5.2..\r\n1.96.2...\r\n5.1.....4\r\n46.....53\r\n2.....8.1\r\n...4.91.7\r\n..4.6....\r\n9...17..6",
+	}
+	expected, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	for _, input := range inputs {
+		g, err := NewGameFromString(input)
+		if err != nil {
+			t.Errorf("Game should be succesfully created from %q, but err: %v", input, err)
+			continue
+		}
+		if g.GameVisual() != expected.GameVisual() {
+			t.Errorf("Game looks like\n%s, but expected is\n%s", g.GameVisual(), expected.GameVisual())
+		}
+	}
+}
+
+func TestParserErrorPositions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErr    error
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"8..|94.|..5\n", ErrParseCellsNoNineRows, 2, 0},
+		{"8..|94.|..5\n---+---+---\n...|.5.|2a.", ErrParseInvalidCharacter, 3, 10},
+		{"8..|94.|..5\n...|.5.|2.", ErrParseRowNoNineCells, 2, 11},
+		{"8..|94.|..5\n...|.5.|2...", ErrParseRowNoNineCells, 2, 12},
+		{"..5|.1.|6..\n39.|7..|.1.\n4.7|9..|.28\n97.|8..|.4.\n5..|2.6|..1\n.8.|..1|.73\n26.|..8|7.4\n.5.|..7|.39\n..8|.4.|1..\n..8|.4.|1..",
+			ErrParseCellsNoNineRows, 10, 0},
+	}
+	for _, test := range tests {
+		_, err := NewGameFromString(test.input)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("NewGameFromString should return ParseError, but returned: %v", err)
+			continue
+		}
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("NewGameFromString returns error: %v, but expected: %v", err, test.expectedErr)
+		}
+		if parseErr.Line != test.expectedLine || parseErr.Column != test.expectedColumn {
+			t.Errorf("ParseError position is %d:%d, but expected: %d:%d", parseErr.Line,
+				parseErr.Column, test.expectedLine, test.expectedColumn)
+		}
+	}
+}
+
+func TestParserInvalidCellString(t *testing.T) {
+	inputs := []string{"", "a1", "a1=", "a1-5 x", "a1=y x", "a1=5 z", "a1=5x"}
+	for _, input := range inputs {
+		_, err := NewCellFromString(input)
+		if !errors.Is(err, ErrParseInvalidCell) {
+			t.Errorf("NewCellFromString(%q) should return ErrParseInvalidCell, but returned: %v", input, err)
+		}
+	}
+	c, err := NewCellFromString(" b2=0 ")
+	if err != nil {
+		t.Errorf("NewCellFromString should create cell, but err: %v", err)
+		return
+	}
+	if c.Value() != EmptyCellValue || c.SolutionCell() {
+		t.Errorf("NewCellFromString creates cell %s, but expected b2=0 o", c)
+	}
+}
+
+const game1Line string = "8..94...5....5.2..1.96.2...5.1.....446.....532.....8.1...4.91.7..4.6....9...17..6"
+
+func TestParserLineRoundTrip(t *testing.T) {
+	inputs := []string{game1Line, "  " + game1Line + "\n",
+		"800940005000050200109602000501000004460000053200000801000409107004060000900017006"}
+	for _, input := range inputs {
+		g, err := ParseLine(input)
+		if err != nil {
+			t.Errorf("ParseLine should create game from %q, but err: %v", input, err)
+			continue
+		}
+		if g.FilledCellCount() != 30 {
+			t.Errorf("Game have filled cells: %d, but expected number: %d", g.FilledCellCount(), 30)
+		}
+		if g.Line() != game1Line {
+			t.Errorf("Game line is %s, but expected: %s", g.Line(), game1Line)
+		}
+	}
+}
+
+func TestParserLineErrors(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErr    error
+		expectedColumn int
+	}{
+		{game1Line[:80], ErrParseLineLength, 81},
+		{game1Line + "1", ErrParseLineLength, 82},
+		{"8.x" + game1Line[3:], ErrParseInvalidCharacter, 3},
+	}
+	for _, test := range tests {
+		_, err := ParseLine(test.input)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, test.expectedErr) {
+			t.Errorf("ParseLine should return %v, but returned: %v", test.expectedErr, err)
+			continue
+		}
+		if parseErr.Column != test.expectedColumn {
+			t.Errorf("ParseError column is %d, but expected: %d", parseErr.Column, test.expectedColumn)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package structures
+
+import (
+	"math/rand"
+)
+
+//Transpose returns transform which swaps rows and columns of the grid.
+func Transpose() *Transform {
+	t := IdentityTransform()
+	t.Transpose = true
+	return t
+}
+
+//Rotate returns transform which rotates the grid clockwise by quarter turns,
+// negative count rotates counterclockwise.
+func Rotate(quarterTurns int) *Transform {
+	switch (quarterTurns%4 + 4) % 4 {
+	case 1:
+		return Transpose().Then(MirrorHorizontal())
+	case 2:
+		return MirrorHorizontal().Then(MirrorVertical())
+	case 3:
+		return Transpose().Then(MirrorVertical())
+	}
+	return IdentityTransform()
+}
+
+//MirrorHorizontal returns transform which mirrors the grid by vertical axis,
+// column a is swapped with column i.
+func MirrorHorizontal() *Transform {
+	t := IdentityTransform()
+	t.Columns = reversed()
+	return t
+}
+
+//MirrorVertical returns transform which mirrors the grid by horizontal axis,
+// row 1 is swapped with row 9.
+func MirrorVertical() *Transform {
+	t := IdentityTransform()
+	t.Rows = reversed()
+	return t
+}
+
+//PermuteDigits returns transform which replaces every digit d by digits[d-1],
+// digits must be permutation of 1 - 9.
+func PermuteDigits(digits [9]uint8) (*Transform, error) {
+	t := IdentityTransform()
+	copy(t.Digits[1:], digits[:])
+	if !t.Valid() {
+		return nil, ErrInvalidTransform
+	}
+	return t, nil
+}
+
+//SwapRows returns transform which swaps two rows (0 - 8) of the same band.
+func SwapRows(a uint8, b uint8) (*Transform, error) {
+	t := IdentityTransform()
+	if err := swap(&t.Rows, a, b, 1); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+//SwapColumns returns transform which swaps two columns (0 - 8) of the same stack.
+func SwapColumns(a uint8, b uint8) (*Transform, error) {
+	t := IdentityTransform()
+	if err := swap(&t.Columns, a, b, 1); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+//SwapBands returns transform which swaps two bands (0 - 2) of rows.
+func SwapBands(a uint8, b uint8) (*Transform, error) {
+	t := IdentityTransform()
+	if err := swap(&t.Rows, a, b, 3); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+//SwapStacks returns transform which swaps two stacks (0 - 2) of columns.
+func SwapStacks(a uint8, b uint8) (*Transform, error) {
+	t := IdentityTransform()
+	if err := swap(&t.Columns, a, b, 3); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+//RandomTransform returns transform chosen uniformly from all transforms.
+func RandomTransform(r *rand.Rand) *Transform {
+	permutations := bandPermutations()
+	t := Transform{Transpose: r.Intn(2) == 1, Rows: permutations[r.Intn(len(permutations))],
+		Columns: permutations[r.Intn(len(permutations))]}
+	for idx, d := range r.Perm(9) {
+		t.Digits[idx+1] = uint8(d + 1)
+	}
+	return &t
+}
+
+//Then method returns transform which applies t and then next, invalid t is
+// returned unchanged.
+func (t *Transform) Then(next *Transform) *Transform {
+	if !t.Valid() {
+		return t
+	}
+	if !next.Valid() {
+		return next
+	}
+	result := Transform{Transpose: t.Transpose != next.Transpose}
+	rows, columns := t.Rows, t.Columns
+	if next.Transpose {
+		rows, columns = columns, rows
+	}
+	for idx := 0; idx < 9; idx++ {
+		result.Rows[idx] = rows[next.Rows[idx]]
+		result.Columns[idx] = columns[next.Columns[idx]]
+	}
+	for d := range t.Digits {
+		result.Digits[d] = next.Digits[t.Digits[d]]
+	}
+	return &result
+}
+
+//Inverse method returns transform which reverts t, invalid t is returned unchanged.
+func (t *Transform) Inverse() *Transform {
+	if !t.Valid() {
+		return t
+	}
+	result := Transform{Transpose: t.Transpose}
+	for idx := uint8(0); idx < 9; idx++ {
+		result.Rows[t.Rows[idx]] = idx
+		result.Columns[t.Columns[idx]] = idx
+	}
+	if t.Transpose {
+		result.Rows, result.Columns = result.Columns, result.Rows
+	}
+	for d := uint8(0); d < 10; d++ {
+		result.Digits[t.Digits[d]] = d
+	}
+	return &result
+}
+
+//Transform method returns copy of the game transformed by t, see Transform.Apply.
+func (g *Game) Transform(t *Transform) (*Game, error) {
+	return t.Apply(g)
+}
+
+//swap swaps items a and b of permutation p, items are groups of size rows
+// (1 for rows, 3 for bands). Swapped rows must be in the same band.
+func swap(p *[9]uint8, a uint8, b uint8, size uint8) error {
+	if a*size >= 9 || b*size >= 9 || (size == 1 && a/3 != b/3) {
+		return ErrInvalidTransform
+	}
+	for idx := uint8(0); idx < size; idx++ {
+		p[a*size+idx], p[b*size+idx] = p[b*size+idx], p[a*size+idx]
+	}
+	return nil
+}
+
+//reversed returns permutation which reverses order of rows.
+func reversed() [9]uint8 {
+	var p [9]uint8
+	for idx := uint8(0); idx < 9; idx++ {
+		p[idx] = 8 - idx
+	}
+	return p
+}
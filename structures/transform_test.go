@@ -0,0 +1,103 @@
+package structures
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTransformOperations(t *testing.T) {
+	g, err := ParseLine(easyLine)
+	if err != nil {
+		t.Errorf("Game should be parsed, but err: %v", err)
+		return
+	}
+	swapRows, _ := SwapRows(3, 5)
+	swapColumns, _ := SwapColumns(8, 6)
+	swapBands, _ := SwapBands(0, 2)
+	swapStacks, _ := SwapStacks(1, 0)
+	digits, _ := PermuteDigits([9]uint8{9, 8, 7, 6, 5, 4, 3, 2, 1})
+	// c1 = 5, e1 = 1 and a2 = 3 in the game.
+	tests := []struct {
+		name      string
+		transform *Transform
+		cells     map[string]uint8
+	}{
+		{"transpose", Transpose(), map[string]uint8{"a3": 5, "a5": 1, "b1": 3}},
+		{"rotate", Rotate(1), map[string]uint8{"i3": 5, "i5": 1, "h1": 3}},
+		{"rotate twice", Rotate(2), map[string]uint8{"g9": 5, "e9": 1, "i8": 3}},
+		{"rotate back", Rotate(-1), map[string]uint8{"a7": 5, "a5": 1, "b9": 3}},
+		{"mirror horizontal", MirrorHorizontal(), map[string]uint8{"g1": 5, "e1": 1, "i2": 3}},
+		{"mirror vertical", MirrorVertical(), map[string]uint8{"c9": 5, "e9": 1, "a8": 3}},
+		{"swap rows", swapRows, map[string]uint8{"c1": 5, "a6": 9, "b4": 8}},
+		{"swap columns", swapColumns, map[string]uint8{"i1": 6, "g3": 8}},
+		{"swap bands", swapBands, map[string]uint8{"c7": 5, "e7": 1, "a8": 3}},
+		{"swap stacks", swapStacks, map[string]uint8{"f1": 5, "b1": 1, "d2": 3}},
+		{"permute digits", digits, map[string]uint8{"c1": 5, "e1": 9, "a2": 7}},
+	}
+	for _, test := range tests {
+		transformed, err := g.Transform(test.transform)
+		if err != nil {
+			t.Errorf("Game should be transformed by %s, but err: %v", test.name, err)
+			continue
+		}
+		for id, value := range test.cells {
+			if c, err := transformed.Cell(id); err != nil || c.Value() != value {
+				t.Errorf("Cell %s should have value %d after %s, but is: %v (err: %v)", id, value, test.name, c, err)
+			}
+		}
+		back, _ := transformed.Transform(test.transform.Inverse())
+		if back.Line() != easyLine {
+			t.Errorf("Inverse of %s should return game back, but game is: %s", test.name, back.Line())
+		}
+	}
+}
+
+func TestTransformInvalidOperations(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"rows of other bands", func() error { _, err := SwapRows(2, 3); return err }()},
+		{"row out of grid", func() error { _, err := SwapRows(9, 9); return err }()},
+		{"columns of other stacks", func() error { _, err := SwapColumns(0, 8); return err }()},
+		{"band out of grid", func() error { _, err := SwapBands(0, 3); return err }()},
+		{"stack out of grid", func() error { _, err := SwapStacks(3, 1); return err }()},
+		{"duplicated digit", func() error { _, err := PermuteDigits([9]uint8{1, 1, 3, 4, 5, 6, 7, 8, 9}); return err }()},
+	}
+	for _, test := range tests {
+		if test.err != ErrInvalidTransform {
+			t.Errorf("Transform swapping %s should not be created, but err: %v", test.name, test.err)
+		}
+	}
+	invalid := IdentityTransform()
+	invalid.Rows[0] = 1
+	if invalid.Then(Rotate(1)).Valid() || Rotate(1).Then(invalid).Valid() || invalid.Inverse().Valid() {
+		t.Errorf("Composition and inverse of invalid transform should be invalid")
+	}
+}
+
+func TestTransformComposition(t *testing.T) {
+	g, _ := ParseLine(easyLine)
+	r := rand.New(rand.NewSource(1))
+	if *Rotate(1).Then(Rotate(1)).Then(Rotate(2)) != *IdentityTransform() || *Rotate(4) != *IdentityTransform() {
+		t.Errorf("Four quarter turns should be identity")
+	}
+	for i := 0; i < 20; i++ {
+		first, second := RandomTransform(r), RandomTransform(r)
+		if !first.Valid() {
+			t.Errorf("Random transform should be valid, but is: %+v", first)
+		}
+		composed, _ := g.Transform(first.Then(second))
+		step, _ := g.Transform(first)
+		step, _ = step.Transform(second)
+		if composed.Line() != step.Line() {
+			t.Errorf("Composed transform should give %s, but gives: %s", step.Line(), composed.Line())
+		}
+		if *first.Then(first.Inverse()) != *IdentityTransform() || *first.Inverse().Then(first) != *IdentityTransform() {
+			t.Errorf("Transform composed with its inverse should be identity, transform: %+v", first)
+		}
+		if !AreEquivalent(g, composed) {
+			t.Errorf("Transformed game should be equivalent to original one, but is: %s", composed.Line())
+		}
+	}
+}
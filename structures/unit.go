@@ -0,0 +1,212 @@
+package structures
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Errors for game variants.
+var (
+	ErrUnknownVariant   error = errors.New("Variant should be classic, diagonal, hyper or diagonal+hyper")
+	ErrVariantTransform error = errors.New("Transform does not keep units of game variant")
+)
+
+//Variant represents units which game has on top of rows, columns and squares,
+// variants can be combined, ex. VariantDiagonal | VariantHyper.
+type Variant uint8
+
+//Game variants.
+const (
+	VariantClassic Variant = 0
+	//VariantDiagonal is Sudoku-X, both main diagonals contain all values.
+	VariantDiagonal Variant = 1
+	//VariantHyper is Windoku, windows between squares contain all values.
+	VariantHyper Variant = 2
+)
+
+//Names of variants, aliases are accepted by ParseVariant.
+const (
+	variantClassicName  string = "classic"
+	variantDiagonalName string = "diagonal"
+	variantHyperName    string = "hyper"
+	variantSep          string = "+"
+)
+
+var variantAliases = map[string]Variant{
+	variantClassicName:  VariantClassic,
+	variantDiagonalName: VariantDiagonal,
+	"x":                 VariantDiagonal,
+	"sudoku-x":          VariantDiagonal,
+	variantHyperName:    VariantHyper,
+	"windoku":           VariantHyper,
+}
+
+//String returns name of variant, ex. diagonal+hyper.
+func (v Variant) String() string {
+	var names []string
+	if v&VariantDiagonal != 0 {
+		names = append(names, variantDiagonalName)
+	}
+	if v&VariantHyper != 0 {
+		names = append(names, variantHyperName)
+	}
+	if len(names) == 0 {
+		return variantClassicName
+	}
+	return strings.Join(names, variantSep)
+}
+
+//ParseVariant returns variant from its name, names of combined variants are
+// separated by + or comma, ex. x+windoku. Empty name is classic variant.
+func ParseVariant(name string) (Variant, error) {
+	var v Variant
+	for _, part := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '+' || r == ','
+	}) {
+		found, ok := variantAliases[strings.TrimSpace(part)]
+		if !ok {
+			return VariantClassic, ErrUnknownVariant
+		}
+		v |= found
+	}
+	return v, nil
+}
+
+//Unit represents cells of the game which must contain distinct values. Units
+// of the grid (all types except cage) contain every value exactly once.
+type Unit struct {
+	Type  UnitType
+	Index uint8
+	Cells []string
+}
+
+//Name returns human readable name of unit, ex. row 1, column a or window 2.
+func (u Unit) Name() string {
+	if u.Type == UnitColumn {
+		return fmt.Sprintf("%s %s", u.Type, columnID(u.Index))
+	}
+	return fmt.Sprintf("%s %d", u.Type, u.Index)
+}
+
+//Units returns units of the grid with variant - rows, columns and squares
+// followed by diagonals and windows. Cells of every unit are ordered by rows.
+func (g *Geometry) Units(v Variant) []Unit {
+	n := g.Size
+	units := make([]Unit, 0, 3*int(n)+2)
+	for _, unitType := range []UnitType{UnitRow, UnitColumn, UnitSquare} {
+		for idx := uint8(1); idx <= n; idx++ {
+			units = append(units, Unit{Type: unitType, Index: idx})
+		}
+	}
+	for r := uint8(1); r <= n; r++ {
+		for c := uint8(1); c <= n; c++ {
+			id := g.CellID(r, c)
+			units[r-1].Cells = append(units[r-1].Cells, id)
+			units[n+c-1].Cells = append(units[n+c-1].Cells, id)
+			box := 2*n + g.Box(r, c) - 1
+			units[box].Cells = append(units[box].Cells, id)
+		}
+	}
+	if v&VariantDiagonal != 0 {
+		main, anti := Unit{Type: UnitDiagonal, Index: 1}, Unit{Type: UnitDiagonal, Index: 2}
+		for r := uint8(1); r <= n; r++ {
+			main.Cells = append(main.Cells, g.CellID(r, r))
+			anti.Cells = append(anti.Cells, g.CellID(r, n+1-r))
+		}
+		units = append(units, main, anti)
+	}
+	if v&VariantHyper != 0 {
+		units = append(units, g.windows()...)
+	}
+	return units
+}
+
+//Variant returns variant of the game, classic game has no other units than
+// rows, columns and squares.
+func (g *Game) Variant() Variant {
+	return g.variant
+}
+
+//SetVariant method sets variant of the game, its units are used by validation
+// and free values of cells.
+func (g *Game) SetVariant(v Variant) {
+	g.variant = v
+}
+
+//Units returns units of the game grid with its variant, cages are not included.
+func (g *Game) Units() []Unit {
+	return g.Geometry().Units(g.variant)
+}
+
+//Package private functions and methods.
+
+//windows returns windows of Hyper sudoku - squares separated from the grid
+// border and from each other by one row and one column (four windows of 9x9
+// grid with top left cells b2, f2, b6 and f6).
+func (g *Geometry) windows() []Unit {
+	var result []Unit
+	for top := uint8(2); top+g.BoxRows-1 <= g.Size; top += g.BoxRows + 1 {
+		for left := uint8(2); left+g.BoxColumns-1 <= g.Size; left += g.BoxColumns + 1 {
+			window := Unit{Type: UnitWindow, Index: uint8(len(result) + 1)}
+			for r := top; r < top+g.BoxRows; r++ {
+				for c := left; c < left+g.BoxColumns; c++ {
+					window.Cells = append(window.Cells, g.CellID(r, c))
+				}
+			}
+			result = append(result, window)
+		}
+	}
+	return result
+}
+
+//unitConflicts method returns values presented more times in units of the game.
+func (g *Game) unitConflicts() ([]Conflict, error) {
+	var conflicts []Conflict
+	for _, u := range g.Units() {
+		valueCells := make(map[uint8][]string)
+		for _, id := range u.Cells {
+			if c, ok := g.cells[id]; ok && c.Value() != EmptyCellValue {
+				valueCells[c.Value()] = append(valueCells[c.Value()], id)
+			}
+		}
+		for value := uint8(1); value <= g.Geometry().Size; value++ {
+			ids := valueCells[value]
+			if len(ids) < 2 {
+				continue
+			}
+			sort.Strings(ids)
+			conflicts = append(conflicts, Conflict{Unit: u.Type, Index: u.Index, Value: value, CellIds: ids})
+		}
+	}
+	return conflicts, nil
+}
+
+//unitValues method returns sorted values of filled cells which share some unit
+// with the cell.
+func (g *Game) unitValues(cell *Cell) []uint8 {
+	var result []uint8
+	for _, u := range g.Units() {
+		if !containsID(u.Cells, cell.Id) {
+			continue
+		}
+		for _, id := range u.Cells {
+			if c, ok := g.cells[id]; ok {
+				result = append(result, c.Value())
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+//containsID returns if ids contain id.
+func containsID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package structures
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVariant(t *testing.T) {
+	tests := map[string]Variant{
+		"":                VariantClassic,
+		"classic":         VariantClassic,
+		"X":               VariantDiagonal,
+		" windoku ":       VariantHyper,
+		"diagonal+hyper":  VariantDiagonal | VariantHyper,
+		"hyper, sudoku-x": VariantDiagonal | VariantHyper,
+	}
+	for name, expected := range tests {
+		v, err := ParseVariant(name)
+		if err != nil || v != expected {
+			t.Errorf("ParseVariant(%q) returns %s (err: %v), but expected: %s", name, v, err, expected)
+		}
+		if parsed, _ := ParseVariant(v.String()); parsed != v {
+			t.Errorf("Variant %s should be parsed from its name, but is: %s", v, parsed)
+		}
+	}
+	if _, err := ParseVariant("jigsaw"); !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("ParseVariant should return ErrUnknownVariant, but returned: %v", err)
+	}
+}
+
+func TestGeometryUnits(t *testing.T) {
+	units := StandardGeometry.Units(VariantDiagonal | VariantHyper)
+	if len(units) != 33 {
+		t.Errorf("9x9 grid has %d units of diagonal+hyper variant, but expected: 33", len(units))
+		return
+	}
+	tests := []struct {
+		unit  Unit
+		name  string
+		cells string
+	}{
+		{units[0], "row 1", "a1 b1 c1 d1 e1 f1 g1 h1 i1"},
+		{units[10], "column b", "b1 b2 b3 b4 b5 b6 b7 b8 b9"},
+		{units[22], "square 5", "d4 e4 f4 d5 e5 f5 d6 e6 f6"},
+		{units[28], "diagonal 2", "i1 h2 g3 f4 e5 d6 c7 b8 a9"},
+		{units[29], "window 1", "b2 c2 d2 b3 c3 d3 b4 c4 d4"},
+		{units[32], "window 4", "f6 g6 h6 f7 g7 h7 f8 g8 h8"},
+	}
+	for _, test := range tests {
+		if test.unit.Name() != test.name || strings.Join(test.unit.Cells, " ") != test.cells {
+			t.Errorf("Unit is %s with cells %v, but expected: %s with cells %s", test.unit.Name(),
+				test.unit.Cells, test.name, test.cells)
+		}
+	}
+	windows := map[*Geometry]int{Geometry4x4: 1, Geometry6x6: 2, Geometry12x12: 6, Geometry16x16: 9}
+	for geometry, expected := range windows {
+		if count := len(geometry.Units(VariantHyper)) - 3*int(geometry.Size); count != expected {
+			t.Errorf("%s grid has %d windows, but expected: %d", geometry, count, expected)
+		}
+	}
+}
+
+func TestVariantValidation(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	g.SetVariant(VariantDiagonal)
+	values, err := g.CellFreeValues(mustCell(t, StandardGeometry, "e5"))
+	if err != nil || valuesText(values) != "27" {
+		t.Errorf("Cell e5 has free values %v (err: %v), but expected: 27", values, err)
+	}
+	if err = g.AddStringCell("e5=8 x"); err != nil {
+		t.Errorf("Solution cell should be added, but err: %v", err)
+	}
+	rowOk, colOk, squareOk, err := g.Validate()
+	if err != nil || !*rowOk || !*colOk || *squareOk {
+		t.Errorf("Game validation should find conflict only in diagonal, but err: %v", err)
+	}
+	report, err := g.ValidationReport()
+	if err != nil {
+		t.Errorf("Game.ValidationReport should pass, but err: %v", err)
+		return
+	}
+	expected := []Conflict{{Unit: UnitDiagonal, Index: 1, Value: 8, CellIds: []string{"a1", "e5"}}}
+	if !reflect.DeepEqual(report.Conflicts, expected) || report.UnitOk(UnitDiagonal) {
+		t.Errorf("Game has conflicts %v, but expected: %v", report.Conflicts, expected)
+	}
+	if g.Clone().Variant() != VariantDiagonal {
+		t.Errorf("Clone of the game should keep its variant")
+	}
+}
+
+func TestVariantFormats(t *testing.T) {
+	g, err := ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	g.SetVariant(VariantDiagonal | VariantHyper)
+	var buffer strings.Builder
+	if err = Save(&buffer, g, FormatKiller); err != nil {
+		t.Errorf("Save in killer format should pass, but err: %v", err)
+	}
+	expected := game1Line + "\nvariant: diagonal+hyper\n"
+	if buffer.String() != expected {
+		t.Errorf("Game is written as\n%s, but expected:\n%s", buffer.String(), expected)
+	}
+	if format := DetectFormat([]byte(expected)); format != FormatKiller {
+		t.Errorf("Game with variant should be detected as killer format, but format is: %s", format)
+	}
+	loaded, err := Load(strings.NewReader(expected))
+	if err != nil || loaded.Variant() != g.Variant() || loaded.Line() != game1Line {
+		t.Errorf("Game should be loaded with its variant, but err: %v", err)
+	}
+	data, err := json.Marshal(g)
+	if err != nil || !strings.Contains(string(data), `"variant":"diagonal+hyper"`) {
+		t.Errorf("Game should be marshaled with its variant, but err: %v", err)
+	}
+	var decoded Game
+	if err = json.Unmarshal(data, &decoded); err != nil || decoded.Variant() != g.Variant() {
+		t.Errorf("Game should be unmarshaled with its variant, but err: %v", err)
+	}
+	_, err = ReadKiller(strings.NewReader("variant: jigsaw\n"))
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 1 || !errors.Is(err, ErrUnknownVariant) {
+		t.Errorf("ReadKiller should return ErrUnknownVariant on line 1, but returned: %v", err)
+	}
+}
+
+func TestVariantTransform(t *testing.T) {
+	g, err := ParseLine(game1Line)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+		return
+	}
+	g.SetVariant(VariantDiagonal | VariantHyper)
+	rotated, err := g.Transform(Rotate(1))
+	if err != nil || rotated.Variant() != g.Variant() {
+		t.Errorf("Rotation should keep units of the variant, but err: %v", err)
+	}
+	swap, _ := SwapRows(0, 1)
+	if _, err = g.Transform(swap); !errors.Is(err, ErrVariantTransform) {
+		t.Errorf("Game.Transform should return ErrVariantTransform, but returned: %v", err)
+	}
+	if canonical, transform := Canonicalize(g); canonical != g.killerText() || transform != nil {
+		t.Errorf("Canonicalize returns %s, but expected game in killer format", canonical)
+	}
+}
@@ -0,0 +1,94 @@
+package structures
+
+import (
+	"fmt"
+	"sort"
+)
+
+//UnitType represents type of game unit (row, column, square, cage, diagonal
+// or window).
+type UnitType uint8
+
+//Unit types of the game.
+const (
+	UnitRow UnitType = iota + 1
+	UnitColumn
+	UnitSquare
+	UnitCage
+	UnitDiagonal
+	UnitWindow
+)
+
+//String returns text representation of unit type.
+func (u UnitType) String() string {
+	switch u {
+	case UnitRow:
+		return "row"
+	case UnitColumn:
+		return "column"
+	case UnitSquare:
+		return "square"
+	case UnitCage:
+		return "cage"
+	case UnitDiagonal:
+		return "diagonal"
+	case UnitWindow:
+		return "window"
+	}
+	return "unknown"
+}
+
+//Conflict represents one value presented more times in one unit. Conflict of
+// cage with Value 0 means that values of its cells can not give cage Sum.
+type Conflict struct {
+	Unit    UnitType
+	Index   uint8
+	Value   uint8
+	Sum     int
+	CellIds []string
+}
+
+//String returns text representation of conflict.
+func (c Conflict) String() string {
+	if c.Unit == UnitCage && c.Value == 0 {
+		return fmt.Sprintf("%s %d: cells %v can not give sum %d", c.Unit, c.Index, c.CellIds, c.Sum)
+	}
+	return fmt.Sprintf("%s %d: value %d in cells %v", c.Unit, c.Index, c.Value, c.CellIds)
+}
+
+//ValidationReport represents detailed result of game validation.
+type ValidationReport struct {
+	Conflicts       []Conflict
+	EmptyCandidates []string
+}
+
+//IsValid returns if report contains no conflict and no cell without free values.
+func (r *ValidationReport) IsValid() bool {
+	return len(r.Conflicts) == 0 && len(r.EmptyCandidates) == 0
+}
+
+//UnitOk returns if there is no conflict in units of given type.
+func (r *ValidationReport) UnitOk(unit UnitType) bool {
+	for _, c := range r.Conflicts {
+		if c.Unit == unit {
+			return false
+		}
+	}
+	return true
+}
+
+//ConflictCells returns sorted ids of all cells which take part in some conflict.
+func (r *ValidationReport) ConflictCells() []string {
+	found := make(map[string]bool)
+	var result []string
+	for _, c := range r.Conflicts {
+		for _, id := range c.CellIds {
+			if !found[id] {
+				found[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,86 @@
+package structures
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidationReportHappyGame(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	report, err := g.ValidationReport()
+	if err != nil {
+		t.Errorf("Game validation report should be created, but err: %v", err)
+	}
+	if !report.IsValid() {
+		t.Errorf("Game validation report should be valid, but has conflicts: %v, empty: %v",
+			report.Conflicts, report.EmptyCandidates)
+	}
+}
+
+func TestValidationReportConflicts(t *testing.T) {
+	tests := []struct {
+		solutionCell string
+		expected     []Conflict
+	}{
+		{"f1=8 x", []Conflict{{Unit: UnitRow, Index: 1, Value: 8, CellIds: []string{"a1", "f1"}}}},
+		{"b2=6 x", []Conflict{{Unit: UnitColumn, Index: 2, Value: 6, CellIds: []string{"b2", "b5"}}}},
+		{"b2=8 x", []Conflict{{Unit: UnitSquare, Index: 1, Value: 8, CellIds: []string{"a1", "b2"}}}},
+	}
+	for _, test := range tests {
+		g, err := NewGameFromString(game1)
+		if err != nil {
+			t.Errorf("Game should be succesfully created, but err: %v", err)
+		}
+		err = g.AddStringCell(test.solutionCell)
+		if err != nil {
+			t.Errorf("Solution cell: %s should be added, but err: %v", test.solutionCell, err)
+		}
+		report, err := g.ValidationReport()
+		if err != nil {
+			t.Errorf("Game validation report should be created, but err: %v", err)
+		}
+		if !reflect.DeepEqual(report.Conflicts, test.expected) {
+			t.Errorf("Game conflicts are %v, but expected: %v", report.Conflicts, test.expected)
+		}
+		if report.UnitOk(test.expected[0].Unit) {
+			t.Errorf("Game unit %s should not be ok", test.expected[0].Unit)
+		}
+		if !reflect.DeepEqual(report.ConflictCells(), test.expected[0].CellIds) {
+			t.Errorf("Game conflict cells are %v, but expected: %v", report.ConflictCells(),
+				test.expected[0].CellIds)
+		}
+	}
+}
+
+func TestValidationReportEmptyCandidates(t *testing.T) {
+	g, err := NewGameFromString(game1)
+	if err != nil {
+		t.Errorf("Game should be succesfully created, but err: %v", err)
+	}
+	// b1 can have only 2, 3 or 7.
+	for _, textCell := range []string{"c1=2 x", "g1=3 x", "h1=7 x"} {
+		err = g.AddStringCell(textCell)
+		if err != nil {
+			t.Errorf("Solution cell: %s should be added, but err: %v", textCell, err)
+		}
+	}
+	report, err := g.ValidationReport()
+	if err != nil {
+		t.Errorf("Game validation report should be created, but err: %v", err)
+	}
+	found := false
+	for _, id := range report.EmptyCandidates {
+		if id == "b1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Cell b1 should be in empty candidates: %v", report.EmptyCandidates)
+	}
+	if report.IsValid() {
+		t.Error("Game validation report should not be valid.")
+	}
+}
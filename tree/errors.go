@@ -0,0 +1,23 @@
+package tree
+
+import (
+	"fmt"
+)
+
+//DuplicateNodeError is returned when node with the same ID already exists in tree.
+type DuplicateNodeError struct {
+	ID string
+}
+
+func (e *DuplicateNodeError) Error() string {
+	return fmt.Sprintf("node ID=%s already exists in tree", e.ID)
+}
+
+//NodeNotFoundError is returned when node with given ID was not found in tree.
+type NodeNotFoundError struct {
+	ID string
+}
+
+func (e *NodeNotFoundError) Error() string {
+	return fmt.Sprintf("node %s was not found", e.ID)
+}
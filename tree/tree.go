@@ -1,7 +1,6 @@
 package tree
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 
@@ -31,7 +30,7 @@ func NewTree(cap int) *Tree {
 func (t *Tree) AddNode(node *Node, parentID string) error {
 	isDuplicatedNode := t.ExistsNode(node.ID)
 	if isDuplicatedNode {
-		return fmt.Errorf("node ID=%s already exists in tree", node.ID)
+		return &DuplicateNodeError{ID: node.ID}
 	}
 	if parentID == "" {
 		t.nodes = append(t.nodes, node)
@@ -40,7 +39,7 @@ func (t *Tree) AddNode(node *Node, parentID string) error {
 	}
 	parent := t.FindNode(parentID)
 	if parent == nil {
-		return fmt.Errorf("parent node %s was not found", parentID)
+		return &NodeNotFoundError{ID: parentID}
 	}
 	parent.AddChild(node)
 	t.ids[node.ID] = true
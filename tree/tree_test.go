@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -112,3 +113,17 @@ func TestTreeShouldReturnSiblings(t *testing.T) {
 		t.Errorf("Siblings returns: %s\n, but expected: %s\n, diff: %s\n", ids, expected, diff)
 	}
 }
+
+func TestTreeAddNodeErrors(t *testing.T) {
+	treeObj := createTree()
+	err := treeObj.AddNode(CreateNode("a1"), "b")
+	var duplicateErr *DuplicateNodeError
+	if !errors.As(err, &duplicateErr) || duplicateErr.ID != "a1" {
+		t.Errorf("AddNode should return DuplicateNodeError for a1, but returned: %v", err)
+	}
+	err = treeObj.AddNode(CreateNode("x1"), "x")
+	var notFoundErr *NodeNotFoundError
+	if !errors.As(err, &notFoundErr) || notFoundErr.ID != "x" {
+		t.Errorf("AddNode should return NodeNotFoundError for x, but returned: %v", err)
+	}
+}